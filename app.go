@@ -19,6 +19,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/x/auth"
 	"github.com/cosmos/cosmos-sdk/x/auth/vesting"
 	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/cosmos-sdk/x/crisis"
 	distr "github.com/cosmos/cosmos-sdk/x/distribution"
 	"github.com/cosmos/cosmos-sdk/x/genutil"
 	"github.com/cosmos/cosmos-sdk/x/params"
@@ -27,6 +28,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/x/supply"
 
 	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice"
+	"github.com/cosmos/sdk-tutorials/nameservice/x/pricefeed"
 )
 
 const appName = "nameservice"
@@ -48,8 +50,10 @@ var (
 		params.AppModuleBasic{},
 		slashing.AppModuleBasic{},
 		supply.AppModuleBasic{},
+		crisis.AppModuleBasic{},
 
 		nameservice.AppModule{},
+		pricefeed.AppModule{},
 	)
 	// account permissions
 	maccPerms = map[string][]string{
@@ -57,6 +61,7 @@ var (
 		distr.ModuleName:          nil,
 		staking.BondedPoolName:    {supply.Burner, supply.Staking},
 		staking.NotBondedPoolName: {supply.Burner, supply.Staking},
+		nameservice.ModuleName:    {supply.Burner},
 	}
 )
 
@@ -83,15 +88,20 @@ type nameServiceApp struct {
 	// subspaces
 	subspaces map[string]params.Subspace
 
+	// number of blocks between two invariant checks, 0 to only check on demand
+	invCheckPeriod uint
+
 	// Keepers
-	accountKeeper  auth.AccountKeeper
-	bankKeeper     bank.Keeper
-	stakingKeeper  staking.Keeper
-	slashingKeeper slashing.Keeper
-	distrKeeper    distr.Keeper
-	supplyKeeper   supply.Keeper
-	paramsKeeper   params.Keeper
-	nsKeeper       nameservice.Keeper
+	accountKeeper   auth.AccountKeeper
+	bankKeeper      bank.Keeper
+	stakingKeeper   staking.Keeper
+	slashingKeeper  slashing.Keeper
+	distrKeeper     distr.Keeper
+	supplyKeeper    supply.Keeper
+	crisisKeeper    crisis.Keeper
+	paramsKeeper    params.Keeper
+	nsKeeper        nameservice.Keeper
+	priceFeedKeeper pricefeed.Keeper
 
 	// Module Manager
 	mm *module.Manager
@@ -105,7 +115,7 @@ var _ simapp.App = (*nameServiceApp)(nil)
 
 // NewNameServiceApp is a constructor function for nameServiceApp
 func NewNameServiceApp(
-	logger log.Logger, db dbm.DB, baseAppOptions ...func(*bam.BaseApp),
+	logger log.Logger, db dbm.DB, invCheckPeriod uint, baseAppOptions ...func(*bam.BaseApp),
 ) *nameServiceApp {
 
 	// First define the top level codec that will be shared by the different modules
@@ -117,7 +127,7 @@ func NewNameServiceApp(
 	bApp.SetAppVersion(version.Version)
 
 	keys := sdk.NewKVStoreKeys(bam.MainStoreKey, auth.StoreKey, staking.StoreKey,
-		supply.StoreKey, distr.StoreKey, slashing.StoreKey, params.StoreKey, nameservice.StoreKey)
+		supply.StoreKey, distr.StoreKey, slashing.StoreKey, params.StoreKey, nameservice.StoreKey, pricefeed.StoreKey)
 
 	tkeys := sdk.NewTransientStoreKeys(params.TStoreKey)
 
@@ -128,6 +138,8 @@ func NewNameServiceApp(
 		keys:      keys,
 		tkeys:     tkeys,
 		subspaces: make(map[string]params.Subspace),
+
+		invCheckPeriod: invCheckPeriod,
 	}
 
 	// The ParamsKeeper handles parameter storage for the application
@@ -138,6 +150,8 @@ func NewNameServiceApp(
 	app.subspaces[staking.ModuleName] = app.paramsKeeper.Subspace(staking.DefaultParamspace)
 	app.subspaces[distr.ModuleName] = app.paramsKeeper.Subspace(distr.DefaultParamspace)
 	app.subspaces[slashing.ModuleName] = app.paramsKeeper.Subspace(slashing.DefaultParamspace)
+	app.subspaces[crisis.ModuleName] = app.paramsKeeper.Subspace(crisis.DefaultParamspace)
+	app.subspaces[pricefeed.ModuleName] = app.paramsKeeper.Subspace(pricefeed.DefaultParamspace)
 
 	// The AccountKeeper handles address -> account lookups
 	app.accountKeeper = auth.NewAccountKeeper(
@@ -188,6 +202,14 @@ func NewNameServiceApp(
 		app.subspaces[slashing.ModuleName],
 	)
 
+	// The CrisisKeeper halts the chain when a registered invariant is broken
+	app.crisisKeeper = crisis.NewKeeper(
+		app.subspaces[crisis.ModuleName],
+		invCheckPeriod,
+		app.supplyKeeper,
+		auth.FeeCollectorName,
+	)
+
 	// register the staking hooks
 	// NOTE: stakingKeeper above is passed by reference, so that it will contain these hooks
 	app.stakingKeeper = *stakingKeeper.SetHooks(
@@ -196,45 +218,76 @@ func NewNameServiceApp(
 			app.slashingKeeper.Hooks()),
 	)
 
+	// The PriceFeedKeeper stores the exchange rates posted by the authorised feeders
+	app.priceFeedKeeper = pricefeed.NewKeeper(
+		app.cdc,
+		keys[pricefeed.StoreKey],
+		app.subspaces[pricefeed.ModuleName],
+	)
+
 	// The NameserviceKeeper is the Keeper from the module for this tutorial
 	// It handles interactions with the namestore
 	app.nsKeeper = nameservice.NewKeeper(
 		app.cdc,
 		keys[nameservice.StoreKey],
 		app.bankKeeper,
+		app.supplyKeeper,
+		app.priceFeedKeeper,
 	)
 
 	app.mm = module.NewManager(
 		genutil.NewAppModule(app.accountKeeper, app.stakingKeeper, app.BaseApp.DeliverTx),
 		auth.NewAppModule(app.accountKeeper),
 		bank.NewAppModule(app.bankKeeper, app.accountKeeper),
-		nameservice.NewAppModule(app.nsKeeper, app.bankKeeper),
+		nameservice.NewAppModule(app.nsKeeper, app.bankKeeper, app.accountKeeper),
+		pricefeed.NewAppModule(app.priceFeedKeeper),
 		supply.NewAppModule(app.supplyKeeper, app.accountKeeper),
+		crisis.NewAppModule(&app.crisisKeeper),
 		distr.NewAppModule(app.distrKeeper, app.accountKeeper, app.supplyKeeper, app.stakingKeeper),
 		slashing.NewAppModule(app.slashingKeeper, app.accountKeeper, app.stakingKeeper),
 		staking.NewAppModule(app.stakingKeeper, app.accountKeeper, app.supplyKeeper),
 	)
 
-	app.mm.SetOrderBeginBlockers(distr.ModuleName, slashing.ModuleName)
-	app.mm.SetOrderEndBlockers(staking.ModuleName)
+	app.mm.SetOrderBeginBlockers(distr.ModuleName, slashing.ModuleName, nameservice.ModuleName)
+	app.mm.SetOrderEndBlockers(crisis.ModuleName, staking.ModuleName, nameservice.ModuleName)
 
 	// Sets the order of Genesis - Order matters, genutil is to always come last
 	// NOTE: The genutils moodule must occur after staking so that pools are
 	// properly initialized with tokens from genesis accounts.
+	// NOTE: auth must come first so that importing an exported genesis keeps
+	// the account numbers of the accounts created by the other modules.
 	app.mm.SetOrderInitGenesis(
+		auth.ModuleName,
 		distr.ModuleName,
 		staking.ModuleName,
-		auth.ModuleName,
 		bank.ModuleName,
 		slashing.ModuleName,
+		pricefeed.ModuleName,
 		nameservice.ModuleName,
 		supply.ModuleName,
+		crisis.ModuleName,
 		genutil.ModuleName,
 	)
 
+	app.mm.RegisterInvariants(&app.crisisKeeper)
+
 	// register all module routes and module queriers
 	app.mm.RegisterRoutes(app.Router(), app.QueryRouter())
 
+	// create the simulation manager and define the order of the modules for deterministic simulations
+	app.sm = module.NewSimulationManager(
+		auth.NewAppModule(app.accountKeeper),
+		bank.NewAppModule(app.bankKeeper, app.accountKeeper),
+		supply.NewAppModule(app.supplyKeeper, app.accountKeeper),
+		staking.NewAppModule(app.stakingKeeper, app.accountKeeper, app.supplyKeeper),
+		distr.NewAppModule(app.distrKeeper, app.accountKeeper, app.supplyKeeper, app.stakingKeeper),
+		slashing.NewAppModule(app.slashingKeeper, app.accountKeeper, app.stakingKeeper),
+		nameservice.NewAppModule(app.nsKeeper, app.bankKeeper, app.accountKeeper),
+		pricefeed.NewAppModule(app.priceFeedKeeper),
+	)
+
+	app.sm.RegisterStoreDecoders()
+
 	// The initChainer handles translating the genesis.json file into initial state for the network
 	app.SetInitChainer(app.InitChainer)
 	app.SetBeginBlocker(app.BeginBlocker)
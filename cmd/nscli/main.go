@@ -17,6 +17,10 @@ import (
 	"github.com/cosmos/cosmos-sdk/x/bank"
 	bankcmd "github.com/cosmos/cosmos-sdk/x/bank/client/cli"
 	app "github.com/cosmos/sdk-tutorials/nameservice"
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice"
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/client/dns"
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/client/graphql"
+	nsrest "github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/client/rest"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	amino "github.com/tendermint/go-amino"
@@ -53,7 +57,8 @@ func main() {
 		queryCmd(cdc),
 		txCmd(cdc),
 		flags.LineBreak,
-		lcd.ServeCommand(cdc, registerRoutes),
+		restServerCmd(cdc),
+		dns.ServeCommand(cdc),
 		flags.LineBreak,
 		keys.Commands(),
 		flags.LineBreak,
@@ -69,10 +74,38 @@ func main() {
 	}
 }
 
-func registerRoutes(rs *lcd.RestServer) {
+const flagGraphQL = "graphql"
+
+// restServerCmd starts the REST server, which looks keys up and signs transactions
+// with the keyring selected by --keyring-backend, and serves GraphQL with --graphql.
+// The keyring is opened before the server starts, asking for the passphrase of the
+// file backend.
+func restServerCmd(cdc *amino.Codec) *cobra.Command {
+	var keyring *nsrest.Keyring
+	cmd := lcd.ServeCommand(cdc, func(rs *lcd.RestServer) {
+		registerRoutes(rs, keyring)
+	})
+	cmd.PreRunE = func(_ *cobra.Command, _ []string) (err error) {
+		keyring, err = nsrest.OpenKeyring(viper.GetString(flags.FlagKeyringBackend), viper.GetString(flags.FlagHome),
+			os.Stdin, viper.GetBool(nsrest.FlagUnsafeKeyringSigning))
+		return err
+	}
+	cmd.Flags().String(flags.FlagKeyringBackend, flags.DefaultKeyringBackend,
+		"Select the keyring's backend (os|file|test) holding the keys the server looks up and signs with")
+	cmd.Flags().Bool(nsrest.FlagUnsafeKeyringSigning, false,
+		"Sign with the os and test keyring backends, which let anyone reaching the server sign with their keys")
+	cmd.Flags().Bool(flagGraphQL, false, "Serve the names, products and accounts over GraphQL at /nameservice/graphql")
+	return cmd
+}
+
+func registerRoutes(rs *lcd.RestServer, keyring *nsrest.Keyring) {
 	client.RegisterRoutes(rs.CliCtx, rs.Mux)
 	authrest.RegisterTxRoutes(rs.CliCtx, rs.Mux)
 	app.ModuleBasics.RegisterRESTRoutes(rs.CliCtx, rs.Mux)
+	nsrest.RegisterKeyringRoutes(rs.CliCtx, rs.Mux, nameservice.StoreKey, keyring)
+	if viper.GetBool(flagGraphQL) {
+		graphql.RegisterRoutes(rs.CliCtx, rs.Mux, nameservice.StoreKey)
+	}
 }
 
 func queryCmd(cdc *amino.Codec) *cobra.Command {
@@ -5,10 +5,16 @@ go 1.13
 require (
 	github.com/cosmos/cosmos-sdk v0.38.4
 	github.com/gorilla/mux v1.7.4
+	github.com/gorilla/websocket v1.4.1
+	github.com/graph-gophers/graphql-go v0.0.0-20190724201507-010347b5f9e6
+	github.com/opentracing/opentracing-go v1.2.0 // indirect
+	github.com/rakyll/statik v0.1.6
 	github.com/spf13/cobra v0.0.7
 	github.com/spf13/viper v1.6.3
 	github.com/stretchr/testify v1.5.1
+	github.com/tendermint/crypto v0.0.0-20191022145703-50d29ede1e15
 	github.com/tendermint/go-amino v0.15.1
 	github.com/tendermint/tendermint v0.33.3
 	github.com/tendermint/tm-db v0.5.1
+	golang.org/x/net v0.0.0-20190813141303-74dc4d7220e7
 )
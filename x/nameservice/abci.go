@@ -0,0 +1,155 @@
+package nameservice
+
+import (
+	"strconv"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
+
+// BeginBlocker migrates a store written by an older version of the module to the
+// current layout at the first block the upgraded module processes
+func BeginBlocker(ctx sdk.Context, keeper Keeper) {
+	keeper.MigrateStore(ctx)
+}
+
+// EndBlocker settles every auction that finished at the current height and
+// renews or closes the subscriptions whose period ran out
+func EndBlocker(ctx sdk.Context, keeper Keeper) {
+	var finished []Auction
+
+	iterator := keeper.GetAuctionsIterator(ctx)
+	for ; iterator.Valid(); iterator.Next() {
+		productID := strings.TrimPrefix(string(iterator.Key()), types.AuctionPrefix)
+		auction := keeper.GetAuction(ctx, productID)
+		if auction.IsFinished(ctx.BlockHeight()) {
+			finished = append(finished, auction)
+		}
+	}
+	iterator.Close()
+
+	for _, auction := range finished {
+		settleAuction(ctx, keeper, auction)
+	}
+
+	for _, subscription := range keeper.GetDueSubscriptions(ctx, ctx.BlockHeight()) {
+		renewSubscription(ctx, keeper, subscription)
+	}
+}
+
+// settleAuction pays the seller from escrow and hands the product over to the
+// winning bidder. Auctions without bids are closed and the seller keeps the product.
+// When the escrow cannot pay the seller, the winning bid is refunded to the bidder and
+// the auction closed, rather than halting the chain; when the refund fails too, the
+// auction is kept and settled again at the next block.
+func settleAuction(ctx sdk.Context, keeper Keeper, auction Auction) {
+	key := types.ProductPrefix + auction.ProductID
+
+	if winner, ok := auction.HighestBid(); ok {
+		// Run the transfers in a cached context so a failed one leaves no partial state
+		cacheCtx, write := ctx.CacheContext()
+		err := keeper.SupplyKeeper.SendCoinsFromModuleToAccount(cacheCtx, types.ModuleName, auction.Seller, winner.Amount)
+		if err != nil {
+			keeper.Logger(ctx).Error("failed to pay the seller of an auction", "product_id", auction.ProductID,
+				"seller", auction.Seller.String(), "bidder", winner.Bidder.String(), "amount", winner.Amount.String(), "err", err)
+
+			cacheCtx, write = ctx.CacheContext()
+			err = keeper.SupplyKeeper.SendCoinsFromModuleToAccount(cacheCtx, types.ModuleName, winner.Bidder, winner.Amount)
+			if err != nil {
+				keeper.Logger(ctx).Error("failed to refund the winner of an auction", "product_id", auction.ProductID,
+					"bidder", winner.Bidder.String(), "amount", winner.Amount.String(), "err", err)
+				return
+			}
+			write()
+
+			ctx.EventManager().EmitEvent(
+				sdk.NewEvent(
+					types.EventTypeCloseAuction,
+					sdk.NewAttribute(sdk.AttributeKeyModule, types.AttributeValueCategory),
+					sdk.NewAttribute(types.AttributeKeyProductID, auction.ProductID),
+					sdk.NewAttribute(types.AttributeKeySeller, auction.Seller.String()),
+					sdk.NewAttribute(types.AttributeKeyBidder, winner.Bidder.String()),
+					sdk.NewAttribute(sdk.AttributeKeyAmount, winner.Amount.String()),
+					sdk.NewAttribute(types.AttributeKeyReason, types.AttributeValuePayoutFailed),
+				),
+			)
+			keeper.DeleteAuction(ctx, auction.ProductID)
+			return
+		}
+		write()
+
+		keeper.SetPurchase(ctx, types.Purchase{
+			ProductID: auction.ProductID,
+			Buyer:     winner.Bidder,
+			Seller:    auction.Seller,
+			Price:     winner.Amount,
+			Height:    ctx.BlockHeight(),
+		})
+
+		product := keeper.GetProduct(ctx, key)
+		product.Owner = winner.Bidder
+		product.Price = winner.Amount
+		product.Listed = false
+		product.Storefront = ""
+		keeper.SetProduct(ctx, key, product)
+	}
+
+	keeper.DeleteAuction(ctx, auction.ProductID)
+}
+
+// renewSubscription charges the subscriber for another period, paid to the current
+// owner of the product. Subscriptions that were cancelled, whose product is no
+// longer sold by subscription or whose subscriber cannot pay are closed.
+func renewSubscription(ctx sdk.Context, keeper Keeper, subscription Subscription) {
+	if subscription.Cancelled {
+		keeper.DeleteSubscription(ctx, subscription.ProductID, subscription.Subscriber)
+		return
+	}
+
+	key := types.ProductPrefix + subscription.ProductID
+
+	if !keeper.IsProductPresent(ctx, key) || !keeper.GetProduct(ctx, key).IsSubscription() {
+		closeSubscription(ctx, keeper, subscription, types.AttributeValueProductUnavailable)
+		return
+	}
+
+	product := keeper.GetProduct(ctx, key)
+
+	// Run the payment in a cached context so a failed transfer leaves no partial state
+	cacheCtx, write := ctx.CacheContext()
+	err := keeper.CoinKeeper.SendCoins(cacheCtx, subscription.Subscriber, product.Owner, subscription.Price)
+	if err != nil {
+		closeSubscription(ctx, keeper, subscription, types.AttributeValueInsufficientFunds)
+		return
+	}
+	write()
+
+	subscription.PaidUntil += subscription.Period
+	keeper.SetSubscription(ctx, subscription)
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeRenewSubscription,
+			sdk.NewAttribute(sdk.AttributeKeyModule, types.AttributeValueCategory),
+			sdk.NewAttribute(types.AttributeKeyProductID, subscription.ProductID),
+			sdk.NewAttribute(types.AttributeKeySubscriber, subscription.Subscriber.String()),
+			sdk.NewAttribute(types.AttributeKeyPaidUntil, strconv.FormatInt(subscription.PaidUntil, 10)),
+		),
+	)
+}
+
+func closeSubscription(ctx sdk.Context, keeper Keeper, subscription Subscription, reason string) {
+	keeper.DeleteSubscription(ctx, subscription.ProductID, subscription.Subscriber)
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeCancelSubscription,
+			sdk.NewAttribute(sdk.AttributeKeyModule, types.AttributeValueCategory),
+			sdk.NewAttribute(types.AttributeKeyProductID, subscription.ProductID),
+			sdk.NewAttribute(types.AttributeKeySubscriber, subscription.Subscriber.String()),
+			sdk.NewAttribute(types.AttributeKeyReason, reason),
+		),
+	)
+}
@@ -0,0 +1,126 @@
+package nameservice
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/keeper"
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
+
+func TestEndBlockerSettlesAuction(t *testing.T) {
+	input, handler := setupHandlerTest(t)
+	ctx, k := input.Ctx, input.Keeper
+
+	_, err := handler(ctx, NewMsgPlaceBid("rare1", price, buyer))
+	require.NoError(t, err)
+
+	// the auction runs until its end height
+	EndBlocker(ctx, k)
+	require.True(t, k.IsAuctionPresent(ctx, "rare1"))
+
+	escrow := input.SupplyKeeper.GetModuleAddress(types.ModuleName)
+	require.Equal(t, price, input.BankKeeper.GetCoins(ctx, escrow))
+
+	ctx = ctx.WithBlockHeight(k.GetAuction(ctx, "rare1").EndHeight)
+	EndBlocker(ctx, k)
+	require.False(t, k.IsAuctionPresent(ctx, "rare1"))
+	require.Equal(t, buyer, k.GetProduct(ctx, "Product-rare1").Owner)
+	require.True(t, input.BankKeeper.GetCoins(ctx, escrow).IsZero())
+}
+
+// failingSupplyKeeper fails the transfers from module accounts to an address
+type failingSupplyKeeper struct {
+	types.SupplyKeeper
+	recipient sdk.AccAddress
+}
+
+func (k failingSupplyKeeper) SendCoinsFromModuleToAccount(ctx sdk.Context, senderModule string,
+	recipientAddr sdk.AccAddress, amt sdk.Coins) error {
+	if recipientAddr.Equals(k.recipient) {
+		return sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "transfer refused")
+	}
+	return k.SupplyKeeper.SendCoinsFromModuleToAccount(ctx, senderModule, recipientAddr, amt)
+}
+
+func TestEndBlockerRefundsBidderWhenPayoutFails(t *testing.T) {
+	input, handler := setupHandlerTest(t)
+	ctx, k := input.Ctx, input.Keeper
+
+	coins := input.BankKeeper.GetCoins(ctx, buyer)
+	_, err := handler(ctx, NewMsgPlaceBid("rare1", price, buyer))
+	require.NoError(t, err)
+
+	// paying the seller fails, the winning bid goes back to the bidder
+	k.SupplyKeeper = failingSupplyKeeper{SupplyKeeper: k.SupplyKeeper, recipient: owner}
+	ctx = ctx.WithBlockHeight(k.GetAuction(ctx, "rare1").EndHeight)
+	require.NotPanics(t, func() { EndBlocker(ctx, k) })
+
+	require.False(t, k.IsAuctionPresent(ctx, "rare1"))
+	require.Equal(t, owner, k.GetProduct(ctx, "Product-rare1").Owner)
+	require.Equal(t, coins, input.BankKeeper.GetCoins(ctx, buyer))
+
+	res, broken := keeper.AuctionEscrowInvariant(k)(ctx)
+	require.False(t, broken, res)
+
+	var closed bool
+	for _, event := range ctx.EventManager().Events() {
+		if event.Type == types.EventTypeCloseAuction {
+			closed = true
+		}
+	}
+	require.True(t, closed)
+}
+
+func TestEndBlockerRetriesAuctionWhoseEscrowCannotPay(t *testing.T) {
+	input, handler := setupHandlerTest(t)
+	ctx, k := input.Ctx, input.Keeper
+
+	_, err := handler(ctx, NewMsgPlaceBid("rare1", price, buyer))
+	require.NoError(t, err)
+
+	// the escrow was drained, neither paying the seller nor refunding the bidder works
+	err = input.SupplyKeeper.SendCoinsFromModuleToAccount(ctx, types.ModuleName, stranger, price)
+	require.NoError(t, err)
+
+	ctx = ctx.WithBlockHeight(k.GetAuction(ctx, "rare1").EndHeight)
+	require.NotPanics(t, func() { EndBlocker(ctx, k) })
+	require.True(t, k.IsAuctionPresent(ctx, "rare1"))
+	require.Equal(t, owner, k.GetProduct(ctx, "Product-rare1").Owner)
+
+	// the auction is settled at the next block once the escrow holds the bid again
+	err = input.SupplyKeeper.SendCoinsFromAccountToModule(ctx, stranger, types.ModuleName, price)
+	require.NoError(t, err)
+
+	ctx = ctx.WithBlockHeight(ctx.BlockHeight() + 1)
+	EndBlocker(ctx, k)
+	require.False(t, k.IsAuctionPresent(ctx, "rare1"))
+	require.Equal(t, buyer, k.GetProduct(ctx, "Product-rare1").Owner)
+}
+
+func TestEndBlockerSkipsSubscriptionsOfDeletedProduct(t *testing.T) {
+	input, handler := setupHandlerTest(t)
+	ctx, k := input.Ctx, input.Keeper
+
+	coins := input.BankKeeper.GetCoins(ctx, stranger)
+
+	// the product is deleted and recreated under the same ID by another owner
+	_, err := handler(ctx, NewMsgDeleteProduct("magazine1", owner))
+	require.NoError(t, err)
+	require.Empty(t, k.GetSubscriptions(ctx, "magazine1"))
+
+	_, err = handler(ctx, NewMsgCreateProduct("magazine1", "a magazine", price, "", nil, types.Content{}, buyer))
+	require.NoError(t, err)
+	_, err = handler(ctx, NewMsgSetProductSubscription("magazine1", 10, buyer))
+	require.NoError(t, err)
+
+	// the subscribers of the deleted product are not charged for the new one
+	ctx = ctx.WithBlockHeight(20)
+	EndBlocker(ctx, k)
+	require.Equal(t, coins, input.BankKeeper.GetCoins(ctx, stranger))
+	require.Empty(t, k.GetDueSubscriptions(ctx, 20))
+}
@@ -22,11 +22,53 @@ var (
 	ModuleCdc        = types.ModuleCdc
 	RegisterCodec    = types.RegisterCodec
 
+	RegisterInvariants = keeper.RegisterInvariants
+	AllInvariants      = keeper.AllInvariants
+
+	NewGenesisState     = types.NewGenesisState
+	DefaultGenesisState = types.DefaultGenesisState
+	ValidateGenesis     = types.ValidateGenesis
+
 	NewProduct          = types.NewProduct
 	NewMsgCreateProduct = types.NewMsgCreateProduct
 	NewMsgUpdateProduct = types.NewMsgUpdateProduct
 	NewMsgDeleteProduct = types.NewMsgDeleteProduct
 	NewMsgBuyProduct    = types.NewMsgBuyProduct
+	NewMsgListProduct   = types.NewMsgListProduct
+	NewMsgDelistProduct = types.NewMsgDelistProduct
+
+	NewQueryProductsParams = types.NewQueryProductsParams
+	NewContent             = types.NewContent
+
+	NewAuction          = types.NewAuction
+	NewMsgCreateAuction = types.NewMsgCreateAuction
+	NewMsgPlaceBid      = types.NewMsgPlaceBid
+
+	NewMsgPublishProduct    = types.NewMsgPublishProduct
+	NewMsgUnpublishProduct  = types.NewMsgUnpublishProduct
+	NewMsgSetStorefrontSale = types.NewMsgSetStorefrontSale
+
+	NewMsgReviewProduct = types.NewMsgReviewProduct
+
+	NewMsgSetProductPricing = types.NewMsgSetProductPricing
+
+	NewMsgCreateCoupon   = types.NewMsgCreateCoupon
+	NewMsgRevokeCoupon   = types.NewMsgRevokeCoupon
+	NewMsgCommitCoupon   = types.NewMsgCommitCoupon
+	HashCouponCode       = types.HashCouponCode
+	HashCouponCommitment = types.HashCouponCommitment
+
+	NewSubscription              = types.NewSubscription
+	NewMsgSetProductSubscription = types.NewMsgSetProductSubscription
+	NewMsgCancelSubscription     = types.NewMsgCancelSubscription
+
+	NewCartItem       = types.NewCartItem
+	NewMsgBuyProducts = types.NewMsgBuyProducts
+
+	NewProductHistoryEntry = types.NewProductHistoryEntry
+
+	NewLicense                = types.NewLicense
+	NewMsgSetProductLicensing = types.NewMsgSetProductLicensing
 )
 
 type (
@@ -43,5 +85,52 @@ type (
 	MsgUpdateProduct    = types.MsgUpdateProduct
 	MsgDeleteProduct    = types.MsgDeleteProduct
 	MsgBuyProduct       = types.MsgBuyProduct
+	MsgListProduct      = types.MsgListProduct
+	MsgDelistProduct    = types.MsgDelistProduct
 	QueryResAllProducts = types.QueryResAllProducts
+	QueryProductsParams = types.QueryProductsParams
+	Content             = types.Content
+
+	Auction          = types.Auction
+	Bid              = types.Bid
+	MsgCreateAuction = types.MsgCreateAuction
+	MsgPlaceBid      = types.MsgPlaceBid
+	QueryResAuctions = types.QueryResAuctions
+	QueryResBids     = types.QueryResBids
+
+	MsgPublishProduct    = types.MsgPublishProduct
+	MsgUnpublishProduct  = types.MsgUnpublishProduct
+	MsgSetStorefrontSale = types.MsgSetStorefrontSale
+
+	Purchase         = types.Purchase
+	Review           = types.Review
+	Rating           = types.Rating
+	MsgReviewProduct = types.MsgReviewProduct
+	QueryResReviews  = types.QueryResReviews
+
+	MsgSetProductPricing = types.MsgSetProductPricing
+
+	Coupon           = types.Coupon
+	CouponCommitment = types.CouponCommitment
+	MsgCreateCoupon  = types.MsgCreateCoupon
+	MsgRevokeCoupon  = types.MsgRevokeCoupon
+	MsgCommitCoupon  = types.MsgCommitCoupon
+
+	Subscription              = types.Subscription
+	MsgSetProductSubscription = types.MsgSetProductSubscription
+	MsgCancelSubscription     = types.MsgCancelSubscription
+	QueryResSubscriptions     = types.QueryResSubscriptions
+
+	CartItem       = types.CartItem
+	MsgBuyProducts = types.MsgBuyProducts
+
+	ProductHistoryEntry    = types.ProductHistoryEntry
+	QueryResProductHistory = types.QueryResProductHistory
+
+	License                = types.License
+	MsgSetProductLicensing = types.MsgSetProductLicensing
+	QueryResHasLicense     = types.QueryResHasLicense
+
+	GenesisState = types.GenesisState
+	WhoisRecord  = types.WhoisRecord
 )
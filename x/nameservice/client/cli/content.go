@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
+
+const (
+	flagContentFile = "content-file"
+	flagContentHash = "content-hash"
+	flagContentURI  = "content-uri"
+	flagContentMime = "content-mime"
+	flagContentSize = "content-size"
+)
+
+// addContentFlags registers the flags describing the off-chain content of a product
+func addContentFlags(cmd *cobra.Command) {
+	cmd.Flags().String(flagContentFile, "", "local file to hash and describe as the product content")
+	cmd.Flags().String(flagContentHash, "", "hex SHA-256 digest or CID of the product content")
+	cmd.Flags().String(flagContentURI, "", "URI the product content can be fetched from")
+	cmd.Flags().String(flagContentMime, "", "MIME type of the product content")
+	cmd.Flags().Uint64(flagContentSize, 0, "size in bytes of the product content")
+}
+
+// contentFromFlags builds the product content metadata. When a content file is given its
+// digest, size and MIME type are filled in, explicitly set flags take precedence.
+func contentFromFlags() (types.Content, error) {
+	var content types.Content
+
+	if path := viper.GetString(flagContentFile); path != "" {
+		fileContent, err := HashContentFile(path)
+		if err != nil {
+			return types.Content{}, err
+		}
+		content = fileContent
+	}
+
+	if hash := viper.GetString(flagContentHash); hash != "" {
+		content.Hash = hash
+	}
+	if uri := viper.GetString(flagContentURI); uri != "" {
+		content.URI = uri
+	}
+	if mimeType := viper.GetString(flagContentMime); mimeType != "" {
+		content.MimeType = mimeType
+	}
+	if size := viper.GetUint64(flagContentSize); size != 0 {
+		content.Size = size
+	}
+
+	return content, nil
+}
+
+// HashContentFile returns the content metadata of a local file: its SHA-256 digest, size
+// and MIME type, guessed from the extension or else sniffed from the first bytes.
+func HashContentFile(path string) (types.Content, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return types.Content{}, err
+	}
+	defer file.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return types.Content{}, err
+	}
+	head = head[:n]
+
+	hasher := sha256.New()
+	hasher.Write(head)
+	rest, err := io.Copy(hasher, file)
+	if err != nil {
+		return types.Content{}, err
+	}
+
+	mimeType := mime.TypeByExtension(filepath.Ext(path))
+	if mimeType == "" {
+		mimeType = http.DetectContentType(head)
+	}
+
+	return types.NewContent(hex.EncodeToString(hasher.Sum(nil)), "", mimeType, uint64(n)+uint64(rest)), nil
+}
@@ -7,8 +7,18 @@ import (
 	"github.com/cosmos/cosmos-sdk/client/context"
 	"github.com/cosmos/cosmos-sdk/client/flags"
 	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	flagListed   = "listed"
+	flagTag      = "tag"
+	flagOwner    = "owner"
+	flagMinPrice = "min-price"
+	flagMaxPrice = "max-price"
 )
 
 func GetQueryCmd(storeKey string, cdc *codec.Codec) *cobra.Command {
@@ -26,6 +36,27 @@ func GetQueryCmd(storeKey string, cdc *codec.Codec) *cobra.Command {
 
 		GetCmdProduct(storeKey, cdc),
 		GetCmdAllProducts(storeKey, cdc),
+
+		GetCmdAuction(storeKey, cdc),
+		GetCmdAuctions(storeKey, cdc),
+		GetCmdBids(storeKey, cdc),
+
+		GetCmdStorefront(storeKey, cdc),
+		GetCmdResolveProduct(storeKey, cdc),
+		GetCmdVerifyContent(storeKey, cdc),
+
+		GetCmdReviews(storeKey, cdc),
+		GetCmdRating(storeKey, cdc),
+		GetCmdReputation(storeKey, cdc),
+
+		GetCmdCoupon(storeKey, cdc),
+
+		GetCmdSubscription(storeKey, cdc),
+		GetCmdSubscriptions(storeKey, cdc),
+
+		GetCmdProductHistory(storeKey, cdc),
+
+		GetCmdHasLicense(storeKey, cdc),
 	)...)
 
 	return nameserviceQueryCmd
@@ -64,6 +95,19 @@ func GetCmdWhois(queryRoute string, cdc *codec.Codec) *cobra.Command {
 			cliCtx := context.NewCLIContext().WithCodec(cdc)
 			name := args[0]
 
+			if !cliCtx.TrustNode {
+				res, err := queryVerified(cliCtx, queryRoute, types.WhoisKey(name))
+				if err != nil {
+					return err
+				}
+
+				out := types.NewWhois()
+				if len(res) > 0 {
+					cdc.MustUnmarshalBinaryBare(res, &out)
+				}
+				return cliCtx.PrintOutput(out)
+			}
+
 			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/whois/%s", queryRoute, name), nil)
 			if err != nil {
 				fmt.Printf("could not resolve whois - %s \n", name)
@@ -108,6 +152,19 @@ func GetCmdProduct(queryRoute string, cdc *codec.Codec) *cobra.Command {
 			cliCtx := context.NewCLIContext().WithCodec(cdc)
 			productID := args[0]
 
+			if !cliCtx.TrustNode {
+				res, err := queryVerified(cliCtx, queryRoute, []byte(types.ProductPrefix+productID))
+				if err != nil {
+					return err
+				}
+
+				out := types.NewProduct()
+				if len(res) > 0 {
+					cdc.MustUnmarshalBinaryBare(res, &out)
+				}
+				return cliCtx.PrintOutput(out)
+			}
+
 			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/product/%s", queryRoute, productID), nil)
 			if err != nil {
 				fmt.Printf("could not resolve whois - %s \n", productID)
@@ -122,13 +179,40 @@ func GetCmdProduct(queryRoute string, cdc *codec.Codec) *cobra.Command {
 }
 
 func GetCmdAllProducts(queryRoute string, cdc *codec.Codec) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "all-products",
 		Short: "all-products",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cliCtx := context.NewCLIContext().WithCodec(cdc)
 
-			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/allProducts", queryRoute), nil)
+			var owner sdk.AccAddress
+			if ownerStr := viper.GetString(flagOwner); ownerStr != "" {
+				addr, err := sdk.AccAddressFromBech32(ownerStr)
+				if err != nil {
+					return err
+				}
+				owner = addr
+			}
+
+			minPrice, err := sdk.ParseCoins(viper.GetString(flagMinPrice))
+			if err != nil {
+				return err
+			}
+
+			maxPrice, err := sdk.ParseCoins(viper.GetString(flagMaxPrice))
+			if err != nil {
+				return err
+			}
+
+			params := types.NewQueryProductsParams(viper.GetString(flagCategory), viper.GetString(flagTag), owner,
+				minPrice, maxPrice, viper.GetBool(flagListed))
+
+			bz, err := cdc.MarshalJSON(params)
+			if err != nil {
+				return err
+			}
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/products", queryRoute), bz)
 			if err != nil {
 				fmt.Printf("could not get all products\n")
 				return nil
@@ -139,4 +223,363 @@ func GetCmdAllProducts(queryRoute string, cdc *codec.Codec) *cobra.Command {
 			return cliCtx.PrintOutput(out)
 		},
 	}
+
+	cmd.Flags().Bool(flagListed, false, "only return products that are for sale")
+	cmd.Flags().String(flagCategory, "", "only return products of the category")
+	cmd.Flags().String(flagTag, "", "only return products labelled with the tag")
+	cmd.Flags().String(flagOwner, "", "only return products owned by the address")
+	cmd.Flags().String(flagMinPrice, "", "only return products costing at least the amount")
+	cmd.Flags().String(flagMaxPrice, "", "only return products costing at most the amount")
+
+	return cmd
+}
+
+// GetCmdAuction queries an auction of a product
+func GetCmdAuction(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "auction [productID]",
+		Short: "Query the auction of a product",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			productID := args[0]
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/auction/%s", queryRoute, productID), nil)
+			if err != nil {
+				fmt.Printf("could not get auction - %s \n", productID)
+				return nil
+			}
+
+			var out types.Auction
+			cdc.MustUnmarshalJSON(res, &out)
+			return cliCtx.PrintOutput(out)
+		},
+	}
+}
+
+// GetCmdAuctions queries a list of all active auctions
+func GetCmdAuctions(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "auctions",
+		Short: "Query all active auctions",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/auctions", queryRoute), nil)
+			if err != nil {
+				fmt.Printf("could not get auctions\n")
+				return nil
+			}
+
+			var out types.QueryResAuctions
+			cdc.MustUnmarshalJSON(res, &out)
+			return cliCtx.PrintOutput(out)
+		},
+	}
+}
+
+// GetCmdBids queries the bids placed on an auction
+func GetCmdBids(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "bids [productID]",
+		Short: "Query the bids placed on the auction of a product",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			productID := args[0]
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/bids/%s", queryRoute, productID), nil)
+			if err != nil {
+				fmt.Printf("could not get bids - %s \n", productID)
+				return nil
+			}
+
+			var out types.QueryResBids
+			cdc.MustUnmarshalJSON(res, &out)
+			return cliCtx.PrintOutput(out)
+		},
+	}
+}
+
+// GetCmdStorefront queries the products published under a name
+func GetCmdStorefront(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "storefront [name]",
+		Short: "Query the products published under a name",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			name := args[0]
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/storefront/%s", queryRoute, name), nil)
+			if err != nil {
+				fmt.Printf("could not get storefront - %s \n", name)
+				return nil
+			}
+
+			var out types.QueryResAllProducts
+			cdc.MustUnmarshalJSON(res, &out)
+			return cliCtx.PrintOutput(out)
+		},
+	}
+}
+
+// GetCmdResolveProduct resolves a name/productID address to a product
+func GetCmdResolveProduct(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "resolve-product [name/productID]",
+		Short: "resolve a product published in the storefront of a name",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			address := args[0]
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/resolveProduct/%s", queryRoute, address), nil)
+			if err != nil {
+				fmt.Printf("could not resolve product - %s \n", address)
+				return nil
+			}
+
+			var out types.Product
+			cdc.MustUnmarshalJSON(res, &out)
+			return cliCtx.PrintOutput(out)
+		},
+	}
+}
+
+// GetCmdVerifyContent checks a delivered file against the content hash of a product
+func GetCmdVerifyContent(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "verify-content [productID] [file]",
+		Short: "verify a delivered file matches the content registered for a product",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			productID := args[0]
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/product/%s", queryRoute, productID), nil)
+			if err != nil {
+				return err
+			}
+
+			var product types.Product
+			cdc.MustUnmarshalJSON(res, &product)
+
+			if !product.Content.IsSHA256() {
+				return fmt.Errorf("product %s has no SHA-256 content hash to verify against", productID)
+			}
+
+			content, err := HashContentFile(args[1])
+			if err != nil {
+				return err
+			}
+
+			if content.Hash != product.Content.Hash {
+				return fmt.Errorf("content hash mismatch: expected %s, got %s", product.Content.Hash, content.Hash)
+			}
+
+			fmt.Printf("content of %s matches product %s\n", args[1], productID)
+			return nil
+		},
+	}
+}
+
+// GetCmdReviews queries the reviews of a product
+func GetCmdReviews(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "reviews [productID]",
+		Short: "Query the reviews of a product",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			productID := args[0]
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/reviews/%s", queryRoute, productID), nil)
+			if err != nil {
+				fmt.Printf("could not get reviews - %s \n", productID)
+				return nil
+			}
+
+			var out types.QueryResReviews
+			cdc.MustUnmarshalJSON(res, &out)
+			return cliCtx.PrintOutput(out)
+		},
+	}
+}
+
+// GetCmdRating queries the aggregated rating of a product
+func GetCmdRating(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rating [productID]",
+		Short: "Query the rating of a product",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			productID := args[0]
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/rating/%s", queryRoute, productID), nil)
+			if err != nil {
+				fmt.Printf("could not get rating - %s \n", productID)
+				return nil
+			}
+
+			var out types.QueryResRating
+			cdc.MustUnmarshalJSON(res, &out)
+			return cliCtx.PrintOutput(out)
+		},
+	}
+}
+
+// GetCmdReputation queries the aggregated rating of a seller
+func GetCmdReputation(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "reputation [address]",
+		Short: "Query the reputation of a seller",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			address := args[0]
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/reputation/%s", queryRoute, address), nil)
+			if err != nil {
+				fmt.Printf("could not get reputation - %s \n", address)
+				return nil
+			}
+
+			var out types.QueryResRating
+			cdc.MustUnmarshalJSON(res, &out)
+			return cliCtx.PrintOutput(out)
+		},
+	}
+}
+
+// GetCmdCoupon queries a discount coupon by its code
+func GetCmdCoupon(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "coupon [code]",
+		Short: "Query a coupon by its code",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			codeHash := types.HashCouponCode(args[0])
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/coupon/%s", queryRoute, codeHash), nil)
+			if err != nil {
+				fmt.Printf("could not get coupon - %s \n", args[0])
+				return nil
+			}
+
+			var out types.Coupon
+			cdc.MustUnmarshalJSON(res, &out)
+			return cliCtx.PrintOutput(out)
+		},
+	}
+}
+
+// GetCmdSubscription queries whether an address has an active subscription to a product
+func GetCmdSubscription(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "subscription [productID] [address]",
+		Short: "Query the subscription status of an address",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			productID, address := args[0], args[1]
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/subscription/%s/%s", queryRoute, productID, address), nil)
+			if err != nil {
+				fmt.Printf("could not get subscription - %s %s \n", productID, address)
+				return nil
+			}
+
+			var out types.QueryResSubscriptionStatus
+			cdc.MustUnmarshalJSON(res, &out)
+			return cliCtx.PrintOutput(out)
+		},
+	}
+}
+
+// GetCmdSubscriptions queries all subscriptions to a product
+func GetCmdSubscriptions(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "subscriptions [productID]",
+		Short: "Query the subscriptions to a product",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			productID := args[0]
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/subscriptions/%s", queryRoute, productID), nil)
+			if err != nil {
+				fmt.Printf("could not get subscriptions - %s \n", productID)
+				return nil
+			}
+
+			var out types.QueryResSubscriptions
+			cdc.MustUnmarshalJSON(res, &out)
+			return cliCtx.PrintOutput(out)
+		},
+	}
+}
+
+// GetCmdProductHistory queries the update audit trail of a product
+func GetCmdProductHistory(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "product-history [productID]",
+		Short: "Query the version history of a product",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			productID := args[0]
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/productHistory/%s", queryRoute, productID), nil)
+			if err != nil {
+				fmt.Printf("could not get product history - %s \n", productID)
+				return nil
+			}
+
+			var out types.QueryResProductHistory
+			cdc.MustUnmarshalJSON(res, &out)
+			return cliCtx.PrintOutput(out)
+		},
+	}
+}
+
+// GetCmdHasLicense queries whether an address holds a valid license for a product
+func GetCmdHasLicense(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "has-license [productID] [address]",
+		Short: "Query whether an address holds a valid license for a product",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			productID, address := args[0], args[1]
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/hasLicense/%s/%s", queryRoute, productID, address), nil)
+			if err != nil {
+				fmt.Printf("could not check license - %s %s \n", productID, address)
+				return nil
+			}
+
+			var out types.QueryResHasLicense
+			cdc.MustUnmarshalJSON(res, &out)
+			return cliCtx.PrintOutput(out)
+		},
+	}
+}
+
+// queryVerified reads the raw value of a key of the module store, the node returns it
+// with a Merkle proof that is verified against a header checked by the light client.
+// The value of a missing key is empty, the node then proves that the key is absent.
+func queryVerified(cliCtx context.CLIContext, storeName string, key []byte) ([]byte, error) {
+	if cliCtx.Verifier == nil {
+		return nil, fmt.Errorf("cannot verify the response of an untrusted node without a light client, " +
+			"set --chain-id or pass --trust-node")
+	}
+
+	res, _, err := cliCtx.QueryStore(key, storeName)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
 }
@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/bytes"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	"github.com/tendermint/tendermint/rpc/client/mock"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
+
+const testChainID = "nameservice-test"
+
+// storeNode serves the queries of a multistore, each version of the store is committed
+// by the block of the same height, whose app hash is in the header of the next block
+type storeNode struct {
+	mock.Client
+	store     *rootmulti.Store
+	tamper    func(resp *abci.ResponseQuery)
+	appHashes [][]byte
+}
+
+func (n *storeNode) ABCIQueryWithOptions(path string, data bytes.HexBytes,
+	opts rpcclient.ABCIQueryOptions) (*ctypes.ResultABCIQuery, error) {
+	resp := n.store.Query(abci.RequestQuery{Path: strings.TrimPrefix(path, "/store"), Data: data,
+		Height: opts.Height, Prove: opts.Prove})
+	if n.tamper != nil {
+		n.tamper(&resp)
+	}
+	return &ctypes.ResultABCIQuery{Response: resp}, nil
+}
+
+func (n *storeNode) Status() (*ctypes.ResultStatus, error) {
+	return &ctypes.ResultStatus{SyncInfo: ctypes.SyncInfo{LatestBlockHeight: int64(len(n.appHashes)) + 1}}, nil
+}
+
+func (n *storeNode) Commit(height *int64) (*ctypes.ResultCommit, error) {
+	header := tmtypes.Header{ChainID: testChainID, Height: *height}
+	if *height > 1 {
+		header.AppHash = n.appHashes[*height-2]
+	}
+	return ctypes.NewResultCommit(&header, &tmtypes.Commit{Height: *height}, true), nil
+}
+
+// trustingVerifier trusts every header and records their heights
+type trustingVerifier struct {
+	heights []int64
+}
+
+func (v *trustingVerifier) ChainID() string {
+	return testChainID
+}
+
+func (v *trustingVerifier) Verify(header tmtypes.SignedHeader) error {
+	v.heights = append(v.heights, header.Height)
+	return nil
+}
+
+// newStoreNode commits a store in which alice is set at height 1 and bob at height 2
+func newStoreNode(t *testing.T) *storeNode {
+	key := sdk.NewKVStoreKey(types.StoreKey)
+	store := rootmulti.NewStore(dbm.NewMemDB())
+	store.MountStoreWithDB(key, sdk.StoreTypeIAVL, nil)
+	require.NoError(t, store.LoadLatestVersion())
+
+	node := &storeNode{store: store}
+	for _, name := range []string{"alice", "bob"} {
+		store.GetKVStore(key).Set(types.WhoisKey(name), []byte(name))
+		node.appHashes = append(node.appHashes, store.Commit().Hash)
+	}
+	return node
+}
+
+func TestQueryVerified(t *testing.T) {
+	node := newStoreNode(t)
+	verifier := &trustingVerifier{}
+	cliCtx := context.CLIContext{}.WithClient(node).WithVerifier(verifier).WithHeight(1)
+
+	// the proof of the state at a height leads to the app hash of the next header
+	res, err := queryVerified(cliCtx, types.StoreKey, types.WhoisKey("alice"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("alice"), res)
+	require.Equal(t, []int64{2}, verifier.heights)
+
+	// bob is proven absent at height 1 and present at height 2
+	res, err = queryVerified(cliCtx, types.StoreKey, types.WhoisKey("bob"))
+	require.NoError(t, err)
+	require.Empty(t, res)
+	res, err = queryVerified(cliCtx.WithHeight(2), types.StoreKey, types.WhoisKey("bob"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("bob"), res)
+
+	_, err = queryVerified(context.CLIContext{}.WithClient(node).WithHeight(1), types.StoreKey, types.WhoisKey("alice"))
+	require.Error(t, err)
+}
+
+func TestQueryVerifiedRejectsTampering(t *testing.T) {
+	tests := []struct {
+		name   string
+		key    string
+		tamper func(resp *abci.ResponseQuery)
+	}{
+		{"tampered value", "alice", func(resp *abci.ResponseQuery) {
+			resp.Value = []byte("mallory")
+		}},
+		{"value of an absent key", "bob", func(resp *abci.ResponseQuery) {
+			resp.Value = []byte("bob")
+		}},
+		{"absence of a present key", "alice", func(resp *abci.ResponseQuery) {
+			resp.Value = nil
+		}},
+		{"tampered proof", "alice", func(resp *abci.ResponseQuery) {
+			op := &resp.Proof.Ops[len(resp.Proof.Ops)-1]
+			op.Data[len(op.Data)-1] ^= 1
+		}},
+		{"proof of another height", "alice", func(resp *abci.ResponseQuery) {
+			resp.Height++
+		}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			node := newStoreNode(t)
+			node.tamper = tc.tamper
+			cliCtx := context.CLIContext{}.WithClient(node).WithVerifier(&trustingVerifier{}).WithHeight(1)
+
+			_, err := queryVerified(cliCtx, types.StoreKey, types.WhoisKey(tc.key))
+			require.Error(t, err)
+		})
+	}
+}
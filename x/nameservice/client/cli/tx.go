@@ -2,8 +2,11 @@ package cli
 
 import (
 	"bufio"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/client/context"
@@ -15,6 +18,29 @@ import (
 	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
 )
 
+const (
+	flagCategory = "category"
+	flagTags     = "tags"
+
+	flagDenom          = "denom"
+	flagAcceptedPrices = "accepted-prices"
+	flagReferencePrice = "reference-price"
+
+	flagReservePrice = "reserve-price"
+	flagDecrement    = "decrement"
+
+	flagCoupon  = "coupon"
+	flagPercent = "percent"
+	flagAmount  = "amount"
+	flagProduct = "product"
+	flagExpiry  = "expiry"
+	flagMaxUses = "max-uses"
+
+	flagExpectedVersion = "expected-version"
+
+	flagLicenseDuration = "license-duration"
+)
+
 func GetTxCmd(storeKey string, cdc *codec.Codec) *cobra.Command {
 	nameserviceTxCmd := &cobra.Command{
 		Use:                        types.ModuleName,
@@ -33,6 +59,29 @@ func GetTxCmd(storeKey string, cdc *codec.Codec) *cobra.Command {
 		GetCmdUpdateProduct(cdc),
 		GetCmdDeleteProduct(cdc),
 		GetCmdBuyProduct(cdc),
+		GetCmdBuyProducts(cdc),
+		GetCmdListProduct(cdc),
+		GetCmdDelistProduct(cdc),
+
+		GetCmdCreateAuction(cdc),
+		GetCmdPlaceBid(cdc),
+
+		GetCmdPublishProduct(cdc),
+		GetCmdUnpublishProduct(cdc),
+		GetCmdSetStorefrontSale(cdc),
+
+		GetCmdReviewProduct(cdc),
+
+		GetCmdSetProductPricing(cdc),
+
+		GetCmdCreateCoupon(cdc),
+		GetCmdRevokeCoupon(cdc),
+		GetCmdCommitCoupon(cdc),
+
+		GetCmdSetProductSubscription(cdc),
+		GetCmdCancelSubscription(cdc),
+
+		GetCmdSetProductLicensing(cdc),
 	)...)
 
 	return nameserviceTxCmd
@@ -117,7 +166,7 @@ func GetCmdDeleteName(cdc *codec.Codec) *cobra.Command {
 }
 
 func GetCmdCreateProduct(cdc *codec.Codec) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:  "create-product [productID] [description] [price]",
 		Args: cobra.ExactArgs(3),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -131,7 +180,12 @@ func GetCmdCreateProduct(cdc *codec.Codec) *cobra.Command {
 				return err
 			}
 
-			msg := types.NewMsgCreateProduct(args[0], args[1], coins, cliCtx.GetFromAddress())
+			content, err := contentFromFlags()
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgCreateProduct(args[0], args[1], coins, viper.GetString(flagCategory), viper.GetStringSlice(flagTags), content, cliCtx.GetFromAddress())
 			err = msg.ValidateBasic()
 			if err != nil {
 				return err
@@ -140,10 +194,16 @@ func GetCmdCreateProduct(cdc *codec.Codec) *cobra.Command {
 			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
 		},
 	}
+
+	cmd.Flags().String(flagCategory, "", "category of the product")
+	cmd.Flags().StringSlice(flagTags, []string{}, "comma separated tags of the product")
+	addContentFlags(cmd)
+
+	return cmd
 }
 
 func GetCmdUpdateProduct(cdc *codec.Codec) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:  "update-product [productID] [description] [price]",
 		Args: cobra.ExactArgs(3),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -157,7 +217,12 @@ func GetCmdUpdateProduct(cdc *codec.Codec) *cobra.Command {
 				return err
 			}
 
-			msg := types.NewMsgUpdateProduct(args[0], args[1], coins, cliCtx.GetFromAddress())
+			content, err := contentFromFlags()
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgUpdateProduct(args[0], args[1], coins, viper.GetString(flagCategory), viper.GetStringSlice(flagTags), content, cliCtx.GetFromAddress())
 			err = msg.ValidateBasic()
 			if err != nil {
 				return err
@@ -166,6 +231,12 @@ func GetCmdUpdateProduct(cdc *codec.Codec) *cobra.Command {
 			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
 		},
 	}
+
+	cmd.Flags().String(flagCategory, "", "category of the product")
+	cmd.Flags().StringSlice(flagTags, []string{}, "comma separated tags of the product")
+	addContentFlags(cmd)
+
+	return cmd
 }
 
 func GetCmdDeleteProduct(cdc *codec.Codec) *cobra.Command {
@@ -190,7 +261,7 @@ func GetCmdDeleteProduct(cdc *codec.Codec) *cobra.Command {
 }
 
 func GetCmdBuyProduct(cdc *codec.Codec) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:  "buy-product [productID]",
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -199,7 +270,8 @@ func GetCmdBuyProduct(cdc *codec.Codec) *cobra.Command {
 
 			txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
 
-			msg := types.NewMsgBuyProduct(args[0], cliCtx.GetFromAddress())
+			msg := types.NewMsgBuyProduct(args[0], viper.GetString(flagDenom), viper.GetString(flagCoupon),
+				viper.GetUint64(flagExpectedVersion), cliCtx.GetFromAddress())
 			err := msg.ValidateBasic()
 			if err != nil {
 				return err
@@ -208,4 +280,495 @@ func GetCmdBuyProduct(cdc *codec.Codec) *cobra.Command {
 			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
 		},
 	}
+
+	cmd.Flags().String(flagDenom, "", "denomination to pay in, required for pegged products")
+	cmd.Flags().String(flagCoupon, "", "coupon code to redeem")
+	cmd.Flags().Uint64(flagExpectedVersion, 0, "fail if the product was updated past this version, 0 to skip the check")
+
+	return cmd
+}
+
+// GetCmdBuyProducts is the CLI command for buying several products in one transaction
+func GetCmdBuyProducts(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "buy-products [productID[:denom[:coupon[:version]]]]...",
+		Short: "buy several products at once, either all purchases succeed or none does",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			items := make([]types.CartItem, len(args))
+			for i, arg := range args {
+				item, err := parseCartItem(arg)
+				if err != nil {
+					return err
+				}
+				items[i] = item
+			}
+
+			msg := types.NewMsgBuyProducts(items, cliCtx.GetFromAddress())
+			err := msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
+
+// parseCartItem parses a productID[:denom[:coupon[:version]]] cart item
+func parseCartItem(arg string) (types.CartItem, error) {
+	parts := strings.SplitN(arg, ":", 4)
+	for len(parts) < 4 {
+		parts = append(parts, "")
+	}
+
+	var expectedVersion uint64
+	if parts[3] != "" {
+		version, err := strconv.ParseUint(parts[3], 10, 64)
+		if err != nil {
+			return types.CartItem{}, err
+		}
+		expectedVersion = version
+	}
+
+	return types.NewCartItem(parts[0], parts[1], parts[2], expectedVersion), nil
+}
+
+func GetCmdListProduct(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list-product [productID]",
+		Short: "put a product you own up for sale",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			msg := types.NewMsgListProduct(args[0], cliCtx.GetFromAddress())
+			err := msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
+
+func GetCmdDelistProduct(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "delist-product [productID]",
+		Short: "withdraw a product you own from sale",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			msg := types.NewMsgDelistProduct(args[0], cliCtx.GetFromAddress())
+			err := msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
+
+// GetCmdCreateAuction is the CLI command for putting a product up for auction
+func GetCmdCreateAuction(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create-auction [productID] [english|dutch] [start-price] [duration]",
+		Short: "put a product you own up for auction for a number of blocks",
+		Args:  cobra.ExactArgs(4),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			startPrice, err := sdk.ParseCoins(args[2])
+			if err != nil {
+				return err
+			}
+
+			duration, err := strconv.ParseInt(args[3], 10, 64)
+			if err != nil {
+				return err
+			}
+
+			reservePrice, err := sdk.ParseCoins(viper.GetString(flagReservePrice))
+			if err != nil {
+				return err
+			}
+
+			decrement, err := sdk.ParseCoins(viper.GetString(flagDecrement))
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgCreateAuction(args[0], args[1], startPrice, reservePrice, decrement, duration, cliCtx.GetFromAddress())
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+
+	cmd.Flags().String(flagReservePrice, "", "lowest price a dutch auction can decline to")
+	cmd.Flags().String(flagDecrement, "", "price drop per block of a dutch auction")
+
+	return cmd
+}
+
+// GetCmdPlaceBid is the CLI command for bidding on an auction
+func GetCmdPlaceBid(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "bid [productID] [amount]",
+		Short: "bid on the auction of a product",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			coins, err := sdk.ParseCoins(args[1])
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgPlaceBid(args[0], coins, cliCtx.GetFromAddress())
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
+
+// GetCmdPublishProduct is the CLI command for publishing a product under a name
+func GetCmdPublishProduct(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "publish-product [name] [productID]",
+		Short: "publish a product you own in the storefront of a name you own",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			msg := types.NewMsgPublishProduct(args[0], args[1], cliCtx.GetFromAddress())
+			err := msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
+
+// GetCmdUnpublishProduct is the CLI command for removing a product from its storefront
+func GetCmdUnpublishProduct(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "unpublish-product [productID]",
+		Short: "remove a product you own from its storefront",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			msg := types.NewMsgUnpublishProduct(args[0], cliCtx.GetFromAddress())
+			err := msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
+
+// GetCmdSetStorefrontSale is the CLI command for choosing whether a name is sold with its storefront
+func GetCmdSetStorefrontSale(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "set-storefront-sale [name] [true|false]",
+		Short: "choose whether buyers of a name you own also get its storefront",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			included, err := strconv.ParseBool(args[1])
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgSetStorefrontSale(args[0], included, cliCtx.GetFromAddress())
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
+
+// GetCmdReviewProduct is the CLI command for reviewing a purchased product
+func GetCmdReviewProduct(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "review-product [productID] [rating] [text]",
+		Short: "rate a product you bought from 1 to 5 with a short review",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			rating, err := strconv.ParseUint(args[1], 10, 8)
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgReviewProduct(args[0], uint8(rating), args[2], cliCtx.GetFromAddress())
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
+
+// GetCmdSetProductPricing is the CLI command for setting alternative and pegged prices of a product
+func GetCmdSetProductPricing(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-product-pricing [productID]",
+		Short: "accept several denominations for a product or peg its price to a reference unit",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			acceptedPrices, err := sdk.ParseCoins(viper.GetString(flagAcceptedPrices))
+			if err != nil {
+				return err
+			}
+
+			referencePrice, err := sdk.ParseDecCoins(viper.GetString(flagReferencePrice))
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgSetProductPricing(args[0], acceptedPrices, referencePrice, cliCtx.GetFromAddress())
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+
+	cmd.Flags().String(flagAcceptedPrices, "", "alternative prices, one full price per denomination, e.g. 10nametoken,5stake")
+	cmd.Flags().String(flagReferencePrice, "", "price in a reference unit converted through the price feed, e.g. 2.5usd")
+
+	return cmd
+}
+
+// GetCmdCreateCoupon is the CLI command for issuing a discount coupon
+func GetCmdCreateCoupon(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create-coupon [code]",
+		Short: "issue a discount coupon for your products, only the hash of the code is stored",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			amount, err := sdk.ParseCoins(viper.GetString(flagAmount))
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgCreateCoupon(
+				types.HashCouponCode(args[0]),
+				viper.GetString(flagProduct),
+				viper.GetUint64(flagPercent),
+				amount,
+				viper.GetInt64(flagExpiry),
+				viper.GetUint64(flagMaxUses),
+				cliCtx.GetFromAddress(),
+			)
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+
+	cmd.Flags().Uint64(flagPercent, 0, "percentage discount")
+	cmd.Flags().String(flagAmount, "", "fixed discount, e.g. 5nametoken")
+	cmd.Flags().String(flagProduct, "", "restrict the coupon to a single product")
+	cmd.Flags().Int64(flagExpiry, 0, "last block height at which the coupon can be redeemed, 0 to never expire")
+	cmd.Flags().Uint64(flagMaxUses, 0, "maximum number of redemptions, 0 for unlimited")
+
+	return cmd
+}
+
+// GetCmdRevokeCoupon is the CLI command for revoking a discount coupon
+func GetCmdRevokeCoupon(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "revoke-coupon [code]",
+		Short: "revoke a coupon you issued",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			msg := types.NewMsgRevokeCoupon(types.HashCouponCode(args[0]), cliCtx.GetFromAddress())
+			err := msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
+
+// GetCmdCommitCoupon is the CLI command for committing to a coupon code before redeeming it
+func GetCmdCommitCoupon(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "commit-coupon [code]",
+		Short: "commit to a coupon code, which can be redeemed with buy-product from the next block on",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			buyer := cliCtx.GetFromAddress()
+			msg := types.NewMsgCommitCoupon(types.HashCouponCode(args[0]), types.HashCouponCommitment(args[0], buyer), buyer)
+			err := msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
+
+// GetCmdSetProductSubscription is the CLI command for selling a product by subscription
+func GetCmdSetProductSubscription(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "set-product-subscription [productID] [period]",
+		Short: "sell a product by subscription charged every period blocks, a period of 0 makes it a one-shot sale again",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			period, err := strconv.ParseInt(args[1], 10, 64)
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgSetProductSubscription(args[0], period, cliCtx.GetFromAddress())
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
+
+// GetCmdCancelSubscription is the CLI command for cancelling a subscription
+func GetCmdCancelSubscription(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "cancel-subscription [productID]",
+		Short: "stop renewing a subscription, access lasts until the end of the paid period",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			msg := types.NewMsgCancelSubscription(args[0], cliCtx.GetFromAddress())
+			err := msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
+
+// GetCmdSetProductLicensing is the CLI command for selling licenses of a product
+func GetCmdSetProductLicensing(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-product-licensing [productID] [true|false]",
+		Short: "sell non-transferable licenses of a product instead of the product itself",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			licensing, err := strconv.ParseBool(args[1])
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgSetProductLicensing(args[0], licensing, viper.GetInt64(flagLicenseDuration), cliCtx.GetFromAddress())
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+
+	cmd.Flags().Int64(flagLicenseDuration, 0, "number of blocks a license lasts, 0 for perpetual licenses")
+
+	return cmd
 }
@@ -0,0 +1,149 @@
+// Package client is a typed Go client for the nameservice module.
+//
+// A Client wraps a context.CLIContext and an auth.TxBuilder: queries are decoded into
+// the structs of the types package and transactions are signed with the key of the
+// context. Failed queries and transactions return the error registered by the module,
+// so callers can match them with errors.Is, e.g. errors.Is(err, types.ErrProductDoesNotExist).
+package client
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/auth/client/utils"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
+
+// Client queries the nameservice module and broadcasts its messages
+type Client struct {
+	cliCtx     context.CLIContext
+	txBldr     auth.TxBuilder
+	queryRoute string
+	passphrase string
+}
+
+// NewClient creates a client over a context, which needs a codec and a node, and, to
+// broadcast transactions, a from address and name, and a tx builder with a keybase,
+// a chain ID and gas. The account number and sequence are queried when left at zero.
+func NewClient(cliCtx context.CLIContext, txBldr auth.TxBuilder) Client {
+	if txBldr.TxEncoder() == nil {
+		txBldr = txBldr.WithTxEncoder(utils.GetTxEncoder(cliCtx.Codec))
+	}
+
+	return Client{
+		cliCtx:     cliCtx,
+		txBldr:     txBldr,
+		queryRoute: types.QuerierRoute,
+	}
+}
+
+// WithPassphrase returns a copy of the client unlocking the signing key with a passphrase
+func (c Client) WithPassphrase(passphrase string) Client {
+	c.passphrase = passphrase
+	return c
+}
+
+// WithHeight returns a copy of the client querying the state at a height, zero being
+// the latest height
+func (c Client) WithHeight(height int64) Client {
+	c.cliCtx = c.cliCtx.WithHeight(height)
+	return c
+}
+
+// CLIContext returns the context of the client
+func (c Client) CLIContext() context.CLIContext {
+	return c.cliCtx
+}
+
+// TxBuilder returns the tx builder of the client
+func (c Client) TxBuilder() auth.TxBuilder {
+	return c.txBldr
+}
+
+// LatestHeight returns the height of the latest block of the node
+func (c Client) LatestHeight() (int64, error) {
+	node, err := c.cliCtx.GetNode()
+	if err != nil {
+		return 0, err
+	}
+
+	status, err := node.Status()
+	if err != nil {
+		return 0, err
+	}
+	return status.SyncInfo.LatestBlockHeight, nil
+}
+
+// query runs a custom query of the module and decodes its result into out
+func (c Client) query(out interface{}, data []byte, path ...interface{}) error {
+	return c.queryCustom(c.queryRoute, out, data, path...)
+}
+
+// queryCustom runs a custom query of the querier registered under a route and decodes
+// its result into out
+func (c Client) queryCustom(queryRoute string, out interface{}, data []byte, path ...interface{}) error {
+	route := fmt.Sprintf("custom/%s", queryRoute)
+	for _, p := range path {
+		route = fmt.Sprintf("%s/%s", route, p)
+	}
+
+	node, err := c.cliCtx.GetNode()
+	if err != nil {
+		return err
+	}
+
+	// the context turns the response of a failed query into a plain error, the node
+	// is asked directly to keep its code. Custom queries come without a proof, their
+	// result is the node's word whether the context trusts it or not.
+	opts := rpcclient.ABCIQueryOptions{Height: c.cliCtx.Height}
+	result, err := node.ABCIQueryWithOptions(route, data, opts)
+	if err != nil {
+		return err
+	}
+
+	resp := result.Response
+	if !resp.IsOK() {
+		return sdkerrors.ABCIError(resp.Codespace, resp.Code, resp.Log)
+	}
+
+	if err := c.cliCtx.Codec.UnmarshalJSON(resp.Value, out); err != nil {
+		return sdkerrors.Wrap(sdkerrors.ErrJSONUnmarshal, err.Error())
+	}
+	return nil
+}
+
+// Broadcast signs messages with the key of the client and broadcasts them in the
+// broadcast mode of the context. The response is returned along with the error of a
+// transaction rejected by CheckTx or, in block mode, by DeliverTx.
+func (c Client) Broadcast(msgs ...sdk.Msg) (sdk.TxResponse, error) {
+	for _, msg := range msgs {
+		if err := msg.ValidateBasic(); err != nil {
+			return sdk.TxResponse{}, err
+		}
+	}
+
+	txBldr, err := utils.PrepareTxBuilder(c.txBldr, c.cliCtx)
+	if err != nil {
+		return sdk.TxResponse{}, err
+	}
+
+	txBytes, err := txBldr.BuildAndSign(c.cliCtx.GetFromName(), c.passphrase, msgs)
+	if err != nil {
+		return sdk.TxResponse{}, err
+	}
+
+	res, err := c.cliCtx.BroadcastTx(txBytes)
+	if err != nil {
+		return res, err
+	}
+
+	if res.Code != 0 {
+		return res, sdkerrors.ABCIError(res.Codespace, res.Code, res.RawLog)
+	}
+	return res, nil
+}
@@ -0,0 +1,99 @@
+package client_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/client"
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/client/clienttest"
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
+
+// setupClients starts an app whose genesis funds the accounts of a seller and a buyer,
+// and returns a client signing with the key of each
+func setupClients(t *testing.T) (seller, buyer client.Client) {
+	_, clients := clienttest.Setup(t, "seller", "buyer")
+	return clients[0], clients[1]
+}
+
+func TestClient(t *testing.T) {
+	seller, buyer := setupClients(t)
+	sellerAddr := seller.CLIContext().GetFromAddress()
+	buyerAddr := buyer.CLIContext().GetFromAddress()
+	price := sdk.NewCoins(sdk.NewInt64Coin("nametoken", 10))
+
+	// names
+	_, err := seller.BuyName("alice", price)
+	require.NoError(t, err)
+	_, err = seller.SetName("alice", "8.8.8.8")
+	require.NoError(t, err)
+
+	value, err := seller.Resolve("alice")
+	require.NoError(t, err)
+	require.Equal(t, "8.8.8.8", value)
+
+	whois, err := buyer.Whois("alice")
+	require.NoError(t, err)
+	require.Equal(t, sellerAddr, whois.Owner)
+
+	names, err := buyer.Names()
+	require.NoError(t, err)
+	require.Equal(t, []string{"alice"}, names)
+
+	// products
+	_, err = seller.CreateProduct("book1", "a book", price, "books", []string{"new"}, types.Content{})
+	require.NoError(t, err)
+	_, err = seller.ListProduct("book1")
+	require.NoError(t, err)
+
+	product, err := buyer.Product("book1")
+	require.NoError(t, err)
+	require.Equal(t, sellerAddr, product.Owner)
+	require.True(t, product.Listed)
+
+	listed, err := buyer.AllProducts(true)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+
+	books, err := buyer.Products(types.QueryProductsParams{Category: "books"})
+	require.NoError(t, err)
+	require.Len(t, books, 1)
+
+	res, err := buyer.BuyProduct("book1", "", "", product.Version)
+	require.NoError(t, err)
+	require.Zero(t, res.Code)
+
+	product, err = seller.Product("book1")
+	require.NoError(t, err)
+	require.Equal(t, buyerAddr, product.Owner)
+
+	account, err := buyer.Account(sellerAddr)
+	require.NoError(t, err)
+	require.Equal(t, sellerAddr, account.GetAddress())
+
+	// failed queries and transactions return the errors of the module
+	_, err = buyer.Product("book2")
+	require.True(t, errors.Is(err, types.ErrProductDoesNotExist), "unexpected error: %v", err)
+
+	_, err = buyer.Auction("book1")
+	require.True(t, errors.Is(err, types.ErrAuctionDoesNotExist), "unexpected error: %v", err)
+
+	_, err = buyer.Resolve("bob")
+	require.True(t, errors.Is(err, sdkerrors.ErrUnknownRequest), "unexpected error: %v", err)
+
+	_, err = buyer.Account(sdk.AccAddress([]byte("unknown_address_____")))
+	require.True(t, errors.Is(err, sdkerrors.ErrUnknownAddress), "unexpected error: %v", err)
+
+	res, err = buyer.DeleteName("alice")
+	require.True(t, errors.Is(err, sdkerrors.ErrUnauthorized), "unexpected error: %v", err)
+	require.NotZero(t, res.Code)
+
+	res, err = seller.PlaceBid("book1", price)
+	require.True(t, errors.Is(err, types.ErrAuctionDoesNotExist), "unexpected error: %v", err)
+	require.NotZero(t, res.Code)
+}
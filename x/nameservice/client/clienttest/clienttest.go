@@ -0,0 +1,328 @@
+// Package clienttest runs the nameservice app in process behind a Tendermint RPC
+// client, for the tests of the clients of the module.
+package clienttest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/bytes"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/lite"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	"github.com/tendermint/tendermint/rpc/client/mock"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+	dbm "github.com/tendermint/tm-db"
+
+	clientcontext "github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/crypto/keys"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	authexported "github.com/cosmos/cosmos-sdk/x/auth/exported"
+
+	app "github.com/cosmos/sdk-tutorials/nameservice"
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/client"
+)
+
+const (
+	// ChainID is the chain ID of the app
+	ChainID = "nameservice-test"
+	// Passphrase encrypts the keys of the accounts
+	Passphrase = "12345678"
+)
+
+// Node runs the app in process, every transaction broadcast in block mode is committed
+// in a block of its own. It keeps the blocks and their results, and publishes the new
+// blocks to its subscribers. The header of a block holds the app hash of the state
+// committed by the previous block, as in Tendermint.
+type Node struct {
+	mock.Client
+
+	mtx           sync.Mutex
+	app           abci.Application
+	appHash       []byte
+	blocks        []*ctypes.ResultBlock
+	results       []*ctypes.ResultBlockResults
+	subscriptions map[string]chan ctypes.ResultEvent
+}
+
+var _ rpcclient.Client = (*Node)(nil)
+
+// ABCIQueryWithOptions queries the app
+func (n *Node) ABCIQueryWithOptions(path string, data bytes.HexBytes,
+	opts rpcclient.ABCIQueryOptions) (*ctypes.ResultABCIQuery, error) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	return mock.ABCIApp{App: n.app}.ABCIQueryWithOptions(path, data, opts)
+}
+
+// Status returns the height of the latest block
+func (n *Node) Status() (*ctypes.ResultStatus, error) {
+	return &ctypes.ResultStatus{SyncInfo: ctypes.SyncInfo{LatestBlockHeight: n.Height()}}, nil
+}
+
+// BroadcastTxCommit checks a transaction and commits it in a new block
+func (n *Node) BroadcastTxCommit(tx tmtypes.Tx) (*ctypes.ResultBroadcastTxCommit, error) {
+	res := &ctypes.ResultBroadcastTxCommit{Hash: tx.Hash()}
+
+	n.mtx.Lock()
+	res.CheckTx = n.app.CheckTx(abci.RequestCheckTx{Tx: tx})
+	n.mtx.Unlock()
+	if res.CheckTx.IsErr() {
+		return res, nil
+	}
+
+	res.DeliverTx = *n.CommitBlock(tx)[0]
+	res.Height = n.Height()
+	return res, nil
+}
+
+// BroadcastTxSync checks a transaction and commits it in a new block when it passes
+func (n *Node) BroadcastTxSync(tx tmtypes.Tx) (*ctypes.ResultBroadcastTx, error) {
+	n.mtx.Lock()
+	res := n.app.CheckTx(abci.RequestCheckTx{Tx: tx})
+	n.mtx.Unlock()
+	if res.IsOK() {
+		n.CommitBlock(tx)
+	}
+
+	return &ctypes.ResultBroadcastTx{Code: res.Code, Data: res.Data, Log: res.Log, Hash: tx.Hash()}, nil
+}
+
+// BroadcastTxAsync broadcasts a transaction like BroadcastTxSync, and only returns its hash
+func (n *Node) BroadcastTxAsync(tx tmtypes.Tx) (*ctypes.ResultBroadcastTx, error) {
+	if _, err := n.BroadcastTxSync(tx); err != nil {
+		return nil, err
+	}
+	return &ctypes.ResultBroadcastTx{Hash: tx.Hash()}, nil
+}
+
+// CommitBlock commits a block of transactions, publishes it and returns the results of
+// the transactions
+func (n *Node) CommitBlock(txs ...tmtypes.Tx) []*abci.ResponseDeliverTx {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	height := int64(len(n.blocks)) + 1
+	header := abci.Header{ChainID: ChainID, Height: height}
+
+	results := &ctypes.ResultBlockResults{Height: height}
+	results.BeginBlockEvents = n.app.BeginBlock(abci.RequestBeginBlock{Header: header}).Events
+	for _, tx := range txs {
+		res := n.app.DeliverTx(abci.RequestDeliverTx{Tx: tx})
+		results.TxsResults = append(results.TxsResults, &res)
+	}
+	results.EndBlockEvents = n.app.EndBlock(abci.RequestEndBlock{Height: height}).Events
+
+	block := tmtypes.MakeBlock(height, txs, nil, nil)
+	block.ChainID = ChainID
+	block.AppHash = n.appHash
+	n.appHash = n.app.Commit().Data
+	n.blocks = append(n.blocks, &ctypes.ResultBlock{Block: block})
+	n.results = append(n.results, results)
+
+	event := ctypes.ResultEvent{Query: "tm.event='NewBlock'", Data: tmtypes.EventDataNewBlock{
+		Block:            block,
+		ResultEndBlock:   abci.ResponseEndBlock{Events: results.EndBlockEvents},
+		ResultBeginBlock: abci.ResponseBeginBlock{Events: results.BeginBlockEvents},
+	}}
+	for _, out := range n.subscriptions {
+		select {
+		case out <- event:
+		default:
+		}
+	}
+	return results.TxsResults
+}
+
+// Height returns the height of the latest block
+func (n *Node) Height() int64 {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	return int64(len(n.blocks))
+}
+
+// Block returns a block, the latest one when height is nil
+func (n *Node) Block(height *int64) (*ctypes.ResultBlock, error) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	i, err := n.index(height)
+	if err != nil {
+		return nil, err
+	}
+	return n.blocks[i], nil
+}
+
+// Commit returns the header of a block. Its commit is not signed, the headers are
+// trusted by Verifier.
+func (n *Node) Commit(height *int64) (*ctypes.ResultCommit, error) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	i, err := n.index(height)
+	if err != nil {
+		return nil, err
+	}
+	header := n.blocks[i].Block.Header
+	return ctypes.NewResultCommit(&header, &tmtypes.Commit{Height: header.Height}, true), nil
+}
+
+// BlockResults returns the results of a block, the latest one when height is nil
+func (n *Node) BlockResults(height *int64) (*ctypes.ResultBlockResults, error) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	i, err := n.index(height)
+	if err != nil {
+		return nil, err
+	}
+	return n.results[i], nil
+}
+
+func (n *Node) index(height *int64) (int, error) {
+	if height == nil {
+		return len(n.blocks) - 1, nil
+	}
+	if *height < 1 || *height > int64(len(n.blocks)) {
+		return 0, fmt.Errorf("height %d must be between 1 and %d", *height, len(n.blocks))
+	}
+	return int(*height) - 1, nil
+}
+
+// IsRunning tells that the node is always running
+func (n *Node) IsRunning() bool {
+	return true
+}
+
+// Subscribe subscribes to the new blocks, whatever the query
+func (n *Node) Subscribe(_ context.Context, subscriber, query string,
+	outCapacity ...int) (<-chan ctypes.ResultEvent, error) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	key := subscriber + "/" + query
+	if _, ok := n.subscriptions[key]; ok {
+		return nil, fmt.Errorf("%s already subscribed to %s", subscriber, query)
+	}
+
+	capacity := 1
+	if len(outCapacity) > 0 {
+		capacity = outCapacity[0]
+	}
+	out := make(chan ctypes.ResultEvent, capacity)
+	n.subscriptions[key] = out
+	return out, nil
+}
+
+// Unsubscribe closes the channel of a subscription
+func (n *Node) Unsubscribe(_ context.Context, subscriber, query string) error {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	key := subscriber + "/" + query
+	out, ok := n.subscriptions[key]
+	if !ok {
+		return fmt.Errorf("%s is not subscribed to %s", subscriber, query)
+	}
+	close(out)
+	delete(n.subscriptions, key)
+	return nil
+}
+
+// Subscriptions returns the number of subscriptions
+func (n *Node) Subscriptions() int {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	return len(n.subscriptions)
+}
+
+// Verifier is a light client trusting every header of the node. It records the heights
+// of the headers it was asked to verify.
+type Verifier struct {
+	mtx     sync.Mutex
+	heights []int64
+}
+
+var _ lite.Verifier = (*Verifier)(nil)
+
+// ChainID returns the chain ID of the app
+func (v *Verifier) ChainID() string {
+	return ChainID
+}
+
+// Verify trusts a header
+func (v *Verifier) Verify(header tmtypes.SignedHeader) error {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	v.heights = append(v.heights, header.Height)
+	return nil
+}
+
+// Heights returns the heights of the headers verified so far
+func (v *Verifier) Heights() []int64 {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	return append([]int64(nil), v.heights...)
+}
+
+// Setup starts an app whose genesis funds the accounts of a keybase with 1000nametoken,
+// one account per name, and returns the node and a client signing with the key of each
+// account. The clients trust the node and broadcast in block mode.
+func Setup(t *testing.T, names ...string) (*Node, []client.Client) {
+	cdc := app.MakeCodec()
+	kb := keys.NewInMemory()
+
+	var accounts []keys.Info
+	for _, name := range names {
+		info, _, err := kb.CreateMnemonic(name, keys.English, Passphrase, keys.Secp256k1)
+		require.NoError(t, err)
+		accounts = append(accounts, info)
+	}
+
+	genesis := app.NewDefaultGenesisState()
+	coins := sdk.NewCoins(sdk.NewInt64Coin("nametoken", 1000))
+	var genAccounts authexported.GenesisAccounts
+	for _, info := range accounts {
+		genAccounts = append(genAccounts, auth.NewBaseAccount(info.GetAddress(), coins, nil, 0, 0))
+	}
+	genesis[auth.ModuleName] = cdc.MustMarshalJSON(auth.NewGenesisState(auth.DefaultParams(), genAccounts))
+	appState, err := cdc.MarshalJSON(genesis)
+	require.NoError(t, err)
+
+	nsApp := app.NewNameServiceApp(log.NewNopLogger(), dbm.NewMemDB(), 0)
+	nsApp.InitChain(abci.RequestInitChain{ChainId: ChainID, AppStateBytes: appState})
+
+	// the first block commits the genesis state, which queries read
+	node := &Node{app: nsApp, subscriptions: make(map[string]chan ctypes.ResultEvent)}
+	node.CommitBlock()
+
+	clients := make([]client.Client, len(accounts))
+	for i, info := range accounts {
+		cliCtx := clientcontext.CLIContext{}.
+			WithCodec(cdc).
+			WithClient(node).
+			WithTrustNode(true).
+			WithChainID(ChainID).
+			WithBroadcastMode(flags.BroadcastBlock).
+			WithFromName(info.GetName()).
+			WithFromAddress(info.GetAddress())
+
+		txBldr := auth.NewTxBuilder(nil, 0, 0, flags.DefaultGasLimit, 0, false, ChainID, "", nil, nil).
+			WithKeybase(kb)
+
+		clients[i] = client.NewClient(cliCtx, txBldr).WithPassphrase(Passphrase)
+	}
+	return node, clients
+}
@@ -0,0 +1,71 @@
+package dns
+
+import (
+	"net"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tendermint/tendermint/libs/log"
+	tmos "github.com/tendermint/tendermint/libs/os"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/client"
+)
+
+const (
+	flagListenAddr = "laddr"
+	flagZone       = "zone"
+	flagTTL        = "ttl"
+)
+
+// ServeCommand starts a DNS server answering queries for the names of the nameservice
+// module over UDP and TCP
+func ServeCommand(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dns-server",
+		Short: "Start a DNS server answering A, AAAA, CNAME and TXT queries for the names of a zone",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger := log.NewTMLogger(log.NewSyncWriter(os.Stdout)).With("module", "dns-server")
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			source := NewNodeSource(client.NewClient(cliCtx, auth.TxBuilder{}))
+
+			addr := viper.GetString(flagListenAddr)
+			zone := viper.GetString(flagZone)
+			server := NewServer(zone, uint32(viper.GetUint(flagTTL)), source, logger)
+
+			packetConn, err := net.ListenPacket("udp", addr)
+			if err != nil {
+				return err
+			}
+			listener, err := net.Listen("tcp", addr)
+			if err != nil {
+				packetConn.Close()
+				return err
+			}
+
+			tmos.TrapSignal(logger, func() {
+				packetConn.Close()
+				listener.Close()
+			})
+
+			logger.Info("starting DNS server", "zone", zone, "addr", addr)
+
+			errs := make(chan error, 2)
+			go func() { errs <- server.ServePacket(packetConn) }()
+			go func() { errs <- server.Serve(listener) }()
+			return <-errs
+		},
+	}
+
+	cmd = flags.GetCommands(cmd)[0]
+	cmd.Flags().String(flagListenAddr, "127.0.0.1:5353", "The UDP and TCP address for the server to listen on")
+	cmd.Flags().String(flagZone, "ns.", "The zone the names are served under")
+	cmd.Flags().Uint(flagTTL, 5, "The time to live of the records (in seconds)")
+
+	return cmd
+}
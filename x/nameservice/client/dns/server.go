@@ -0,0 +1,329 @@
+// Package dns serves the names of the nameservice module over DNS.
+//
+// The names are served under a zone, e.g. the name alice is served as alice.ns. in the
+// zone ns. The value of a name answers A queries when it is an IPv4 address, AAAA
+// queries when it is an IPv6 address and A, AAAA and CNAME queries with a CNAME record
+// when it is a host name. TXT queries are answered with the value itself. Names nobody
+// owns do not exist. DNS names are case insensitive, queries are looked up lower case.
+package dns
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/dns/dnsmessage"
+
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/client"
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
+
+const (
+	// maxUDPSize is the size of the largest answer sent over UDP, larger answers are truncated
+	maxUDPSize = 512
+	// maxTXTLength is the length of the longest string of a TXT record
+	maxTXTLength = 255
+)
+
+// Source reads the records of names
+type Source interface {
+	// LatestHeight returns the height of the latest block
+	LatestHeight() (int64, error)
+	// Whois returns the record of a name at a height
+	Whois(name string, height int64) (types.Whois, error)
+}
+
+// nodeSource reads the records of names from the node of a client
+type nodeSource struct {
+	client client.Client
+}
+
+// NewNodeSource creates a source querying the node of a client
+func NewNodeSource(c client.Client) Source {
+	return nodeSource{client: c}
+}
+
+func (s nodeSource) LatestHeight() (int64, error) {
+	return s.client.LatestHeight()
+}
+
+func (s nodeSource) Whois(name string, height int64) (types.Whois, error) {
+	return s.client.WithHeight(height).Whois(name)
+}
+
+// Server answers DNS queries for the names of a zone. Records are cached until the
+// next block.
+type Server struct {
+	zone   string
+	ttl    uint32
+	source Source
+	logger log.Logger
+
+	mtx    sync.Mutex
+	height int64
+	cache  map[string]types.Whois
+}
+
+// NewServer creates a server answering queries for the names of a zone with records
+// living ttl seconds
+func NewServer(zone string, ttl uint32, source Source, logger log.Logger) *Server {
+	return &Server{
+		zone:   strings.ToLower(strings.TrimSuffix(zone, ".") + "."),
+		ttl:    ttl,
+		source: source,
+		logger: logger,
+		cache:  make(map[string]types.Whois),
+	}
+}
+
+// ServePacket answers the queries received on a packet connection, until it is closed
+func (s *Server) ServePacket(conn net.PacketConn) error {
+	buf := make([]byte, 65535)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			return err
+		}
+
+		res, err := s.answer(buf[:n], maxUDPSize)
+		if err != nil {
+			s.logger.Debug("dropping malformed query", "from", addr, "err", err)
+			continue
+		}
+
+		if _, err := conn.WriteTo(res, addr); err != nil {
+			s.logger.Error("failed to write answer", "to", addr, "err", err)
+		}
+	}
+}
+
+// Serve answers the queries received on the connections of a stream listener, until it
+// is closed
+func (s *Server) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+
+		go s.serveConn(conn)
+	}
+}
+
+// serveConn answers the queries of a stream connection, each message is preceded by
+// its length on two bytes
+func (s *Server) serveConn(conn net.Conn) {
+	defer conn.Close()
+
+	for {
+		var length uint16
+		if err := binary.Read(conn, binary.BigEndian, &length); err != nil {
+			return
+		}
+
+		query := make([]byte, length)
+		if _, err := io.ReadFull(conn, query); err != nil {
+			return
+		}
+
+		res, err := s.answer(query, 65535)
+		if err != nil {
+			s.logger.Debug("closing connection after malformed query", "from", conn.RemoteAddr(), "err", err)
+			return
+		}
+
+		if err := binary.Write(conn, binary.BigEndian, uint16(len(res))); err != nil {
+			return
+		}
+		if _, err := conn.Write(res); err != nil {
+			return
+		}
+	}
+}
+
+// answer builds the answer to a query, truncated to maxSize bytes
+func (s *Server) answer(query []byte, maxSize int) ([]byte, error) {
+	var p dnsmessage.Parser
+	header, err := p.Start(query)
+	if err != nil {
+		return nil, err
+	}
+	if header.Response {
+		return nil, errors.New("message is not a query")
+	}
+
+	questions, err := p.AllQuestions()
+	if err != nil {
+		return nil, err
+	}
+
+	res := dnsmessage.Message{
+		Header: dnsmessage.Header{
+			ID:               header.ID,
+			Response:         true,
+			OpCode:           header.OpCode,
+			Authoritative:    true,
+			RecursionDesired: header.RecursionDesired,
+		},
+		Questions: questions,
+	}
+
+	switch {
+	case header.OpCode != 0:
+		res.RCode = dnsmessage.RCodeNotImplemented
+	case len(questions) != 1:
+		res.RCode = dnsmessage.RCodeFormatError
+	default:
+		res.RCode, res.Answers = s.resolve(questions[0])
+	}
+
+	out, err := res.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(out) > maxSize {
+		res.Truncated = true
+		res.Answers = nil
+		return res.Pack()
+	}
+	return out, nil
+}
+
+// resolve answers a question
+func (s *Server) resolve(q dnsmessage.Question) (dnsmessage.RCode, []dnsmessage.Resource) {
+	if q.Class != dnsmessage.ClassINET {
+		return dnsmessage.RCodeRefused, nil
+	}
+
+	fqdn := strings.ToLower(q.Name.String())
+	if fqdn == s.zone {
+		return dnsmessage.RCodeSuccess, nil
+	}
+
+	name := strings.TrimSuffix(fqdn, "."+s.zone)
+	if name == fqdn {
+		return dnsmessage.RCodeRefused, nil
+	}
+
+	whois, err := s.lookup(name)
+	if err != nil {
+		s.logger.Error("failed to look name up", "name", name, "err", err)
+		return dnsmessage.RCodeServerFailure, nil
+	}
+	if whois.Owner.Empty() {
+		return dnsmessage.RCodeNameError, nil
+	}
+
+	header := dnsmessage.ResourceHeader{Name: q.Name, Type: q.Type, Class: dnsmessage.ClassINET, TTL: s.ttl}
+	ip := net.ParseIP(whois.Value)
+	target, isHost := hostName(whois.Value)
+
+	switch {
+	case q.Type == dnsmessage.TypeTXT:
+		return dnsmessage.RCodeSuccess, []dnsmessage.Resource{
+			{Header: header, Body: &dnsmessage.TXTResource{TXT: splitTXT(whois.Value)}},
+		}
+
+	case isHost && (q.Type == dnsmessage.TypeA || q.Type == dnsmessage.TypeAAAA || q.Type == dnsmessage.TypeCNAME):
+		header.Type = dnsmessage.TypeCNAME
+		return dnsmessage.RCodeSuccess, []dnsmessage.Resource{
+			{Header: header, Body: &dnsmessage.CNAMEResource{CNAME: target}},
+		}
+
+	case q.Type == dnsmessage.TypeA && ip != nil && ip.To4() != nil:
+		var a dnsmessage.AResource
+		copy(a.A[:], ip.To4())
+		return dnsmessage.RCodeSuccess, []dnsmessage.Resource{{Header: header, Body: &a}}
+
+	case q.Type == dnsmessage.TypeAAAA && ip != nil && ip.To4() == nil:
+		var aaaa dnsmessage.AAAAResource
+		copy(aaaa.AAAA[:], ip.To16())
+		return dnsmessage.RCodeSuccess, []dnsmessage.Resource{{Header: header, Body: &aaaa}}
+
+	default:
+		// the name exists without records of the type
+		return dnsmessage.RCodeSuccess, nil
+	}
+}
+
+// lookup returns the record of a name at the latest height, records read at an older
+// height are dropped from the cache
+func (s *Server) lookup(name string) (types.Whois, error) {
+	height, err := s.source.LatestHeight()
+	if err != nil {
+		return types.Whois{}, err
+	}
+
+	s.mtx.Lock()
+	if height != s.height {
+		s.height = height
+		s.cache = make(map[string]types.Whois)
+	}
+	whois, ok := s.cache[name]
+	s.mtx.Unlock()
+
+	if ok {
+		return whois, nil
+	}
+
+	whois, err = s.source.Whois(name, height)
+	if err != nil {
+		return types.Whois{}, err
+	}
+
+	s.mtx.Lock()
+	if height == s.height {
+		s.cache[name] = whois
+	}
+	s.mtx.Unlock()
+
+	return whois, nil
+}
+
+// hostName returns the fully qualified form of a value that is a host name, i.e. at
+// least two labels of letters, digits and hyphens
+func hostName(value string) (dnsmessage.Name, bool) {
+	host := strings.TrimSuffix(value, ".")
+	labels := strings.Split(host, ".")
+	if len(labels) < 2 {
+		return dnsmessage.Name{}, false
+	}
+
+	for _, label := range labels {
+		if len(label) == 0 || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return dnsmessage.Name{}, false
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+				return dnsmessage.Name{}, false
+			}
+		}
+	}
+
+	// IPv4 addresses are made of valid labels too
+	if net.ParseIP(host) != nil {
+		return dnsmessage.Name{}, false
+	}
+
+	name, err := dnsmessage.NewName(host + ".")
+	if err != nil {
+		return dnsmessage.Name{}, false
+	}
+	return name, true
+}
+
+// splitTXT splits a value in the strings of a TXT record
+func splitTXT(value string) []string {
+	txt := []string{}
+	for len(value) > maxTXTLength {
+		txt = append(txt, value[:maxTXTLength])
+		value = value[maxTXTLength:]
+	}
+	return append(txt, value)
+}
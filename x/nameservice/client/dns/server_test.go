@@ -0,0 +1,154 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/libs/log"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
+
+// fakeSource serves records from memory and counts the lookups
+type fakeSource struct {
+	mtx     sync.Mutex
+	height  int64
+	names   map[string]string
+	lookups int
+}
+
+func (s *fakeSource) LatestHeight() (int64, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.height, nil
+}
+
+func (s *fakeSource) Whois(name string, height int64) (types.Whois, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.lookups++
+	value, ok := s.names[name]
+	if !ok {
+		return types.NewWhois(), nil
+	}
+	owner := sdk.AccAddress(crypto.AddressHash([]byte("owner")))
+	return types.Whois{Value: value, Owner: owner, Price: types.MinNamePrice}, nil
+}
+
+// startServer serves a source over UDP and TCP, and returns a resolver sending its
+// queries to the server over the network it is asked for, and a function stopping the server
+func startServer(t *testing.T, source Source) (*net.Resolver, func()) {
+	server := NewServer("ns", 5, source, log.NewNopLogger())
+
+	packetConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go server.ServePacket(packetConn) // nolint: errcheck
+	go server.Serve(listener)         // nolint: errcheck
+
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			if strings.HasPrefix(network, "tcp") {
+				return d.DialContext(ctx, network, listener.Addr().String())
+			}
+			return d.DialContext(ctx, network, packetConn.LocalAddr().String())
+		},
+	}
+
+	return resolver, func() {
+		packetConn.Close()
+		listener.Close()
+	}
+}
+
+func TestServer(t *testing.T) {
+	source := &fakeSource{height: 1, names: map[string]string{
+		"alice": "8.8.8.8",
+		"bob":   "2001:db8::1",
+		"carol": "example.com",
+		"dave":  "hello world",
+	}}
+	resolver, stop := startServer(t, source)
+	defer stop()
+	ctx := context.Background()
+
+	addrs, err := resolver.LookupHost(ctx, "alice.ns.")
+	require.NoError(t, err)
+	require.Equal(t, []string{"8.8.8.8"}, addrs)
+
+	addrs, err = resolver.LookupHost(ctx, "BOB.ns.")
+	require.NoError(t, err)
+	require.Equal(t, []string{"2001:db8::1"}, addrs)
+
+	cname, err := resolver.LookupCNAME(ctx, "carol.ns.")
+	require.NoError(t, err)
+	require.Equal(t, "example.com.", cname)
+
+	txt, err := resolver.LookupTXT(ctx, "dave.ns.")
+	require.NoError(t, err)
+	require.Equal(t, []string{"hello world"}, txt)
+
+	// names nobody owns do not exist
+	_, err = resolver.LookupHost(ctx, "erin.ns.")
+	var dnsErr *net.DNSError
+	require.True(t, errors.As(err, &dnsErr), "unexpected error: %v", err)
+	require.True(t, dnsErr.IsNotFound)
+
+	// names outside of the zone are refused
+	_, err = resolver.LookupHost(ctx, "alice.example.")
+	require.Error(t, err)
+}
+
+func TestServerLongTXT(t *testing.T) {
+	value := strings.Repeat("a", 600)
+	resolver, stop := startServer(t, &fakeSource{height: 1, names: map[string]string{"alice": value}})
+	defer stop()
+
+	// the answer does not fit in a UDP message, the resolver retries over TCP
+	txt, err := resolver.LookupTXT(context.Background(), "alice.ns.")
+	require.NoError(t, err)
+	require.Equal(t, value, strings.Join(txt, ""))
+}
+
+func TestServerCache(t *testing.T) {
+	source := &fakeSource{height: 1, names: map[string]string{"alice": "8.8.8.8"}}
+	server := NewServer("ns.", 5, source, log.NewNopLogger())
+
+	for i := 0; i < 3; i++ {
+		whois, err := server.lookup("alice")
+		require.NoError(t, err)
+		require.Equal(t, "8.8.8.8", whois.Value)
+	}
+	require.Equal(t, 1, source.lookups)
+
+	// records are read again once a block is committed
+	source.height = 2
+	source.names["alice"] = "1.1.1.1"
+	whois, err := server.lookup("alice")
+	require.NoError(t, err)
+	require.Equal(t, "1.1.1.1", whois.Value)
+	require.Equal(t, 2, source.lookups)
+}
+
+func TestSplitTXT(t *testing.T) {
+	require.Equal(t, []string{""}, splitTXT(""))
+
+	txt := splitTXT(strings.Repeat("a", 2*maxTXTLength+1))
+	require.Len(t, txt, 3)
+	require.Len(t, txt[0], maxTXTLength)
+	require.Len(t, txt[1], maxTXTLength)
+	require.Equal(t, "a", txt[2])
+}
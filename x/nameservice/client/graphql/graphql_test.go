@@ -0,0 +1,348 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/client"
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/client/clienttest"
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
+
+// setup starts an app in which the account of a client owns the names alice and bob
+// and the products book1, published under alice, and book2, and returns the client and
+// a server of the GraphQL endpoint
+func setup(t *testing.T) (client.Client, *httptest.Server) {
+	_, clients := clienttest.Setup(t, "owner")
+	owner := clients[0]
+
+	price := sdk.NewCoins(sdk.NewInt64Coin("nametoken", 10))
+	for _, name := range []string{"alice", "bob"} {
+		_, err := owner.BuyName(name, price)
+		require.NoError(t, err)
+	}
+	_, err := owner.SetName("alice", "8.8.8.8")
+	require.NoError(t, err)
+	for _, productID := range []string{"book1", "book2"} {
+		_, err = owner.CreateProduct(productID, "a book", price, "books", nil, types.Content{})
+		require.NoError(t, err)
+	}
+	_, err = owner.PublishProduct("alice", "book1")
+	require.NoError(t, err)
+
+	r := mux.NewRouter()
+	RegisterRoutes(owner.CLIContext(), r, "nameservice")
+	return owner, httptest.NewServer(r)
+}
+
+// countingClient counts the queries sent to a node by route
+type countingClient struct {
+	rpcclient.Client
+
+	mtx     sync.Mutex
+	queries map[string]int
+}
+
+func (c *countingClient) ABCIQueryWithOptions(path string, data tmbytes.HexBytes,
+	opts rpcclient.ABCIQueryOptions) (*ctypes.ResultABCIQuery, error) {
+	c.mtx.Lock()
+	c.queries[strings.TrimPrefix(path, "custom/nameservice/")]++
+	c.mtx.Unlock()
+	return c.Client.ABCIQueryWithOptions(path, data, opts)
+}
+
+// serveCounting serves the GraphQL endpoint from a client counting the queries of
+// the requests
+func serveCounting(owner client.Client) (*httptest.Server, *countingClient) {
+	cliCtx := owner.CLIContext()
+	node := &countingClient{Client: cliCtx.Client, queries: make(map[string]int)}
+
+	r := mux.NewRouter()
+	RegisterRoutes(cliCtx.WithClient(node), r, "nameservice")
+	return httptest.NewServer(r), node
+}
+
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string `json:"message"`
+		Extensions struct {
+			Codespace string `json:"codespace"`
+			Code      uint32 `json:"code"`
+		} `json:"extensions"`
+	} `json:"errors"`
+}
+
+func execute(t *testing.T, url, query string, variables map[string]interface{}, data interface{}) response {
+	bz, err := json.Marshal(request{Query: query, Variables: variables})
+	require.NoError(t, err)
+	res, err := http.Post(url, "application/json", bytes.NewReader(bz))
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	bz, err = ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode, string(bz))
+
+	var resp response
+	require.NoError(t, json.Unmarshal(bz, &resp))
+	if data != nil {
+		require.Empty(t, resp.Errors, string(bz))
+		require.NoError(t, json.Unmarshal(resp.Data, data))
+	}
+	return resp
+}
+
+func TestSchemaFile(t *testing.T) {
+	bz, err := ioutil.ReadFile("schema.graphql")
+	require.NoError(t, err)
+	require.Equal(t, string(bz), schema, "schema.graphql and the schema constant differ")
+}
+
+func TestAccountProfile(t *testing.T) {
+	owner, server := setup(t)
+	defer server.Close()
+	url := server.URL + "/nameservice/graphql"
+	address := owner.CLIContext().GetFromAddress().String()
+
+	// the profile of an owner is read in a single request
+	var profile struct {
+		Height  string `json:"height"`
+		Account struct {
+			Address string `json:"address"`
+			Coins   []struct {
+				Denom  string `json:"denom"`
+				Amount string `json:"amount"`
+			} `json:"coins"`
+			Names struct {
+				TotalCount int `json:"totalCount"`
+				Edges      []struct {
+					Node struct {
+						Name       string `json:"name"`
+						Value      string `json:"value"`
+						Storefront struct {
+							Edges []struct {
+								Node struct {
+									ID string `json:"id"`
+								} `json:"node"`
+							} `json:"edges"`
+						} `json:"storefront"`
+					} `json:"node"`
+				} `json:"edges"`
+			} `json:"names"`
+			Products struct {
+				TotalCount int `json:"totalCount"`
+				Edges      []struct {
+					Node struct {
+						ID         string   `json:"id"`
+						Tags       []string `json:"tags"`
+						Storefront *struct {
+							Owner struct {
+								Address string `json:"address"`
+							} `json:"owner"`
+						} `json:"storefront"`
+					} `json:"node"`
+				} `json:"edges"`
+			} `json:"products"`
+		} `json:"account"`
+	}
+	execute(t, url, `query($address: String!) {
+		height
+		account(address: $address) {
+			address
+			coins { denom amount }
+			names { totalCount edges { node { name value storefront { edges { node { id } } } } } }
+			products { totalCount edges { node { id tags storefront { owner { address } } } } }
+		}
+	}`, map[string]interface{}{"address": address}, &profile)
+
+	require.Equal(t, "7", profile.Height)
+	require.Equal(t, address, profile.Account.Address)
+	require.Equal(t, "nametoken", profile.Account.Coins[0].Denom)
+	require.Equal(t, "980", profile.Account.Coins[0].Amount)
+
+	require.Equal(t, 2, profile.Account.Names.TotalCount)
+	alice := profile.Account.Names.Edges[0].Node
+	require.Equal(t, "alice", alice.Name)
+	require.Equal(t, "8.8.8.8", alice.Value)
+	require.Len(t, alice.Storefront.Edges, 1)
+	require.Equal(t, "book1", alice.Storefront.Edges[0].Node.ID)
+	require.Equal(t, "bob", profile.Account.Names.Edges[1].Node.Name)
+
+	require.Equal(t, 2, profile.Account.Products.TotalCount)
+	book1 := profile.Account.Products.Edges[0].Node
+	require.Equal(t, "book1", book1.ID)
+	require.Empty(t, book1.Tags)
+	require.Equal(t, address, book1.Storefront.Owner.Address)
+	require.Nil(t, profile.Account.Products.Edges[1].Node.Storefront)
+
+	// the value of alice was set at height 4
+	var whois struct {
+		Whois struct {
+			Value string `json:"value"`
+		} `json:"whois"`
+	}
+	execute(t, url+"?height=3", `{ whois(name: "alice") { value } }`, nil, &whois)
+	require.Empty(t, whois.Whois.Value)
+}
+
+func TestPagination(t *testing.T) {
+	_, server := setup(t)
+	defer server.Close()
+	url := server.URL + "/nameservice/graphql"
+
+	type page struct {
+		Names struct {
+			TotalCount int `json:"totalCount"`
+			Edges      []struct {
+				Cursor string `json:"cursor"`
+				Node   struct {
+					Name string `json:"name"`
+				} `json:"node"`
+			} `json:"edges"`
+			PageInfo struct {
+				HasNextPage bool    `json:"hasNextPage"`
+				EndCursor   *string `json:"endCursor"`
+			} `json:"pageInfo"`
+		} `json:"names"`
+	}
+	query := `query($after: String) {
+		names(first: 1, after: $after) { totalCount edges { cursor node { name } } pageInfo { hasNextPage endCursor } }
+	}`
+
+	var first page
+	execute(t, url, query, nil, &first)
+	require.Equal(t, 2, first.Names.TotalCount)
+	require.Len(t, first.Names.Edges, 1)
+	require.Equal(t, "alice", first.Names.Edges[0].Node.Name)
+	require.True(t, first.Names.PageInfo.HasNextPage)
+	require.Equal(t, first.Names.Edges[0].Cursor, *first.Names.PageInfo.EndCursor)
+
+	var second page
+	execute(t, url, query, map[string]interface{}{"after": *first.Names.PageInfo.EndCursor}, &second)
+	require.Len(t, second.Names.Edges, 1)
+	require.Equal(t, "bob", second.Names.Edges[0].Node.Name)
+	require.False(t, second.Names.PageInfo.HasNextPage)
+
+	var products struct {
+		Products struct {
+			TotalCount int `json:"totalCount"`
+		} `json:"products"`
+	}
+	execute(t, url, `{ products(filter: {category: "books", minPrice: "20nametoken"}) { totalCount } }`, nil, &products)
+	require.Zero(t, products.Products.TotalCount)
+	execute(t, url, `{ products(filter: {category: "books"}) { totalCount } }`, nil, &products)
+	require.Equal(t, 2, products.Products.TotalCount)
+}
+
+func TestErrors(t *testing.T) {
+	_, server := setup(t)
+	defer server.Close()
+	url := server.URL + "/nameservice/graphql"
+
+	// names nobody owns and unknown products are null
+	var missing struct {
+		Whois   *struct{ Name string } `json:"whois"`
+		Product *struct{ ID string }   `json:"product"`
+	}
+	execute(t, url, `{ whois(name: "carol") { name } product(id: "book3") { id } }`, nil, &missing)
+	require.Nil(t, missing.Whois)
+	require.Nil(t, missing.Product)
+
+	// failed fields carry the codes of the SDK
+	for _, tc := range []struct {
+		query string
+		code  uint32
+	}{
+		{`{ account(address: "alice") { address } }`, 7},
+		{`{ names(first: 101) { totalCount } }`, 18},
+		{`{ names(after: "!") { totalCount } }`, 18},
+		{`{ products(filter: {maxPrice: "ten"}) { totalCount } }`, 10},
+	} {
+		res := execute(t, url, tc.query, nil, nil)
+		require.Len(t, res.Errors, 1, tc.query)
+		require.Equal(t, "sdk", res.Errors[0].Extensions.Codespace, tc.query)
+		require.Equal(t, tc.code, res.Errors[0].Extensions.Code, tc.query)
+	}
+
+	// a query the schema does not validate is reported without data
+	res := execute(t, url, `{ unknown }`, nil, nil)
+	require.NotEmpty(t, res.Errors)
+}
+
+func TestConnectionQueries(t *testing.T) {
+	owner, server := setup(t)
+	server.Close()
+	server, node := serveCounting(owner)
+	defer server.Close()
+	url := server.URL + "/nameservice/graphql"
+
+	// a connection is read with a single query, whatever the size of its page
+	var res struct {
+		Account struct {
+			Names struct {
+				TotalCount int `json:"totalCount"`
+				Edges      []struct {
+					Node struct {
+						Name       string `json:"name"`
+						Storefront struct {
+							TotalCount int `json:"totalCount"`
+						} `json:"storefront"`
+					} `json:"node"`
+				} `json:"edges"`
+			} `json:"names"`
+		} `json:"account"`
+	}
+	query := `query($address: String!) {
+		account(address: $address) { names { totalCount edges { node { name storefront { totalCount } } } } }
+	}`
+	execute(t, url, query, map[string]interface{}{"address": owner.CLIContext().GetFromAddress().String()}, &res)
+	require.Equal(t, 2, res.Account.Names.TotalCount)
+	require.Len(t, res.Account.Names.Edges, 2)
+	require.Equal(t, 1, res.Account.Names.Edges[0].Node.Storefront.TotalCount)
+	require.Zero(t, res.Account.Names.Edges[1].Node.Storefront.TotalCount)
+
+	// one query for the names of the account and one for its total, and one for the total
+	// of the storefront of each name, whose page is not selected
+	require.Equal(t, map[string]int{"namesPage": 2, "productsPage": 2}, node.queries)
+
+	// the total, which reads every record, is not queried unless selected
+	node.queries = map[string]int{}
+	query = `query($address: String!) {
+		account(address: $address) { names { edges { node { name } } pageInfo { hasNextPage } } }
+	}`
+	execute(t, url, query, map[string]interface{}{"address": owner.CLIContext().GetFromAddress().String()}, &res)
+	require.Len(t, res.Account.Names.Edges, 2)
+	require.Equal(t, map[string]int{"namesPage": 1}, node.queries)
+}
+
+func TestLimits(t *testing.T) {
+	owner, server := setup(t)
+	server.Close()
+	server, node := serveCounting(owner)
+	defer server.Close()
+	url := server.URL + "/nameservice/graphql"
+
+	// a request nesting selections too deep is rejected before any query runs
+	query := `{ names { edges { node { owner { names { edges { node { owner { names { edges { node {
+		owner { address } } } } } } } } } } } } }`
+	res := execute(t, url, query, nil, nil)
+	require.Len(t, res.Errors, 1)
+	require.Contains(t, res.Errors[0].Message, "exceeds max depth")
+	require.Nil(t, res.Data)
+	require.Empty(t, node.queries)
+}
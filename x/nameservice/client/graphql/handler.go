@@ -0,0 +1,100 @@
+// Package graphql serves the names, products and accounts of the nameservice module
+// over GraphQL.
+//
+// The schema is in schema.graphql. Its resolvers run the custom queries of the module
+// and of the auth module through the typed client, at the height a request reads, and
+// memoize the results for the duration of the request: the record of a name, say, is
+// queried once however many fields of the request refer to it. Failed queries are
+// reported in the errors of the response, with the codespace and code of the error in
+// its extensions.
+//
+// Lists are paginated by the querier of the module, which reads a page without the
+// records past it; the number of matching records, which reads all of them, is queried
+// only when totalCount is selected. The depth and the parallelism of requests are bounded.
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	graphql "github.com/graph-gophers/graphql-go"
+
+	clientcontext "github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/types/rest"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/client"
+)
+
+const (
+	// maxDepth bounds the nesting of the selections of a request
+	maxDepth = 12
+	// maxParallelism bounds the number of fields of a request resolved concurrently,
+	// hence of queries in flight
+	maxParallelism = 8
+)
+
+// RegisterRoutes registers the GraphQL endpoint under /<storeName>/graphql
+func RegisterRoutes(cliCtx clientcontext.CLIContext, r *mux.Router, storeName string) {
+	r.Handle(fmt.Sprintf("/%s/graphql", storeName), NewHandler(cliCtx)).Methods("POST")
+}
+
+// Handler executes GraphQL requests sent as JSON objects with a query, and optionally an
+// operation name and variables
+type Handler struct {
+	cliCtx clientcontext.CLIContext
+	schema *graphql.Schema
+}
+
+// NewHandler creates a handler querying the node of a context
+func NewHandler(cliCtx clientcontext.CLIContext) *Handler {
+	return &Handler{
+		cliCtx: cliCtx,
+		schema: graphql.MustParseSchema(schema, &queryResolver{},
+			graphql.MaxDepth(maxDepth), graphql.MaxParallelism(maxParallelism)),
+	}
+}
+
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cliCtx, ok := rest.ParseQueryHeightOrReturnBadRequest(w, h.cliCtx, r)
+	if !ok {
+		return
+	}
+
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// every field of the request reads the same block
+	c := client.NewClient(cliCtx, auth.TxBuilder{})
+	if cliCtx.Height == 0 {
+		height, err := c.LatestHeight()
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		c = c.WithHeight(height)
+	}
+
+	ctx := withLoader(r.Context(), newLoader(c))
+	res := h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+
+	bz, err := json.Marshal(res)
+	if err != nil {
+		rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(bz)
+}
@@ -0,0 +1,130 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authexported "github.com/cosmos/cosmos-sdk/x/auth/exported"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/client"
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
+
+// loader runs the queries of a request, each one once, the resolvers of the fields of a
+// request running concurrently
+type loader struct {
+	client client.Client
+
+	mtx     sync.Mutex
+	results map[string]*result
+}
+
+type result struct {
+	once  sync.Once
+	value interface{}
+	err   error
+}
+
+func newLoader(c client.Client) *loader {
+	return &loader{
+		client:  c,
+		results: make(map[string]*result),
+	}
+}
+
+type loaderKey struct{}
+
+// withLoader returns a context carrying the loader of a request
+func withLoader(ctx context.Context, l *loader) context.Context {
+	return context.WithValue(ctx, loaderKey{}, l)
+}
+
+// loaderFrom returns the loader of the request of a context
+func loaderFrom(ctx context.Context) *loader {
+	return ctx.Value(loaderKey{}).(*loader)
+}
+
+// load returns the result of the query identified by key, running it on first use
+func (l *loader) load(key string, query func() (interface{}, error)) (interface{}, error) {
+	l.mtx.Lock()
+	res, ok := l.results[key]
+	if !ok {
+		res = &result{}
+		l.results[key] = res
+	}
+	l.mtx.Unlock()
+
+	res.once.Do(func() { res.value, res.err = query() })
+	return res.value, res.err
+}
+
+// height returns the height the loader queries
+func (l *loader) height() int64 {
+	return l.client.CLIContext().Height
+}
+
+func (l *loader) whois(name string) (types.Whois, error) {
+	value, err := l.load("whois/"+name, func() (interface{}, error) {
+		return l.client.Whois(name)
+	})
+	whois, _ := value.(types.Whois)
+	return whois, err
+}
+
+// namesPage returns a page of the records of the names owned by an address, of all
+// names when owner is empty
+func (l *loader) namesPage(owner sdk.AccAddress, page types.QueryPage) (types.QueryResNamesPage, error) {
+	key := fmt.Sprintf("namesPage/%s/%s/%d/%t", owner, page.After, page.Limit, page.CountTotal)
+	value, err := l.load(key, func() (interface{}, error) {
+		return l.client.NamesPage(owner, page)
+	})
+	res, _ := value.(types.QueryResNamesPage)
+	return res, err
+}
+
+func (l *loader) product(productID string) (types.Product, error) {
+	value, err := l.load("product/"+productID, func() (interface{}, error) {
+		return l.client.Product(productID)
+	})
+	product, _ := value.(types.Product)
+	return product, err
+}
+
+func (l *loader) productsPage(params types.QueryProductsParams, page types.QueryPage) (types.QueryResProductsPage, error) {
+	bz, err := l.client.CLIContext().Codec.MarshalJSON(types.QueryProductsPageParams{Filter: params, Page: page})
+	if err != nil {
+		return types.QueryResProductsPage{}, err
+	}
+
+	value, err := l.load("productsPage/"+string(bz), func() (interface{}, error) {
+		return l.client.ProductsPage(params, page)
+	})
+	res, _ := value.(types.QueryResProductsPage)
+	return res, err
+}
+
+func (l *loader) rating(productID string) (types.QueryResRating, error) {
+	value, err := l.load("rating/"+productID, func() (interface{}, error) {
+		return l.client.Rating(productID)
+	})
+	rating, _ := value.(types.QueryResRating)
+	return rating, err
+}
+
+func (l *loader) reputation(seller sdk.AccAddress) (types.QueryResRating, error) {
+	value, err := l.load("reputation/"+seller.String(), func() (interface{}, error) {
+		return l.client.Reputation(seller)
+	})
+	rating, _ := value.(types.QueryResRating)
+	return rating, err
+}
+
+func (l *loader) account(address sdk.AccAddress) (authexported.Account, error) {
+	value, err := l.load("account/"+address.String(), func() (interface{}, error) {
+		return l.client.Account(address)
+	})
+	account, _ := value.(authexported.Account)
+	return account, err
+}
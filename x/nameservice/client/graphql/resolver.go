@@ -0,0 +1,506 @@
+package graphql
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// codedError reports the codespace and code of an error in the extensions of the
+// GraphQL error
+type codedError struct {
+	err error
+}
+
+// wrapError returns err as a codedError, nil when err is nil
+func wrapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return codedError{err: err}
+}
+
+func (e codedError) Error() string {
+	return e.err.Error()
+}
+
+func (e codedError) Extensions() map[string]interface{} {
+	codespace, code, _ := sdkerrors.ABCIInfo(e.err, false)
+	return map[string]interface{}{"codespace": codespace, "code": code}
+}
+
+// pageArgs are the arguments of paginated fields
+type pageArgs struct {
+	First *int32
+	After *string
+}
+
+// page returns the page of keys selected by the arguments
+func (args pageArgs) page() (types.QueryPage, error) {
+	page := types.QueryPage{Limit: defaultPageSize}
+	if args.First != nil {
+		if *args.First < 0 || *args.First > maxPageSize {
+			return page, sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "first must be between 0 and %d", maxPageSize)
+		}
+		page.Limit = int(*args.First)
+	}
+
+	if args.After != nil {
+		bz, err := base64.URLEncoding.DecodeString(*args.After)
+		if err != nil {
+			return page, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "invalid cursor")
+		}
+		page.After = string(bz)
+	}
+	return page, nil
+}
+
+func cursor(key string) string {
+	return base64.URLEncoding.EncodeToString([]byte(key))
+}
+
+type pageInfoResolver struct {
+	hasNextPage bool
+	endCursor   *string
+}
+
+// newPageInfo returns the info of a page ending with lastKey, if it is not empty
+func newPageInfo(hasNextPage bool, lastKey *string) *pageInfoResolver {
+	info := &pageInfoResolver{hasNextPage: hasNextPage}
+	if lastKey != nil {
+		c := cursor(*lastKey)
+		info.endCursor = &c
+	}
+	return info
+}
+
+func (r *pageInfoResolver) HasNextPage() bool {
+	return r.hasNextPage
+}
+
+func (r *pageInfoResolver) EndCursor() *string {
+	return r.endCursor
+}
+
+// queryResolver resolves the fields of the Query type
+type queryResolver struct{}
+
+func (r *queryResolver) Height(ctx context.Context) string {
+	return strconv.FormatInt(loaderFrom(ctx).height(), 10)
+}
+
+func (r *queryResolver) Whois(ctx context.Context, args struct{ Name string }) (*whoisResolver, error) {
+	whois, err := loaderFrom(ctx).whois(args.Name)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	// the querier answers names nobody owns with a record carrying the minimum price
+	if whois.Owner.Empty() {
+		return nil, nil
+	}
+	return &whoisResolver{name: args.Name, whois: whois}, nil
+}
+
+func (r *queryResolver) Names(ctx context.Context, args pageArgs) (*whoisConnectionResolver, error) {
+	return newWhoisConnection(ctx, nil, args)
+}
+
+func (r *queryResolver) Product(ctx context.Context, args struct{ ID graphql.ID }) (*productResolver, error) {
+	product, err := loaderFrom(ctx).product(string(args.ID))
+	if errors.Is(err, types.ErrProductDoesNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return &productResolver{product: product}, nil
+}
+
+// productFilter is the ProductFilter input
+type productFilter struct {
+	Category *string
+	Tag      *string
+	Owner    *string
+	MinPrice *string
+	MaxPrice *string
+	Listed   *bool
+}
+
+// params returns the parameters of the products query matching the filter
+func (f *productFilter) params() (types.QueryProductsParams, error) {
+	var params types.QueryProductsParams
+	if f == nil {
+		return params, nil
+	}
+
+	if f.Category != nil {
+		params.Category = *f.Category
+	}
+	if f.Tag != nil {
+		params.Tag = *f.Tag
+	}
+	if f.Listed != nil {
+		params.Listed = *f.Listed
+	}
+
+	if f.Owner != nil {
+		owner, err := sdk.AccAddressFromBech32(*f.Owner)
+		if err != nil {
+			return params, sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, err.Error())
+		}
+		params.Owner = owner
+	}
+
+	for _, price := range []struct {
+		value *string
+		coins *sdk.Coins
+	}{{f.MinPrice, &params.MinPrice}, {f.MaxPrice, &params.MaxPrice}} {
+		if price.value == nil {
+			continue
+		}
+		coins, err := sdk.ParseCoins(*price.value)
+		if err != nil {
+			return params, sdkerrors.Wrap(sdkerrors.ErrInvalidCoins, err.Error())
+		}
+		*price.coins = coins
+	}
+	return params, nil
+}
+
+func (r *queryResolver) Products(ctx context.Context, args struct {
+	Filter *productFilter
+	First  *int32
+	After  *string
+}) (*productConnectionResolver, error) {
+	params, err := args.Filter.params()
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	return newProductConnection(ctx, params, pageArgs{First: args.First, After: args.After})
+}
+
+func (r *queryResolver) Account(args struct{ Address string }) (*accountResolver, error) {
+	address, err := sdk.AccAddressFromBech32(args.Address)
+	if err != nil {
+		return nil, wrapError(sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, err.Error()))
+	}
+	return &accountResolver{address: address}, nil
+}
+
+// accountResolver resolves the fields of the Account type
+type accountResolver struct {
+	address sdk.AccAddress
+}
+
+func (r *accountResolver) Address() string {
+	return r.address.String()
+}
+
+func (r *accountResolver) Coins(ctx context.Context) ([]*coinResolver, error) {
+	account, err := loaderFrom(ctx).account(r.address)
+	if errors.Is(err, sdkerrors.ErrUnknownAddress) {
+		return []*coinResolver{}, nil
+	}
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return newCoins(account.GetCoins()), nil
+}
+
+func (r *accountResolver) Names(ctx context.Context, args pageArgs) (*whoisConnectionResolver, error) {
+	return newWhoisConnection(ctx, r.address, args)
+}
+
+func (r *accountResolver) Products(ctx context.Context, args pageArgs) (*productConnectionResolver, error) {
+	return newProductConnection(ctx, types.QueryProductsParams{Owner: r.address}, args)
+}
+
+func (r *accountResolver) Reputation(ctx context.Context) (*ratingResolver, error) {
+	rating, err := loaderFrom(ctx).reputation(r.address)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return &ratingResolver{rating: rating}, nil
+}
+
+// whoisResolver resolves the fields of the Whois type
+type whoisResolver struct {
+	name  string
+	whois types.Whois
+}
+
+func (r *whoisResolver) Name() string {
+	return r.name
+}
+
+func (r *whoisResolver) Value() string {
+	return r.whois.Value
+}
+
+func (r *whoisResolver) Owner() *accountResolver {
+	return &accountResolver{address: r.whois.Owner}
+}
+
+func (r *whoisResolver) Price() []*coinResolver {
+	return newCoins(r.whois.Price)
+}
+
+func (r *whoisResolver) StorefrontIncluded() bool {
+	return r.whois.StorefrontIncluded
+}
+
+func (r *whoisResolver) Storefront(ctx context.Context, args pageArgs) (*productConnectionResolver, error) {
+	return newProductConnection(ctx, types.QueryProductsParams{Storefront: r.name}, args)
+}
+
+// productResolver resolves the fields of the Product type
+type productResolver struct {
+	product types.Product
+}
+
+func (r *productResolver) ID() graphql.ID {
+	return graphql.ID(r.product.ProductID)
+}
+
+func (r *productResolver) Description() string {
+	return r.product.Description
+}
+
+func (r *productResolver) Owner() *accountResolver {
+	return &accountResolver{address: r.product.Owner}
+}
+
+func (r *productResolver) Price() []*coinResolver {
+	return newCoins(r.product.Price)
+}
+
+func (r *productResolver) AcceptedPrices() []*coinResolver {
+	return newCoins(r.product.AcceptedPrices)
+}
+
+func (r *productResolver) Listed() bool {
+	return r.product.Listed
+}
+
+func (r *productResolver) Category() string {
+	return r.product.Category
+}
+
+func (r *productResolver) Tags() []string {
+	if r.product.Tags == nil {
+		return []string{}
+	}
+	return r.product.Tags
+}
+
+func (r *productResolver) Storefront(ctx context.Context) (*whoisResolver, error) {
+	if r.product.Storefront == "" {
+		return nil, nil
+	}
+
+	whois, err := loaderFrom(ctx).whois(r.product.Storefront)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return &whoisResolver{name: r.product.Storefront, whois: whois}, nil
+}
+
+func (r *productResolver) SubscriptionPeriod() string {
+	return strconv.FormatInt(r.product.SubscriptionPeriod, 10)
+}
+
+func (r *productResolver) Licensing() bool {
+	return r.product.Licensing
+}
+
+func (r *productResolver) LicenseDuration() string {
+	return strconv.FormatInt(r.product.LicenseDuration, 10)
+}
+
+func (r *productResolver) Version() string {
+	return strconv.FormatUint(r.product.Version, 10)
+}
+
+func (r *productResolver) Rating(ctx context.Context) (*ratingResolver, error) {
+	rating, err := loaderFrom(ctx).rating(r.product.ProductID)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return &ratingResolver{rating: rating}, nil
+}
+
+// coinResolver resolves the fields of the Coin type
+type coinResolver struct {
+	coin sdk.Coin
+}
+
+func newCoins(coins sdk.Coins) []*coinResolver {
+	resolvers := make([]*coinResolver, len(coins))
+	for i, coin := range coins {
+		resolvers[i] = &coinResolver{coin: coin}
+	}
+	return resolvers
+}
+
+func (r *coinResolver) Denom() string {
+	return r.coin.Denom
+}
+
+func (r *coinResolver) Amount() string {
+	return r.coin.Amount.String()
+}
+
+// ratingResolver resolves the fields of the Rating type
+type ratingResolver struct {
+	rating types.QueryResRating
+}
+
+func (r *ratingResolver) Count() string {
+	return strconv.FormatUint(r.rating.Count, 10)
+}
+
+func (r *ratingResolver) Average() string {
+	return r.rating.Average.String()
+}
+
+// whoisConnectionResolver resolves a page of names, sorted. The page and the total are
+// queried only when selected, the total by a query of its own as it reads every name.
+type whoisConnectionResolver struct {
+	owner sdk.AccAddress
+	page  types.QueryPage
+}
+
+// newWhoisConnection selects the page of the names owned by an address, of all names
+// when owner is empty, selected by the arguments
+func newWhoisConnection(ctx context.Context, owner sdk.AccAddress, args pageArgs) (*whoisConnectionResolver, error) {
+	page, err := args.page()
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return &whoisConnectionResolver{owner: owner, page: page}, nil
+}
+
+func (r *whoisConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	res, err := loaderFrom(ctx).namesPage(r.owner, types.QueryPage{CountTotal: true})
+	if err != nil {
+		return 0, wrapError(err)
+	}
+	return int32(res.Total), nil
+}
+
+func (r *whoisConnectionResolver) Edges(ctx context.Context) ([]*whoisEdgeResolver, error) {
+	res, err := loaderFrom(ctx).namesPage(r.owner, r.page)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	edges := make([]*whoisEdgeResolver, len(res.Names))
+	for i, named := range res.Names {
+		edges[i] = &whoisEdgeResolver{node: &whoisResolver{name: named.Name, whois: named.Whois}}
+	}
+	return edges, nil
+}
+
+func (r *whoisConnectionResolver) PageInfo(ctx context.Context) (*pageInfoResolver, error) {
+	res, err := loaderFrom(ctx).namesPage(r.owner, r.page)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	var lastKey *string
+	if n := len(res.Names); n > 0 {
+		lastKey = &res.Names[n-1].Name
+	}
+	return newPageInfo(res.HasNext, lastKey), nil
+}
+
+type whoisEdgeResolver struct {
+	node *whoisResolver
+}
+
+func (r *whoisEdgeResolver) Cursor() string {
+	return cursor(r.node.name)
+}
+
+func (r *whoisEdgeResolver) Node() *whoisResolver {
+	return r.node
+}
+
+// productConnectionResolver resolves a page of products, sorted by ID. The page and the
+// total are queried only when selected, the total by a query of its own as it reads
+// every matching product.
+type productConnectionResolver struct {
+	params types.QueryProductsParams
+	page   types.QueryPage
+}
+
+// newProductConnection selects the page of the products matching params selected by
+// the arguments
+func newProductConnection(ctx context.Context, params types.QueryProductsParams, args pageArgs) (*productConnectionResolver, error) {
+	page, err := args.page()
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return &productConnectionResolver{params: params, page: page}, nil
+}
+
+func (r *productConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	res, err := loaderFrom(ctx).productsPage(r.params, types.QueryPage{CountTotal: true})
+	if err != nil {
+		return 0, wrapError(err)
+	}
+	return int32(res.Total), nil
+}
+
+func (r *productConnectionResolver) Edges(ctx context.Context) ([]*productEdgeResolver, error) {
+	res, err := loaderFrom(ctx).productsPage(r.params, r.page)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	edges := make([]*productEdgeResolver, len(res.Products))
+	for i, product := range res.Products {
+		edges[i] = &productEdgeResolver{node: &productResolver{product: product}}
+	}
+	return edges, nil
+}
+
+func (r *productConnectionResolver) PageInfo(ctx context.Context) (*pageInfoResolver, error) {
+	res, err := loaderFrom(ctx).productsPage(r.params, r.page)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	var lastKey *string
+	if n := len(res.Products); n > 0 {
+		lastKey = &res.Products[n-1].ProductID
+	}
+	return newPageInfo(res.HasNext, lastKey), nil
+}
+
+type productEdgeResolver struct {
+	node *productResolver
+}
+
+func (r *productEdgeResolver) Cursor() string {
+	return cursor(r.node.product.ProductID)
+}
+
+func (r *productEdgeResolver) Node() *productResolver {
+	return r.node
+}
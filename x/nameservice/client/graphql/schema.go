@@ -0,0 +1,114 @@
+package graphql
+
+// schema is the content of schema.graphql, the schema of the endpoint shipped to the
+// clients of the module. TestSchemaFile keeps both in sync.
+const schema = `# Schema of the GraphQL endpoint of the nameservice module.
+#
+# Every request reads the state of a single block, the latest one unless the URL sets
+# the height query parameter. 64-bit integers and amounts are strings, as in the JSON
+# of the REST routes. Lists are paginated with connections: first is the size of a
+# page, 20 by default and 100 at most, and after the end cursor of the previous page.
+# Selections nest at most 12 levels deep.
+
+schema {
+  query: Query
+}
+
+type Query {
+  # height of the block the request reads
+  height: String!
+  # whois returns the record of a name, null when nobody owns it
+  whois(name: String!): Whois
+  names(first: Int, after: String): WhoisConnection!
+  # product returns a product, null when it does not exist
+  product(id: ID!): Product
+  products(filter: ProductFilter, first: Int, after: String): ProductConnection!
+  # account returns the account of a bech32 address
+  account(address: String!): Account!
+}
+
+# Account holds the coins of an address along with the names and products it owns
+type Account {
+  address: String!
+  coins: [Coin!]!
+  names(first: Int, after: String): WhoisConnection!
+  products(first: Int, after: String): ProductConnection!
+  # reputation aggregates the reviews of the products the account sold
+  reputation: Rating!
+}
+
+type Whois {
+  name: String!
+  value: String!
+  owner: Account!
+  price: [Coin!]!
+  storefrontIncluded: Boolean!
+  # storefront lists the products published under the name
+  storefront(first: Int, after: String): ProductConnection!
+}
+
+type Product {
+  id: ID!
+  description: String!
+  owner: Account!
+  price: [Coin!]!
+  acceptedPrices: [Coin!]!
+  listed: Boolean!
+  category: String!
+  tags: [String!]!
+  # storefront is the name the product is published under, if any
+  storefront: Whois
+  subscriptionPeriod: String!
+  licensing: Boolean!
+  licenseDuration: String!
+  version: String!
+  rating: Rating!
+}
+
+# ProductFilter restricts products to the ones matching all of its fields
+input ProductFilter {
+  category: String
+  tag: String
+  owner: String
+  minPrice: String
+  maxPrice: String
+  listed: Boolean
+}
+
+type Coin {
+  denom: String!
+  amount: String!
+}
+
+type Rating {
+  count: String!
+  average: String!
+}
+
+type PageInfo {
+  hasNextPage: Boolean!
+  endCursor: String
+}
+
+type WhoisConnection {
+  totalCount: Int!
+  edges: [WhoisEdge!]!
+  pageInfo: PageInfo!
+}
+
+type WhoisEdge {
+  cursor: String!
+  node: Whois!
+}
+
+type ProductConnection {
+  totalCount: Int!
+  edges: [ProductEdge!]!
+  pageInfo: PageInfo!
+}
+
+type ProductEdge {
+  cursor: String!
+  node: Product!
+}
+`
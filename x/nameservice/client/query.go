@@ -0,0 +1,201 @@
+package client
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	authexported "github.com/cosmos/cosmos-sdk/x/auth/exported"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/keeper"
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
+
+// Resolve returns the value a name resolves to
+func (c Client) Resolve(name string) (string, error) {
+	var res types.QueryResResolve
+	if err := c.query(&res, nil, keeper.QueryResolve, name); err != nil {
+		return "", err
+	}
+	return res.Value, nil
+}
+
+// Whois returns the record of a name, which carries the minimum price of names nobody owns
+func (c Client) Whois(name string) (types.Whois, error) {
+	var whois types.Whois
+	err := c.query(&whois, nil, keeper.QueryWhois, name)
+	return whois, err
+}
+
+// Names returns all names
+func (c Client) Names() ([]string, error) {
+	var names types.QueryResNames
+	err := c.query(&names, nil, keeper.QueryNames)
+	return names, err
+}
+
+// NamesPage returns a page of the records of the names owned by an address, of all
+// names when owner is empty
+func (c Client) NamesPage(owner sdk.AccAddress, page types.QueryPage) (types.QueryResNamesPage, error) {
+	var res types.QueryResNamesPage
+	bz, err := c.cliCtx.Codec.MarshalJSON(types.QueryNamesPageParams{Owner: owner, Page: page})
+	if err != nil {
+		return res, err
+	}
+
+	err = c.query(&res, bz, keeper.QueryNamesPage)
+	return res, err
+}
+
+// Product returns a product
+func (c Client) Product(productID string) (types.Product, error) {
+	var product types.Product
+	if err := c.query(&product, nil, keeper.QueryProduct, productID); err != nil {
+		return product, err
+	}
+
+	// the querier answers unknown products with an empty one
+	if product.ProductID == "" {
+		return product, sdkerrors.Wrap(types.ErrProductDoesNotExist, productID)
+	}
+	return product, nil
+}
+
+// AllProducts returns all products, or only the listed ones
+func (c Client) AllProducts(listedOnly bool) ([]types.Product, error) {
+	path := []interface{}{keeper.QueryAllProducts}
+	if listedOnly {
+		path = append(path, keeper.QueryListedFilter)
+	}
+
+	var products types.QueryResAllProducts
+	err := c.query(&products, nil, path...)
+	return products, err
+}
+
+// Products returns the products matching the filters of params
+func (c Client) Products(params types.QueryProductsParams) ([]types.Product, error) {
+	bz, err := c.cliCtx.Codec.MarshalJSON(params)
+	if err != nil {
+		return nil, err
+	}
+
+	var products types.QueryResAllProducts
+	err = c.query(&products, bz, keeper.QueryProducts)
+	return products, err
+}
+
+// ProductsPage returns a page of the products matching the filters of params
+func (c Client) ProductsPage(params types.QueryProductsParams, page types.QueryPage) (types.QueryResProductsPage, error) {
+	var res types.QueryResProductsPage
+	bz, err := c.cliCtx.Codec.MarshalJSON(types.QueryProductsPageParams{Filter: params, Page: page})
+	if err != nil {
+		return res, err
+	}
+
+	err = c.query(&res, bz, keeper.QueryProductsPage)
+	return res, err
+}
+
+// Auction returns the auction of a product
+func (c Client) Auction(productID string) (types.Auction, error) {
+	var auction types.Auction
+	err := c.query(&auction, nil, keeper.QueryAuction, productID)
+	return auction, err
+}
+
+// Auctions returns all auctions
+func (c Client) Auctions() ([]types.Auction, error) {
+	var auctions types.QueryResAuctions
+	err := c.query(&auctions, nil, keeper.QueryAuctions)
+	return auctions, err
+}
+
+// Bids returns the bids of the auction of a product
+func (c Client) Bids(productID string) ([]types.Bid, error) {
+	var bids types.QueryResBids
+	err := c.query(&bids, nil, keeper.QueryBids, productID)
+	return bids, err
+}
+
+// Storefront returns the products published under a name
+func (c Client) Storefront(name string) ([]types.Product, error) {
+	var products types.QueryResAllProducts
+	err := c.query(&products, nil, keeper.QueryStorefront, name)
+	return products, err
+}
+
+// ResolveProduct returns the product published at name/productID
+func (c Client) ResolveProduct(name, productID string) (types.Product, error) {
+	var product types.Product
+	err := c.query(&product, nil, keeper.QueryResolveProduct, name, productID)
+	return product, err
+}
+
+// Reviews returns the reviews of a product
+func (c Client) Reviews(productID string) ([]types.Review, error) {
+	var reviews types.QueryResReviews
+	err := c.query(&reviews, nil, keeper.QueryReviews, productID)
+	return reviews, err
+}
+
+// Rating returns the aggregated rating of a product
+func (c Client) Rating(productID string) (types.QueryResRating, error) {
+	var rating types.QueryResRating
+	err := c.query(&rating, nil, keeper.QueryRating, productID)
+	return rating, err
+}
+
+// Reputation returns the aggregated rating of a seller
+func (c Client) Reputation(seller sdk.AccAddress) (types.QueryResRating, error) {
+	var rating types.QueryResRating
+	err := c.query(&rating, nil, keeper.QueryReputation, seller)
+	return rating, err
+}
+
+// Coupon returns a coupon by its code
+func (c Client) Coupon(code string) (types.Coupon, error) {
+	var coupon types.Coupon
+	err := c.query(&coupon, nil, keeper.QueryCoupon, types.HashCouponCode(code))
+	return coupon, err
+}
+
+// Subscription returns the subscription of an address to a product and whether it is active
+func (c Client) Subscription(productID string, subscriber sdk.AccAddress) (types.QueryResSubscriptionStatus, error) {
+	var status types.QueryResSubscriptionStatus
+	err := c.query(&status, nil, keeper.QuerySubscription, productID, subscriber)
+	return status, err
+}
+
+// Subscriptions returns the subscriptions to a product
+func (c Client) Subscriptions(productID string) ([]types.Subscription, error) {
+	var subscriptions types.QueryResSubscriptions
+	err := c.query(&subscriptions, nil, keeper.QuerySubscriptions, productID)
+	return subscriptions, err
+}
+
+// ProductHistory returns the price and description changes of a product
+func (c Client) ProductHistory(productID string) ([]types.ProductHistoryEntry, error) {
+	var history types.QueryResProductHistory
+	err := c.query(&history, nil, keeper.QueryProductHistory, productID)
+	return history, err
+}
+
+// HasLicense returns whether an address holds a valid license of a product
+func (c Client) HasLicense(productID string, holder sdk.AccAddress) (types.QueryResHasLicense, error) {
+	var res types.QueryResHasLicense
+	err := c.query(&res, nil, keeper.QueryHasLicense, productID, holder)
+	return res, err
+}
+
+// Account returns the account of an address from the auth module, the querier of which
+// fails with sdkerrors.ErrUnknownAddress for addresses without an account
+func (c Client) Account(address sdk.AccAddress) (authexported.Account, error) {
+	bz, err := c.cliCtx.Codec.MarshalJSON(auth.NewQueryAccountParams(address))
+	if err != nil {
+		return nil, err
+	}
+
+	var account authexported.Account
+	err = c.queryCustom(auth.QuerierRoute, &account, bz, auth.QueryAccount)
+	return account, err
+}
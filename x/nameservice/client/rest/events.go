@@ -0,0 +1,483 @@
+package rest
+
+import (
+	gocontext "context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	abci "github.com/tendermint/tendermint/abci/types"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/rest"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
+
+const (
+	// eventsSubscriber is the name the REST server subscribes to the blocks of the node with
+	eventsSubscriber = "nameservice-rest-events"
+	eventsQuery      = "tm.event='NewBlock'"
+
+	// endBlockAction is the action of the changes made at the end of a block, such as
+	// the settlement of an auction
+	endBlockAction = "end_block"
+
+	// listenerCapacity is the number of notifications a client may lag behind before it is dropped
+	listenerCapacity = 256
+	// maxReplayHeights is the number of blocks a client may replay
+	maxReplayHeights = 10000
+	writeTimeout     = 10 * time.Second
+)
+
+// changeNotification tells that a transaction, or the end of a block, changed a name or
+// a product. Owner is the owner after the change, PreviousOwner the one before it when
+// the change transferred the name or the product, e.g. after a purchase.
+type changeNotification struct {
+	Height        int64          `json:"height"`
+	TxHash        string         `json:"txhash,omitempty"`
+	Action        string         `json:"action"`
+	Name          string         `json:"name,omitempty"`
+	ProductID     string         `json:"product_id,omitempty"`
+	Owner         sdk.AccAddress `json:"owner,omitempty"`
+	PreviousOwner sdk.AccAddress `json:"previous_owner,omitempty"`
+}
+
+// changeFilter selects notifications, empty fields match every notification
+type changeFilter struct {
+	Name      string
+	ProductID string
+	Owner     sdk.AccAddress
+}
+
+func (f changeFilter) matches(n changeNotification) bool {
+	if f.Name != "" && n.Name != f.Name {
+		return false
+	}
+	if f.ProductID != "" && n.ProductID != f.ProductID {
+		return false
+	}
+	if !f.Owner.Empty() && !n.Owner.Equals(f.Owner) && !n.PreviousOwner.Equals(f.Owner) {
+		return false
+	}
+	return true
+}
+
+// blockChanges returns the changes made by the transactions of a block, then by the
+// end of the block
+func blockChanges(node rpcclient.Client, block *tmtypes.Block) ([]changeNotification, error) {
+	height := block.Height
+	results, err := node.BlockResults(&height)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []changeNotification
+	for i, result := range results.TxsResults {
+		if i >= len(block.Txs) {
+			break
+		}
+		changes = append(changes, txChanges(height, block.Txs[i], result)...)
+	}
+
+	for _, change := range eventChanges(results.EndBlockEvents, endBlockAction) {
+		change.Height = height
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// txChanges returns the changes made by the messages of a transaction, failed
+// transactions change nothing
+func txChanges(height int64, tx tmtypes.Tx, result *abci.ResponseDeliverTx) []changeNotification {
+	if result == nil || result.Code != abci.CodeTypeOK {
+		return nil
+	}
+
+	changes := eventChanges(result.Events, "")
+	for i := range changes {
+		changes[i].Height = height
+		changes[i].TxHash = fmt.Sprintf("%X", tx.Hash())
+	}
+	return changes
+}
+
+// eventChanges returns the changes told by the name_change and product_change events
+// of the module. Each message of a transaction is preceded by a message event with its
+// action, the changes made outside of a message have the default action. A record
+// written several times by a message is notified once.
+func eventChanges(events []abci.Event, defaultAction string) []changeNotification {
+	var changes []changeNotification
+	seen := make(map[string]bool)
+
+	action := defaultAction
+	for _, event := range events {
+		attributes := make(map[string]string)
+		for _, attribute := range event.Attributes {
+			attributes[string(attribute.Key)] = string(attribute.Value)
+		}
+
+		var change changeNotification
+		switch event.Type {
+		case sdk.EventTypeMessage:
+			if a, ok := attributes[sdk.AttributeKeyAction]; ok {
+				action = a
+				seen = make(map[string]bool)
+			}
+			continue
+		case types.EventTypeNameChange:
+			change.Name = attributes[types.AttributeKeyName]
+		case types.EventTypeProductChange:
+			change.ProductID = attributes[types.AttributeKeyProductID]
+			change.Name = attributes[types.AttributeKeyStorefront]
+		default:
+			continue
+		}
+
+		change.Action = action
+		if owner, ok := attributes[types.AttributeKeyOwner]; ok {
+			change.Owner, _ = sdk.AccAddressFromBech32(owner)
+		}
+		if previousOwner, ok := attributes[types.AttributeKeyPreviousOwner]; ok {
+			change.PreviousOwner, _ = sdk.AccAddressFromBech32(previousOwner)
+		}
+
+		key := strings.Join([]string{event.Type, change.Name, change.ProductID,
+			change.Owner.String(), change.PreviousOwner.String()}, "/")
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// eventHub fans the blocks of a single subscription to the node out to the WebSocket
+// clients, the subscription is opened along with the first client and closed along
+// with the last one
+type eventHub struct {
+	cliCtx context.CLIContext
+
+	mtx       sync.Mutex
+	listeners map[chan changeNotification]struct{}
+	// quit is closed to stop the current subscription, nil when there is none
+	quit chan struct{}
+}
+
+func newEventHub(cliCtx context.CLIContext) *eventHub {
+	return &eventHub{
+		cliCtx:    cliCtx,
+		listeners: make(map[chan changeNotification]struct{}),
+	}
+}
+
+// subscribe returns a channel receiving the changes of the blocks committed from now
+// on. The channel is closed when the client lags too far behind.
+func (h *eventHub) subscribe() (chan changeNotification, error) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	if h.quit == nil {
+		node, err := h.cliCtx.GetNode()
+		if err != nil {
+			return nil, err
+		}
+		if !node.IsRunning() {
+			if err := node.Start(); err != nil {
+				return nil, err
+			}
+		}
+
+		events, err := node.Subscribe(gocontext.Background(), eventsSubscriber, eventsQuery, listenerCapacity)
+		if err != nil {
+			return nil, err
+		}
+
+		h.quit = make(chan struct{})
+		go h.run(node, events, h.quit)
+	}
+
+	listener := make(chan changeNotification, listenerCapacity)
+	h.listeners[listener] = struct{}{}
+	return listener, nil
+}
+
+// unsubscribe stops sending changes to a channel
+func (h *eventHub) unsubscribe(listener chan changeNotification) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	h.remove(listener)
+}
+
+// remove closes a listener and releases the subscription to the node along with the
+// last listener. The hub must be locked.
+func (h *eventHub) remove(listener chan changeNotification) {
+	if _, ok := h.listeners[listener]; !ok {
+		return
+	}
+	delete(h.listeners, listener)
+	close(listener)
+
+	if len(h.listeners) == 0 && h.quit != nil {
+		close(h.quit)
+		h.quit = nil
+
+		if node, err := h.cliCtx.GetNode(); err == nil {
+			_ = node.Unsubscribe(gocontext.Background(), eventsSubscriber, eventsQuery)
+		}
+	}
+}
+
+// run sends the changes of the blocks of a subscription to the listeners, until the
+// subscription is released or ends
+func (h *eventHub) run(node rpcclient.Client, events <-chan ctypes.ResultEvent, quit chan struct{}) {
+	for {
+		var event ctypes.ResultEvent
+		select {
+		case <-quit:
+			return
+		case e, ok := <-events:
+			if !ok {
+				h.end(quit)
+				return
+			}
+			event = e
+		}
+
+		data, ok := event.Data.(tmtypes.EventDataNewBlock)
+		if !ok || data.Block == nil {
+			continue
+		}
+
+		changes, err := blockChanges(node, data.Block)
+		if err != nil {
+			// listeners would miss the changes of the block, they resume from it
+			h.end(quit)
+			return
+		}
+		if len(changes) == 0 {
+			continue
+		}
+
+		h.mtx.Lock()
+		if h.quit != quit {
+			h.mtx.Unlock()
+			return
+		}
+		for listener := range h.listeners {
+			if !send(listener, changes) {
+				h.remove(listener)
+			}
+		}
+		h.mtx.Unlock()
+	}
+}
+
+// end closes the listeners of a subscription which ended
+func (h *eventHub) end(quit chan struct{}) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	if h.quit != quit {
+		return
+	}
+	// the last listener releases the subscription
+	for listener := range h.listeners {
+		h.remove(listener)
+	}
+	if h.quit == quit {
+		close(quit)
+		h.quit = nil
+	}
+}
+
+// send sends changes to a listener unless it lags too far behind
+func send(listener chan changeNotification, changes []changeNotification) bool {
+	for _, change := range changes {
+		select {
+		case listener <- change:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// replay calls fn with the changes of the blocks committed since a height, and returns
+// the height of the last block replayed
+func (h *eventHub) replay(fromHeight int64, fn func(changeNotification) error) (int64, error) {
+	node, err := h.cliCtx.GetNode()
+	if err != nil {
+		return 0, err
+	}
+
+	status, err := node.Status()
+	if err != nil {
+		return 0, err
+	}
+	latest := status.SyncInfo.LatestBlockHeight
+	if latest-fromHeight >= maxReplayHeights {
+		return 0, fmt.Errorf("from_height %d is more than %d blocks behind", fromHeight, maxReplayHeights)
+	}
+
+	for height := fromHeight; height <= latest; height++ {
+		h := height
+		block, err := node.Block(&h)
+		if err != nil {
+			return 0, err
+		}
+
+		changes, err := blockChanges(node, block.Block)
+		if err != nil {
+			// the results of the latest block are saved after it, its changes are
+			// then sent to the listener
+			if height == latest {
+				return height - 1, nil
+			}
+			return 0, err
+		}
+
+		for _, change := range changes {
+			if err := fn(change); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return latest, nil
+}
+
+// upgrader accepts the WebSockets of clients that are not browsers, which send no
+// origin, and of pages served from the host of the REST server itself, so that other
+// sites cannot stream on behalf of their visitors
+var upgrader = websocket.Upgrader{CheckOrigin: checkOrigin}
+
+func checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+// eventsHandler streams the changes of names and products over a WebSocket as JSON
+// messages. The name, owner and product_id query parameters filter the changes, an
+// owner matching both the owner and the previous owner of a change. The from_height
+// parameter first replays the changes committed since a height, so that a client
+// reconnecting from the height of the last change it received misses nothing, it then
+// skips the changes of that height it already received.
+func eventsHandler(cliCtx context.CLIContext, hub *eventHub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		filter := changeFilter{Name: query.Get("name"), ProductID: query.Get("product_id")}
+
+		if ownerStr := query.Get("owner"); ownerStr != "" {
+			owner, err := sdk.AccAddressFromBech32(ownerStr)
+			if err != nil {
+				rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			filter.Owner = owner
+		}
+
+		var fromHeight int64
+		if fromStr := query.Get("from_height"); fromStr != "" {
+			height, err := strconv.ParseInt(fromStr, 10, 64)
+			if err != nil || height < 1 {
+				rest.WriteErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("invalid from_height %s", fromStr))
+				return
+			}
+			fromHeight = height
+		}
+
+		listener, err := hub.subscribe()
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadGateway, err.Error())
+			return
+		}
+		defer hub.unsubscribe(listener)
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		// the deadline of the request would end the stream
+		_ = conn.SetReadDeadline(time.Time{})
+
+		// clients only send control messages, reading handles them and notices the close
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			for {
+				if _, _, err := conn.NextReader(); err != nil {
+					return
+				}
+			}
+		}()
+
+		write := func(change changeNotification) error {
+			if !filter.matches(change) {
+				return nil
+			}
+
+			bz, err := cliCtx.Codec.MarshalJSON(change)
+			if err != nil {
+				return err
+			}
+			_ = conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			return conn.WriteMessage(websocket.TextMessage, bz)
+		}
+
+		closeWith := func(code int, text string) {
+			msg := websocket.FormatCloseMessage(code, text)
+			_ = conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(writeTimeout))
+		}
+
+		// blocks committed during the replay arrive on the listener too, they are
+		// skipped up to the last block replayed
+		var lastHeight int64
+		if fromHeight > 0 {
+			lastHeight, err = hub.replay(fromHeight, write)
+			if err != nil {
+				closeWith(websocket.CloseInternalServerErr, err.Error())
+				return
+			}
+		}
+
+		for {
+			select {
+			case <-closed:
+				return
+
+			case change, ok := <-listener:
+				if !ok {
+					closeWith(websocket.CloseTryAgainLater, "changes were dropped, resume from the height of the last change received")
+					return
+				}
+				if change.Height <= lastHeight {
+					continue
+				}
+				if err := write(change); err != nil {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package rest
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
+
+var (
+	alice = sdk.AccAddress([]byte("alice_______________"))
+	bob   = sdk.AccAddress([]byte("bob_________________"))
+)
+
+func messageEvent(action string) sdk.Event {
+	return sdk.NewEvent(sdk.EventTypeMessage, sdk.NewAttribute(sdk.AttributeKeyAction, action))
+}
+
+func nameChange(name string, owner, previousOwner sdk.AccAddress) sdk.Event {
+	event := sdk.NewEvent(types.EventTypeNameChange, sdk.NewAttribute(types.AttributeKeyName, name))
+	return withOwners(event, owner, previousOwner)
+}
+
+func productChange(productID, storefront string, owner, previousOwner sdk.AccAddress) sdk.Event {
+	event := sdk.NewEvent(types.EventTypeProductChange, sdk.NewAttribute(types.AttributeKeyProductID, productID))
+	if storefront != "" {
+		event = event.AppendAttributes(sdk.NewAttribute(types.AttributeKeyStorefront, storefront))
+	}
+	return withOwners(event, owner, previousOwner)
+}
+
+func withOwners(event sdk.Event, owner, previousOwner sdk.AccAddress) sdk.Event {
+	if !owner.Empty() {
+		event = event.AppendAttributes(sdk.NewAttribute(types.AttributeKeyOwner, owner.String()))
+	}
+	if !previousOwner.Empty() {
+		event = event.AppendAttributes(sdk.NewAttribute(types.AttributeKeyPreviousOwner, previousOwner.String()))
+	}
+	return event
+}
+
+func TestEventChanges(t *testing.T) {
+	events := sdk.Events{
+		messageEvent("buy_name"),
+		sdk.NewEvent("transfer", sdk.NewAttribute("recipient", alice.String())),
+		nameChange("alice", bob, alice),
+		productChange("book1", "alice", bob, alice),
+		// a record written twice by a message is notified once
+		productChange("book1", "alice", bob, alice),
+		messageEvent("delete_product"),
+		productChange("book1", "alice", nil, bob),
+	}.ToABCIEvents()
+
+	require.Equal(t, []changeNotification{
+		{Action: "buy_name", Name: "alice", Owner: bob, PreviousOwner: alice},
+		{Action: "buy_name", Name: "alice", ProductID: "book1", Owner: bob, PreviousOwner: alice},
+		{Action: "delete_product", Name: "alice", ProductID: "book1", PreviousOwner: bob},
+	}, eventChanges(events, ""))
+
+	// changes outside of a message have the default action
+	events = sdk.Events{productChange("album1", "", bob, alice)}.ToABCIEvents()
+	require.Equal(t, []changeNotification{
+		{Action: endBlockAction, ProductID: "album1", Owner: bob, PreviousOwner: alice},
+	}, eventChanges(events, endBlockAction))
+
+	require.Empty(t, eventChanges(nil, ""))
+}
+
+func TestTxChanges(t *testing.T) {
+	tx := tmtypes.Tx("tx")
+	events := sdk.Events{messageEvent("set_name"), nameChange("alice", alice, nil)}.ToABCIEvents()
+
+	changes := txChanges(7, tx, &abci.ResponseDeliverTx{Events: events})
+	require.Equal(t, []changeNotification{
+		{Height: 7, TxHash: fmt.Sprintf("%X", tx.Hash()), Action: "set_name", Name: "alice", Owner: alice},
+	}, changes)
+
+	// failed transactions change nothing
+	require.Empty(t, txChanges(7, tx, &abci.ResponseDeliverTx{Code: 5, Events: events}))
+	require.Empty(t, txChanges(7, tx, nil))
+}
+
+func TestChangeFilterMatches(t *testing.T) {
+	sale := changeNotification{Action: "buy_product", Name: "alice", ProductID: "book1", Owner: bob, PreviousOwner: alice}
+	carol := sdk.AccAddress([]byte("carol_______________"))
+
+	tests := []struct {
+		name    string
+		filter  changeFilter
+		matches bool
+	}{
+		{"empty filter", changeFilter{}, true},
+		{"name", changeFilter{Name: "alice"}, true},
+		{"other name", changeFilter{Name: "bob"}, false},
+		{"product", changeFilter{ProductID: "book1"}, true},
+		{"other product", changeFilter{ProductID: "book2"}, false},
+		{"new owner", changeFilter{Owner: bob}, true},
+		{"previous owner", changeFilter{Owner: alice}, true},
+		{"other owner", changeFilter{Owner: carol}, false},
+		{"all fields", changeFilter{Name: "alice", ProductID: "book1", Owner: alice}, true},
+		{"one field differs", changeFilter{Name: "alice", ProductID: "book2", Owner: alice}, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.matches, tc.filter.matches(sale))
+		})
+	}
+}
+
+func TestCheckOrigin(t *testing.T) {
+	tests := []struct {
+		name   string
+		origin string
+		ok     bool
+	}{
+		{"no origin", "", true},
+		{"same host", "http://localhost:1317", true},
+		{"other host", "http://example.com", false},
+		{"other port", "http://localhost:8080", false},
+		{"invalid origin", "://", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "http://localhost:1317/nameservice/events", nil)
+			if tc.origin != "" {
+				r.Header.Set("Origin", tc.origin)
+			}
+			require.Equal(t, tc.ok, checkOrigin(r))
+		})
+	}
+}
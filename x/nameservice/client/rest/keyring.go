@@ -0,0 +1,106 @@
+package rest
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/tendermint/crypto/bcrypt"
+	tmcrypto "github.com/tendermint/tendermint/crypto"
+
+	"github.com/cosmos/cosmos-sdk/client/input"
+	"github.com/cosmos/cosmos-sdk/crypto/keys"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// FlagUnsafeKeyringSigning lets the REST server sign with the keyring backends that
+// have no passphrase
+const FlagUnsafeKeyringSigning = "unsafe-keyring-signing"
+
+// Keyring is the keyring the REST server looks keys up in and signs with. It is opened
+// once when the server starts, requests neither create it nor choose its passphrase.
+type Keyring struct {
+	keybase keys.Keybase
+	backend string
+	// keyhash is the bcrypt hash of the passphrase of the file backend, which the
+	// requests using a key present. The os and test backends have no passphrase.
+	keyhash []byte
+	// signing tells whether requests may sign with the keys
+	signing bool
+}
+
+// NewKeyring creates the keyring of the REST server over a keybase. Requests using a
+// key present the passphrase, unless it is empty.
+func NewKeyring(kb keys.Keybase, passphrase string) (*Keyring, error) {
+	kr := &Keyring{keybase: kb, signing: true}
+	if passphrase == "" {
+		return kr, nil
+	}
+
+	// hashed as the file backend hashes its passphrase
+	keyhash, err := bcrypt.GenerateFromPassword(tmcrypto.CRandBytes(16), []byte(passphrase), 2)
+	if err != nil {
+		return nil, err
+	}
+	kr.keyhash = keyhash
+	return kr, nil
+}
+
+// OpenKeyring opens the keyring of a backend under rootDir. The file keyring must
+// already exist and its passphrase is read once from in. Anyone reaching the server
+// could sign with the keys of the os and test backends, so they only sign with
+// unsafeSigning.
+func OpenKeyring(backend, rootDir string, in io.Reader, unsafeSigning bool) (*Keyring, error) {
+	if backend != keys.BackendFile {
+		kb, err := keys.NewKeyring(sdk.KeyringServiceName(), backend, rootDir, in)
+		if err != nil {
+			return nil, err
+		}
+		return &Keyring{keybase: kb, backend: backend, signing: unsafeSigning}, nil
+	}
+
+	keyhashPath := filepath.Join(rootDir, "keyring-"+sdk.KeyringServiceName(), "keyhash")
+	keyhash, err := ioutil.ReadFile(keyhashPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the file keyring, add a key to create it: %v", err)
+	}
+
+	passphrase, err := input.GetPassword("Enter keyring passphrase:", bufio.NewReader(in))
+	if err != nil {
+		return nil, err
+	}
+	if bcrypt.CompareHashAndPassword(keyhash, []byte(passphrase)) != nil {
+		return nil, errors.New("incorrect keyring passphrase")
+	}
+
+	// the keyring asks for its passphrase when it is first read
+	kb, err := keys.NewKeyring(sdk.KeyringServiceName(), backend, rootDir, strings.NewReader(passphrase+"\n"))
+	if err != nil {
+		return nil, err
+	}
+	return &Keyring{keybase: kb, backend: backend, keyhash: keyhash, signing: true}, nil
+}
+
+// authorize checks the passphrase presented by a request using a key
+func (kr *Keyring) authorize(passphrase string) error {
+	if kr.keyhash == nil {
+		return nil
+	}
+	if bcrypt.CompareHashAndPassword(kr.keyhash, []byte(passphrase)) != nil {
+		return errors.New("incorrect keyring passphrase")
+	}
+	return nil
+}
+
+// checkSigning checks that requests may sign with the keys
+func (kr *Keyring) checkSigning() error {
+	if !kr.signing {
+		return fmt.Errorf("the REST server signs with the %s keyring backend only when started with --%s",
+			kr.backend, FlagUnsafeKeyringSigning)
+	}
+	return nil
+}
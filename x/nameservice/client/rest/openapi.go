@@ -0,0 +1,646 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/tendermint/tendermint/crypto"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/rest"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
+
+// The OpenAPI specification of the routes is generated from the route table below and
+// the Go types the handlers read and write, so that the schemas cannot drift from the
+// requests and responses. Schemas follow the amino JSON encoding: 64-bit integers are
+// strings, byte slices are base64 strings and interfaces are {type, value} objects.
+
+// responseKind tells how a handler writes its result
+type responseKind int
+
+const (
+	// queryResponse results are wrapped in {height, result} by rest.PostProcessResponse
+	queryResponse responseKind = iota
+	// bareResponse results are written as they are
+	bareResponse
+	// streamResponse results are messages of a WebSocket
+	streamResponse
+	// documentResponse results are documents of another media type
+	documentResponse
+)
+
+// apiParam is a query or header parameter of a route, path parameters are read from
+// the path of the route
+type apiParam struct {
+	Name        string
+	In          string
+	Description string
+}
+
+// apiRoute documents a route registered by RegisterRoutes, Path is relative to the store name
+type apiRoute struct {
+	Method      string
+	Path        string
+	Tag         string
+	Summary     string
+	Description string
+	Params      []apiParam
+	// Body is the zero value of the request body, nil for routes without a body
+	Body interface{}
+	// Result is the zero value of the result, or the media type of a document
+	Result interface{}
+	// ProvenResult is the zero value of the result returned with prove=true, if any
+	ProvenResult interface{}
+	Kind         responseKind
+}
+
+// pathParamDescriptions describes the parameters of the paths of the routes
+var pathParamDescriptions = map[string]string{
+	restName:    "Name",
+	"productID": "Product ID",
+	"address":   "Bech32 account address",
+	"codeHash":  "Hex encoded SHA-256 hash of the coupon code",
+	"file":      "Swagger UI asset",
+}
+
+func queryParam(name, description string) apiParam {
+	return apiParam{Name: name, In: "query", Description: description}
+}
+
+var proveQueryParam = queryParam(proveParam, "Set to true to return the raw store value along with its Merkle proof")
+
+// apiRoutes documents every route registered by RegisterRoutes
+var apiRoutes = []apiRoute{
+	{Method: "GET", Path: "/names", Tag: "names", Summary: "List the names",
+		Result: types.QueryResNames{}},
+	{Method: "POST", Path: "/names", Tag: "names", Summary: "Generate a transaction buying a name",
+		Body: buyNameReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "PUT", Path: "/names", Tag: "names", Summary: "Generate a transaction setting the value of a name",
+		Body: setNameReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "DELETE", Path: "/names", Tag: "names", Summary: "Generate a transaction deleting a name",
+		Body: deleteNameReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "GET", Path: "/names/{name}", Tag: "names", Summary: "Resolve a name to its value",
+		Result: types.QueryResResolve{}},
+	{Method: "GET", Path: "/names/{name}/whois", Tag: "names", Summary: "Get the record of a name",
+		Params: []apiParam{proveQueryParam},
+		Result: types.Whois{}, ProvenResult: provenWhois{}},
+	{Method: "PUT", Path: "/names/storefrontSale", Tag: "names",
+		Summary: "Generate a transaction including the storefront in the sales of a name",
+		Body:    setStorefrontSaleReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "GET", Path: "/names/{name}/storefront", Tag: "names", Summary: "List the products published under a name",
+		Result: types.QueryResAllProducts{}},
+	{Method: "GET", Path: "/names/{name}/storefront/{productID}", Tag: "names",
+		Summary: "Get a product published under a name",
+		Result:  types.Product{}},
+
+	{Method: "GET", Path: "/product", Tag: "products", Summary: "List the products",
+		Params: []apiParam{
+			queryParam("category", "Only return the products of a category"),
+			queryParam("tag", "Only return the products labelled with a tag"),
+			queryParam("owner", "Only return the products of a Bech32 account address"),
+			queryParam("min_price", "Only return the products priced at least these coins, e.g. 10nametoken"),
+			queryParam("max_price", "Only return the products priced at most these coins"),
+			queryParam("listed", "Set to true to only return the listed products"),
+		},
+		Result: types.QueryResAllProducts{}},
+	{Method: "POST", Path: "/product", Tag: "products", Summary: "Generate a transaction creating a product",
+		Body: createProductReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "PUT", Path: "/product", Tag: "products", Summary: "Generate a transaction updating a product",
+		Body: updateProductReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "POST", Path: "/product/buyProduct", Tag: "products", Summary: "Generate a transaction buying a product",
+		Body: buyProductReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "POST", Path: "/product/buyProducts", Tag: "products",
+		Summary: "Generate a transaction buying several products at once",
+		Body:    buyProductsReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "POST", Path: "/product/listProduct", Tag: "products", Summary: "Generate a transaction listing a product for sale",
+		Body: listProductReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "POST", Path: "/product/delistProduct", Tag: "products", Summary: "Generate a transaction delisting a product",
+		Body: delistProductReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "POST", Path: "/product/publishProduct", Tag: "products",
+		Summary: "Generate a transaction publishing a product under a name",
+		Body:    publishProductReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "POST", Path: "/product/unpublishProduct", Tag: "products",
+		Summary: "Generate a transaction unpublishing a product from its storefront",
+		Body:    unpublishProductReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "POST", Path: "/product/reviewProduct", Tag: "products", Summary: "Generate a transaction reviewing a product",
+		Body: reviewProductReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "PUT", Path: "/product/pricing", Tag: "products",
+		Summary: "Generate a transaction setting the accepted and reference prices of a product",
+		Body:    setProductPricingReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "PUT", Path: "/product/subscription", Tag: "products",
+		Summary: "Generate a transaction selling a product as a subscription",
+		Body:    setProductSubscriptionReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "POST", Path: "/product/cancelSubscription", Tag: "products",
+		Summary: "Generate a transaction cancelling a subscription",
+		Body:    cancelSubscriptionReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "PUT", Path: "/product/licensing", Tag: "products",
+		Summary: "Generate a transaction selling licenses of a product",
+		Body:    setProductLicensingReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "GET", Path: "/product/{productID}", Tag: "products", Summary: "Get a product",
+		Params: []apiParam{proveQueryParam},
+		Result: types.Product{}, ProvenResult: provenProduct{}},
+	{Method: "GET", Path: "/product/{productID}/reviews", Tag: "products", Summary: "List the reviews of a product",
+		Result: types.QueryResReviews{}},
+	{Method: "GET", Path: "/product/{productID}/rating", Tag: "products", Summary: "Get the rating of a product",
+		Result: types.QueryResRating{}},
+	{Method: "GET", Path: "/product/{productID}/hasLicense/{address}", Tag: "products",
+		Summary: "Check whether an account holds a valid license of a product",
+		Result:  types.QueryResHasLicense{}},
+	{Method: "GET", Path: "/product/{productID}/history", Tag: "products", Summary: "List the changes of the terms of a product",
+		Result: types.QueryResProductHistory{}},
+	{Method: "GET", Path: "/product/{productID}/subscriptions", Tag: "products", Summary: "List the subscriptions to a product",
+		Result: types.QueryResSubscriptions{}},
+	{Method: "GET", Path: "/product/{productID}/subscriptions/{address}", Tag: "products",
+		Summary: "Get the subscription of an account to a product",
+		Result:  types.QueryResSubscriptionStatus{}},
+	{Method: "GET", Path: "/seller/{address}/reputation", Tag: "products", Summary: "Get the reputation of a seller",
+		Result: types.QueryResRating{}},
+
+	{Method: "GET", Path: "/auction", Tag: "auctions", Summary: "List the auctions",
+		Result: types.QueryResAuctions{}},
+	{Method: "POST", Path: "/auction", Tag: "auctions", Summary: "Generate a transaction auctioning a product",
+		Body: createAuctionReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "POST", Path: "/auction/bid", Tag: "auctions", Summary: "Generate a transaction bidding on an auction",
+		Body: placeBidReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "GET", Path: "/auction/{productID}", Tag: "auctions", Summary: "Get the auction of a product",
+		Result: types.Auction{}},
+	{Method: "GET", Path: "/auction/{productID}/bids", Tag: "auctions", Summary: "List the bids of an auction",
+		Result: types.QueryResBids{}},
+
+	{Method: "POST", Path: "/coupon", Tag: "coupons", Summary: "Generate a transaction creating a coupon",
+		Body: createCouponReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "DELETE", Path: "/coupon", Tag: "coupons", Summary: "Generate a transaction revoking a coupon",
+		Body: revokeCouponReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "GET", Path: "/coupon/{codeHash}", Tag: "coupons", Summary: "Get a coupon",
+		Result: types.Coupon{}},
+	{Method: "POST", Path: "/coupon/commit", Tag: "coupons", Summary: "Generate a transaction committing to a coupon code before redeeming it",
+		Body: commitCouponReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+
+	{Method: "GET", Path: "/name/{name}/address", Tag: "keys", Summary: "Look a key of the keyring of the REST server up",
+		Description: "Served by nscli rest-server along with the keyring it was started with.",
+		Params:      []apiParam{{Name: passphraseHeader, In: "header", Description: "Passphrase of the file keyring backend"}},
+		Result:      keys.KeyOutput{}},
+	{Method: "POST", Path: "/tx/sign", Tag: "transactions",
+		Summary:     "Sign a generated transaction with a key of the REST server and broadcast it",
+		Description: "Served by nscli rest-server, which signs with the os and test keyring backends only with --unsafe-keyring-signing. Rejected transactions are returned with a 4xx status code telling why.",
+		Body:        signTxReq{}, Result: sdk.TxResponse{}, Kind: bareResponse},
+	{Method: "POST", Path: "/tx/prepare", Tag: "transactions",
+		Summary: "Get the document an external signer signs for a generated transaction",
+		Body:    prepareTxReq{}, Result: prepareTxRes{}},
+	{Method: "POST", Path: "/tx/submit", Tag: "transactions",
+		Summary:     "Broadcast a transaction signed by an external signer",
+		Description: "Rejected transactions are returned with a 4xx status code telling why.",
+		Body:        submitTxReq{}, Result: sdk.TxResponse{}, Kind: bareResponse},
+
+	{Method: "GET", Path: "/events", Tag: "events",
+		Summary:     "Stream the changes of names and products over a WebSocket",
+		Description: "Every message of the WebSocket is a changeNotification.",
+		Params: []apiParam{
+			queryParam("name", "Only stream the changes of a name"),
+			queryParam("owner", "Only stream the changes of the names and products a Bech32 account address owned before or after them"),
+			queryParam("product_id", "Only stream the changes of a product"),
+			queryParam("from_height", "First replay the changes committed since a height"),
+		},
+		Result: changeNotification{}, Kind: streamResponse},
+
+	{Method: "GET", Path: "/v2/names", Tag: "v2 names", Summary: "List the names",
+		Result: types.QueryResNames{}},
+	{Method: "GET", Path: "/v2/names/{name}", Tag: "v2 names", Summary: "Get the record of a name, names nobody owns do not exist",
+		Result: types.Whois{}},
+	{Method: "DELETE", Path: "/v2/names/{name}", Tag: "v2 names", Summary: "Generate a transaction deleting a name",
+		Body: v2SignerReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "GET", Path: "/v2/names/{name}/value", Tag: "v2 names", Summary: "Resolve a name to its value",
+		Result: types.QueryResResolve{}},
+	{Method: "PUT", Path: "/v2/names/{name}/value", Tag: "v2 names", Summary: "Generate a transaction setting the value of a name",
+		Body: v2SetValueReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "POST", Path: "/v2/names/{name}/purchase", Tag: "v2 names", Summary: "Generate a transaction buying a name",
+		Body: v2PurchaseNameReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "PUT", Path: "/v2/names/{name}/storefront-sale", Tag: "v2 names",
+		Summary: "Generate a transaction including the storefront in the sales of a name",
+		Body:    v2StorefrontSaleReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "GET", Path: "/v2/names/{name}/products", Tag: "v2 names", Summary: "List the products published under a name",
+		Result: types.QueryResAllProducts{}},
+	{Method: "GET", Path: "/v2/names/{name}/products/{productID}", Tag: "v2 names",
+		Summary: "Get a product published under a name",
+		Result:  types.Product{}},
+
+	{Method: "GET", Path: "/v2/products", Tag: "v2 products", Summary: "List the products",
+		Params: []apiParam{
+			queryParam("category", "Only return the products of a category"),
+			queryParam("tag", "Only return the products labelled with a tag"),
+			queryParam("owner", "Only return the products of a Bech32 account address"),
+			queryParam("min_price", "Only return the products priced at least these coins, e.g. 10nametoken"),
+			queryParam("max_price", "Only return the products priced at most these coins"),
+			queryParam("listed", "Set to true to only return the listed products"),
+		},
+		Result: types.QueryResAllProducts{}},
+	{Method: "POST", Path: "/v2/products", Tag: "v2 products", Summary: "Generate a transaction creating a product",
+		Body: v2CreateProductReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "GET", Path: "/v2/products/{productID}", Tag: "v2 products", Summary: "Get a product",
+		Result: types.Product{}},
+	{Method: "PUT", Path: "/v2/products/{productID}", Tag: "v2 products", Summary: "Generate a transaction updating a product",
+		Body: v2UpdateProductReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "POST", Path: "/v2/products/{productID}/purchase", Tag: "v2 products", Summary: "Generate a transaction buying a product",
+		Body: v2PurchaseProductReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "PUT", Path: "/v2/products/{productID}/listing", Tag: "v2 products",
+		Summary: "Generate a transaction listing a product for sale or delisting it",
+		Body:    v2ListingReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "PUT", Path: "/v2/products/{productID}/storefront", Tag: "v2 products",
+		Summary: "Generate a transaction publishing a product under a name",
+		Body:    v2StorefrontReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "DELETE", Path: "/v2/products/{productID}/storefront", Tag: "v2 products",
+		Summary: "Generate a transaction unpublishing a product from its storefront",
+		Body:    v2SignerReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "PUT", Path: "/v2/products/{productID}/pricing", Tag: "v2 products",
+		Summary: "Generate a transaction setting the accepted and reference prices of a product",
+		Body:    v2PricingReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "PUT", Path: "/v2/products/{productID}/subscription", Tag: "v2 products",
+		Summary: "Generate a transaction selling a product as a subscription",
+		Body:    v2SubscriptionTermsReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "PUT", Path: "/v2/products/{productID}/licensing", Tag: "v2 products",
+		Summary: "Generate a transaction selling licenses of a product",
+		Body:    v2LicensingReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "GET", Path: "/v2/products/{productID}/reviews", Tag: "v2 products", Summary: "List the reviews of a product",
+		Result: types.QueryResReviews{}},
+	{Method: "POST", Path: "/v2/products/{productID}/reviews", Tag: "v2 products", Summary: "Generate a transaction reviewing a product",
+		Body: v2ReviewReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "GET", Path: "/v2/products/{productID}/rating", Tag: "v2 products", Summary: "Get the rating of a product",
+		Result: types.QueryResRating{}},
+	{Method: "GET", Path: "/v2/products/{productID}/history", Tag: "v2 products", Summary: "List the changes of the terms of a product",
+		Result: types.QueryResProductHistory{}},
+	{Method: "GET", Path: "/v2/products/{productID}/subscriptions", Tag: "v2 products", Summary: "List the subscriptions to a product",
+		Result: types.QueryResSubscriptions{}},
+	{Method: "GET", Path: "/v2/products/{productID}/subscriptions/{address}", Tag: "v2 products",
+		Summary: "Get the subscription of an account to a product",
+		Result:  types.QueryResSubscriptionStatus{}},
+	{Method: "DELETE", Path: "/v2/products/{productID}/subscriptions/{address}", Tag: "v2 products",
+		Summary: "Generate a transaction cancelling the subscription of the signer",
+		Body:    v2SignerReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "GET", Path: "/v2/products/{productID}/licenses/{address}", Tag: "v2 products",
+		Summary: "Check whether an account holds a valid license of a product",
+		Result:  types.QueryResHasLicense{}},
+	{Method: "GET", Path: "/v2/products/{productID}/auction", Tag: "v2 products", Summary: "Get the auction of a product",
+		Result: types.Auction{}},
+	{Method: "POST", Path: "/v2/products/{productID}/auction", Tag: "v2 products", Summary: "Generate a transaction auctioning a product",
+		Body: v2AuctionReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "GET", Path: "/v2/products/{productID}/auction/bids", Tag: "v2 products", Summary: "List the bids of the auction of a product",
+		Result: types.QueryResBids{}},
+	{Method: "POST", Path: "/v2/products/{productID}/auction/bids", Tag: "v2 products",
+		Summary: "Generate a transaction bidding on the auction of a product",
+		Body:    v2BidReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "POST", Path: "/v2/purchases", Tag: "v2 products", Summary: "Generate a transaction buying several products at once",
+		Body: v2PurchaseProductsReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+
+	{Method: "GET", Path: "/v2/auctions", Tag: "v2 auctions", Summary: "List the auctions",
+		Result: types.QueryResAuctions{}},
+	{Method: "GET", Path: "/v2/sellers/{address}/reputation", Tag: "v2 sellers", Summary: "Get the reputation of a seller",
+		Result: types.QueryResRating{}},
+
+	{Method: "POST", Path: "/v2/coupons", Tag: "v2 coupons", Summary: "Generate a transaction creating a coupon",
+		Body: v2CouponReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "GET", Path: "/v2/coupons/{codeHash}", Tag: "v2 coupons", Summary: "Get a coupon",
+		Result: types.Coupon{}},
+	{Method: "DELETE", Path: "/v2/coupons/{codeHash}", Tag: "v2 coupons", Summary: "Generate a transaction revoking a coupon",
+		Body: v2SignerReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+	{Method: "POST", Path: "/v2/coupons/commitments", Tag: "v2 coupons",
+		Summary: "Generate a transaction committing to a coupon code before redeeming it",
+		Body:    v2CommitCouponReq{}, Result: auth.StdTx{}, Kind: bareResponse},
+
+	{Method: "GET", Path: "/swagger", Tag: "documentation", Summary: "Browse this specification with Swagger UI",
+		Result: "text/html", Kind: documentResponse},
+	{Method: "GET", Path: "/swagger/openapi.json", Tag: "documentation", Summary: "Get this specification",
+		Result: "application/json", Kind: documentResponse},
+	{Method: "GET", Path: "/swagger/{file}", Tag: "documentation", Summary: "Get an asset of Swagger UI",
+		Result: "application/octet-stream", Kind: documentResponse},
+}
+
+type openAPISpec struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       openAPIInfo                             `json:"info"`
+	Tags       []openAPITag                            `json:"tags"`
+	Paths      map[string]map[string]*openAPIOperation `json:"paths"`
+	Components openAPIComponents                       `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Version     string `json:"version"`
+}
+
+type openAPITag struct {
+	Name string `json:"name"`
+}
+
+type openAPIOperation struct {
+	Tags        []string                    `json:"tags"`
+	Summary     string                      `json:"summary"`
+	Description string                      `json:"description,omitempty"`
+	OperationID string                      `json:"operationId"`
+	Parameters  []openAPIParameter          `json:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*openAPIResponse `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Schema      *openAPISchema `json:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `json:"schema"`
+}
+
+type openAPIComponents struct {
+	Schemas map[string]*openAPISchema `json:"schemas"`
+}
+
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Description          string                    `json:"description,omitempty"`
+	Items                *openAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*openAPISchema `json:"properties,omitempty"`
+	AdditionalProperties *openAPISchema            `json:"additionalProperties,omitempty"`
+	OneOf                []*openAPISchema          `json:"oneOf,omitempty"`
+}
+
+// newOpenAPISpec generates the specification of the routes registered under a store name
+func newOpenAPISpec(storeName string) openAPISpec {
+	g := newSchemaGenerator()
+
+	spec := openAPISpec{
+		OpenAPI: "3.0.0",
+		Info: openAPIInfo{
+			Title: "Nameservice REST API",
+			Description: "Routes of the nameservice module served by nscli rest-server. Transactions " +
+				"generated by the POST, PUT and DELETE routes are unsigned, they are signed and " +
+				"broadcast with the routes under /tx.",
+			Version: "1",
+		},
+		Paths: make(map[string]map[string]*openAPIOperation),
+	}
+
+	seenTags := make(map[string]bool)
+	for _, route := range apiRoutes {
+		if !seenTags[route.Tag] {
+			seenTags[route.Tag] = true
+			spec.Tags = append(spec.Tags, openAPITag{Name: route.Tag})
+		}
+
+		path := fmt.Sprintf("/%s%s", storeName, route.Path)
+		if spec.Paths[path] == nil {
+			spec.Paths[path] = make(map[string]*openAPIOperation)
+		}
+		spec.Paths[path][strings.ToLower(route.Method)] = g.operation(route)
+	}
+
+	spec.Components.Schemas = g.schemas
+	return spec
+}
+
+var pathParamPattern = regexp.MustCompile(`{(\w+)}`)
+
+// operation generates the operation of a route
+func (g *schemaGenerator) operation(route apiRoute) *openAPIOperation {
+	// the v2 routes write their errors in an envelope carrying the code of the error
+	v2 := strings.HasPrefix(route.Path, v2Path+"/")
+	errorType := reflect.TypeOf(rest.ErrorResponse{})
+	if v2 {
+		errorType = reflect.TypeOf(v2ErrorResponse{})
+	}
+
+	op := &openAPIOperation{
+		Tags:        []string{route.Tag},
+		Summary:     route.Summary,
+		Description: route.Description,
+		OperationID: operationID(route),
+		Responses: map[string]*openAPIResponse{
+			"default": jsonResponse("Error", g.schema(errorType)),
+		},
+	}
+
+	for _, match := range pathParamPattern.FindAllStringSubmatch(route.Path, -1) {
+		op.Parameters = append(op.Parameters, openAPIParameter{
+			Name:        match[1],
+			In:          "path",
+			Description: pathParamDescriptions[match[1]],
+			Required:    true,
+			Schema:      &openAPISchema{Type: "string"},
+		})
+	}
+	params := route.Params
+	if v2 && route.Kind == queryResponse {
+		params = append(params, queryParam(heightParam, "Height to query the state at, the latest height by default"))
+	}
+	for _, param := range params {
+		op.Parameters = append(op.Parameters, openAPIParameter{
+			Name:        param.Name,
+			In:          param.In,
+			Description: param.Description,
+			Schema:      &openAPISchema{Type: "string"},
+		})
+	}
+
+	if route.Body != nil {
+		op.RequestBody = &openAPIRequestBody{
+			Required: true,
+			Content: map[string]openAPIMediaType{
+				"application/json": {Schema: g.schema(reflect.TypeOf(route.Body))},
+			},
+		}
+	}
+
+	switch route.Kind {
+	case queryResponse:
+		result := g.schema(reflect.TypeOf(route.Result))
+		if route.ProvenResult != nil {
+			result = &openAPISchema{OneOf: []*openAPISchema{result, g.schema(reflect.TypeOf(route.ProvenResult))}}
+		}
+		op.Responses["200"] = jsonResponse("Result at the height it was queried at", &openAPISchema{
+			Type: "object",
+			Properties: map[string]*openAPISchema{
+				"height": {Type: "string", Format: "int64"},
+				"result": result,
+			},
+		})
+	case bareResponse:
+		op.Responses["200"] = jsonResponse("Result", g.schema(reflect.TypeOf(route.Result)))
+	case streamResponse:
+		op.Responses["101"] = jsonResponse("Switching to the WebSocket protocol", g.schema(reflect.TypeOf(route.Result)))
+	case documentResponse:
+		op.Responses["200"] = &openAPIResponse{
+			Description: "Document",
+			Content: map[string]openAPIMediaType{
+				route.Result.(string): {Schema: &openAPISchema{Type: "string"}},
+			},
+		}
+	}
+
+	return op
+}
+
+func jsonResponse(description string, schema *openAPISchema) *openAPIResponse {
+	return &openAPIResponse{
+		Description: description,
+		Content:     map[string]openAPIMediaType{"application/json": {Schema: schema}},
+	}
+}
+
+// operationID derives a unique identifier of a route from its method and path,
+// e.g. getProductProductIDReviews
+func operationID(route apiRoute) string {
+	id := strings.ToLower(route.Method)
+	for _, part := range strings.FieldsFunc(route.Path, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	}) {
+		id += strings.ToUpper(part[:1]) + part[1:]
+	}
+	return id
+}
+
+var (
+	accAddressType = reflect.TypeOf(sdk.AccAddress{})
+	intType        = reflect.TypeOf(sdk.Int{})
+	decType        = reflect.TypeOf(sdk.Dec{})
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+	pubKeyType     = reflect.TypeOf((*crypto.PubKey)(nil)).Elem()
+)
+
+// schemaGenerator generates the schemas of Go types, structs are added to the
+// components of the specification and referenced by their name
+type schemaGenerator struct {
+	schemas map[string]*openAPISchema
+	names   map[reflect.Type]string
+}
+
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{
+		schemas: make(map[string]*openAPISchema),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+// schema returns the schema of the amino JSON encoding of a type
+func (g *schemaGenerator) schema(t reflect.Type) *openAPISchema {
+	switch t {
+	case accAddressType:
+		return &openAPISchema{Type: "string", Format: "bech32"}
+	case intType:
+		return &openAPISchema{Type: "string", Format: "integer"}
+	case decType:
+		return &openAPISchema{Type: "string", Format: "decimal"}
+	case timeType:
+		return &openAPISchema{Type: "string", Format: "date-time"}
+	case rawMessageType:
+		return &openAPISchema{}
+	case pubKeyType:
+		return &openAPISchema{Type: "object", Properties: map[string]*openAPISchema{
+			"type":  {Type: "string"},
+			"value": {Type: "string", Format: "byte"},
+		}}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.schema(t.Elem())
+	case reflect.String:
+		return &openAPISchema{Type: "string"}
+	case reflect.Bool:
+		return &openAPISchema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &openAPISchema{Type: "integer"}
+	case reflect.Int, reflect.Int64:
+		return &openAPISchema{Type: "string", Format: "int64"}
+	case reflect.Uint, reflect.Uint64:
+		return &openAPISchema{Type: "string", Format: "uint64"}
+	case reflect.Float32, reflect.Float64:
+		return &openAPISchema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &openAPISchema{Type: "string", Format: "byte"}
+		}
+		return &openAPISchema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &openAPISchema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Interface:
+		// amino encodes the concrete types registered for an interface along with their name
+		return &openAPISchema{Type: "object", Properties: map[string]*openAPISchema{
+			"type":  {Type: "string"},
+			"value": {Type: "object"},
+		}}
+	case reflect.Struct:
+		return &openAPISchema{Ref: "#/components/schemas/" + g.structName(t)}
+	default:
+		panic(fmt.Sprintf("no schema for type %s", t))
+	}
+}
+
+// structName adds the schema of a struct to the components, under its type name
+// qualified with its package when two structs share a name
+func (g *schemaGenerator) structName(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+
+	name := t.Name()
+	if _, taken := g.schemas[name]; taken || name == "" {
+		name = strings.Replace(t.String(), ".", "", 1)
+	}
+
+	// registered before the properties, so that recursive types end
+	schema := &openAPISchema{Type: "object", Properties: make(map[string]*openAPISchema)}
+	g.names[t] = name
+	g.schemas[name] = schema
+	g.addFields(schema, t)
+	return name
+}
+
+// addFields adds the properties of the exported fields of a struct to a schema,
+// embedded structs without a JSON name are flattened like encoding/json does
+func (g *schemaGenerator) addFields(schema *openAPISchema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		tag := strings.Split(field.Tag.Get("json"), ",")[0]
+		if tag == "-" {
+			continue
+		}
+		if field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct {
+			g.addFields(schema, field.Type)
+			continue
+		}
+
+		name := tag
+		if name == "" {
+			name = field.Name
+		}
+		schema.Properties[name] = g.schema(field.Type)
+	}
+}
@@ -0,0 +1,104 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+)
+
+const testStoreName = "nameservice"
+
+func newTestRouter() *mux.Router {
+	r := mux.NewRouter()
+	RegisterRoutes(context.CLIContext{}, r, testStoreName)
+	RegisterKeyringRoutes(context.CLIContext{}, r, testStoreName, &Keyring{})
+	return r
+}
+
+func TestOpenAPISpecCoversRoutes(t *testing.T) {
+	spec := newOpenAPISpec(testStoreName)
+
+	registered := make(map[string]bool)
+	err := newTestRouter().Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		require.NoError(t, err)
+		methods, err := route.GetMethods()
+		require.NoError(t, err)
+
+		for _, method := range methods {
+			method = strings.ToLower(method)
+			registered[method+" "+path] = true
+			require.NotNil(t, spec.Paths[path][method], "route %s %s is missing from the OpenAPI specification", method, path)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	// the specification does not document routes that are not registered
+	operationIDs := make(map[string]bool)
+	for path, ops := range spec.Paths {
+		for method, op := range ops {
+			require.True(t, registered[method+" "+path], "route %s %s of the OpenAPI specification is not registered", method, path)
+			require.False(t, operationIDs[op.OperationID], "operation ID %s is not unique", op.OperationID)
+			operationIDs[op.OperationID] = true
+		}
+	}
+}
+
+func TestOpenAPISpecSchemas(t *testing.T) {
+	spec := newOpenAPISpec(testStoreName)
+
+	buyName := spec.Paths["/nameservice/names"]["post"].RequestBody.Content["application/json"].Schema
+	require.Equal(t, "#/components/schemas/buyNameReq", buyName.Ref)
+	require.Len(t, spec.Components.Schemas["buyNameReq"].Properties, 4)
+	require.Equal(t, "#/components/schemas/BaseReq", spec.Components.Schemas["buyNameReq"].Properties["base_req"].Ref)
+
+	// amino encodes 64-bit integers and addresses as strings
+	product := spec.Components.Schemas["Product"]
+	require.Equal(t, "string", product.Properties["version"].Type)
+	require.Equal(t, "string", product.Properties["owner"].Type)
+	require.Equal(t, "#/components/schemas/Coin", product.Properties["price"].Items.Ref)
+
+	whois := spec.Paths["/nameservice/names/{name}/whois"]["get"]
+	result := whois.Responses["200"].Content["application/json"].Schema.Properties["result"]
+	require.Equal(t, "#/components/schemas/Whois", result.OneOf[0].Ref)
+	require.Equal(t, "#/components/schemas/provenWhois", result.OneOf[1].Ref)
+	require.Equal(t, "name", whois.Parameters[0].Name)
+	require.Equal(t, "path", whois.Parameters[0].In)
+
+	// every reference resolves to a schema of the components
+	bz, err := json.Marshal(spec)
+	require.NoError(t, err)
+	for _, ref := range strings.Split(string(bz), `"$ref":"#/components/schemas/`)[1:] {
+		name := ref[:strings.Index(ref, `"`)]
+		require.Contains(t, spec.Components.Schemas, name)
+	}
+}
+
+func TestSwaggerRoutes(t *testing.T) {
+	server := httptest.NewServer(newTestRouter())
+	defer server.Close()
+
+	res, err := http.Get(server.URL + "/nameservice/swagger/openapi.json")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	var spec openAPISpec
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&spec))
+	require.Equal(t, "3.0.0", spec.OpenAPI)
+
+	for _, path := range []string{"/nameservice/swagger", "/nameservice/swagger/swagger-ui-bundle.js"} {
+		res, err := http.Get(server.URL + path)
+		require.NoError(t, err)
+		res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode, path)
+	}
+}
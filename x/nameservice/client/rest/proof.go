@@ -0,0 +1,135 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/merkle"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	"github.com/cosmos/cosmos-sdk/types/rest"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
+
+// proveParam is the query parameter asking for a store value along with its Merkle proof
+const proveParam = "prove"
+
+// storeProof proves the raw value of a key of a store at the height of the response.
+// The proof leads to the app hash of the header of the next height, the value of an
+// absent key is empty and the proof then proves its absence.
+type storeProof struct {
+	Store string        `json:"store"`
+	Key   []byte        `json:"key"`
+	Value []byte        `json:"value"`
+	Proof *merkle.Proof `json:"proof"`
+}
+
+func newStoreProof(storeName string, resp abci.ResponseQuery) storeProof {
+	return storeProof{Store: storeName, Key: resp.Key, Value: resp.Value, Proof: resp.Proof}
+}
+
+// provenWhois is the record of a name along with the proof of it
+type provenWhois struct {
+	Whois types.Whois `json:"whois"`
+	Proof storeProof  `json:"proof"`
+}
+
+// provenProduct is a product along with the proof of it
+type provenProduct struct {
+	Product types.Product `json:"product"`
+	Proof   storeProof    `json:"proof"`
+}
+
+func writeProvenWhois(cliCtx context.CLIContext, storeName string, w http.ResponseWriter, name string) {
+	resp, err := queryStoreWithProof(cliCtx, storeName, types.WhoisKey(name))
+	if err != nil {
+		rest.WriteErrorResponse(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	whois := types.NewWhois()
+	if len(resp.Value) > 0 {
+		if err := cliCtx.Codec.UnmarshalBinaryBare(resp.Value, &whois); err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	rest.PostProcessResponse(w, cliCtx.WithHeight(resp.Height), provenWhois{Whois: whois, Proof: newStoreProof(storeName, resp)})
+}
+
+func writeProvenProduct(cliCtx context.CLIContext, storeName string, w http.ResponseWriter, productID string) {
+	resp, err := queryStoreWithProof(cliCtx, storeName, []byte(types.ProductPrefix+productID))
+	if err != nil {
+		rest.WriteErrorResponse(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	product := types.NewProduct()
+	if len(resp.Value) > 0 {
+		if err := cliCtx.Codec.UnmarshalBinaryBare(resp.Value, &product); err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	rest.PostProcessResponse(w, cliCtx.WithHeight(resp.Height), provenProduct{Product: product, Proof: newStoreProof(storeName, resp)})
+}
+
+// queryStoreWithProof reads a key of a store along with its Merkle proof. Unless the REST
+// server trusts its node, the proof is verified against a header checked by the light client.
+func queryStoreWithProof(cliCtx context.CLIContext, storeName string, key []byte) (abci.ResponseQuery, error) {
+	node, err := cliCtx.GetNode()
+	if err != nil {
+		return abci.ResponseQuery{}, err
+	}
+
+	opts := rpcclient.ABCIQueryOptions{Height: cliCtx.Height, Prove: true}
+	result, err := node.ABCIQueryWithOptions(fmt.Sprintf("/store/%s/key", storeName), key, opts)
+	if err != nil {
+		return abci.ResponseQuery{}, err
+	}
+
+	resp := result.Response
+	if !resp.IsOK() {
+		return resp, errors.New(resp.Log)
+	}
+
+	if cliCtx.TrustNode {
+		return resp, nil
+	}
+	return resp, verifyStoreProof(cliCtx, storeName, resp)
+}
+
+// verifyStoreProof verifies the proof of a store query against the app hash of the
+// header following the height of the query
+func verifyStoreProof(cliCtx context.CLIContext, storeName string, resp abci.ResponseQuery) error {
+	if cliCtx.Verifier == nil {
+		return errors.New("cannot verify the proof of an untrusted node without a light client")
+	}
+
+	commit, err := cliCtx.Verify(resp.Height + 1)
+	if err != nil {
+		return err
+	}
+
+	kp := merkle.KeyPath{}.
+		AppendKey([]byte(storeName), merkle.KeyEncodingURL).
+		AppendKey(resp.Key, merkle.KeyEncodingURL)
+
+	prt := rootmulti.DefaultProofRuntime()
+	if len(resp.Value) == 0 {
+		err = prt.VerifyAbsence(resp.Proof, commit.Header.AppHash, kp.String())
+	} else {
+		err = prt.VerifyValue(resp.Proof, commit.Header.AppHash, kp.String(), resp.Value)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to verify the merkle proof: %w", err)
+	}
+	return nil
+}
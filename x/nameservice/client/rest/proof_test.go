@@ -0,0 +1,160 @@
+package rest_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/bytes"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/client"
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/client/clienttest"
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/client/rest"
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
+
+// tamperingNode changes the responses of the queries of a node
+type tamperingNode struct {
+	*clienttest.Node
+	tamper func(resp *abci.ResponseQuery)
+}
+
+func (n tamperingNode) ABCIQueryWithOptions(path string, data bytes.HexBytes,
+	opts rpcclient.ABCIQueryOptions) (*ctypes.ResultABCIQuery, error) {
+	res, err := n.Node.ABCIQueryWithOptions(path, data, opts)
+	if err == nil && n.tamper != nil {
+		n.tamper(&res.Response)
+	}
+	return res, err
+}
+
+// setupProof starts an app in which a seller owns the name alice and the product book1,
+// and returns the client of the seller, the node and the height of the latest block
+// whose next header is committed
+func setupProof(t *testing.T) (client.Client, *clienttest.Node, int64) {
+	node, clients := clienttest.Setup(t, "seller")
+	seller := clients[0]
+
+	price := sdk.NewCoins(sdk.NewInt64Coin("nametoken", 10))
+	_, err := seller.BuyName("alice", price)
+	require.NoError(t, err)
+	_, err = seller.CreateProduct("book1", "a book", price, "books", nil, types.Content{})
+	require.NoError(t, err)
+
+	node.CommitBlock()
+	return seller, node, node.Height() - 1
+}
+
+// serveProofs serves the routes of the module from a context that does not trust the
+// node and verifies the proofs of the state at a height
+func serveProofs(cliCtx context.CLIContext, node tamperingNode, verifier *clienttest.Verifier, height int64) *httptest.Server {
+	cliCtx = cliCtx.WithClient(node).WithTrustNode(false).WithHeight(height)
+	if verifier != nil {
+		cliCtx = cliCtx.WithVerifier(verifier)
+	}
+
+	r := mux.NewRouter()
+	rest.RegisterRoutes(cliCtx, r, "nameservice")
+	return httptest.NewServer(r)
+}
+
+type provenRes struct {
+	Height int64 `json:"height,string"`
+	Result struct {
+		Whois   json.RawMessage `json:"whois"`
+		Product json.RawMessage `json:"product"`
+		Proof   struct {
+			Store string `json:"store"`
+			Value []byte `json:"value"`
+		} `json:"proof"`
+	} `json:"result"`
+}
+
+func TestProvenQueries(t *testing.T) {
+	seller, node, height := setupProof(t)
+	cdc := seller.CLIContext().Codec
+	verifier := &clienttest.Verifier{}
+	server := serveProofs(seller.CLIContext(), tamperingNode{Node: node}, verifier, height)
+	defer server.Close()
+
+	// the proof of the state at a height leads to the app hash of the next header
+	status, bz := request(t, "GET", server.URL+"/nameservice/names/alice/whois?prove=true", nil)
+	require.Equal(t, http.StatusOK, status, string(bz))
+	require.Equal(t, []int64{height + 1}, verifier.Heights())
+
+	var res provenRes
+	require.NoError(t, json.Unmarshal(bz, &res))
+	require.Equal(t, height, res.Height)
+	require.Equal(t, "nameservice", res.Result.Proof.Store)
+	var whois types.Whois
+	require.NoError(t, cdc.UnmarshalJSON(res.Result.Whois, &whois))
+	require.Equal(t, seller.CLIContext().GetFromAddress(), whois.Owner)
+
+	status, bz = request(t, "GET", server.URL+"/nameservice/product/book1?prove=true", nil)
+	require.Equal(t, http.StatusOK, status, string(bz))
+	res = provenRes{}
+	require.NoError(t, json.Unmarshal(bz, &res))
+	var product types.Product
+	require.NoError(t, cdc.UnmarshalJSON(res.Result.Product, &product))
+	require.Equal(t, "book1", product.ProductID)
+
+	// the absence of a name is proven too
+	status, bz = request(t, "GET", server.URL+"/nameservice/names/bob/whois?prove=true", nil)
+	require.Equal(t, http.StatusOK, status, string(bz))
+	res = provenRes{}
+	require.NoError(t, json.Unmarshal(bz, &res))
+	require.Empty(t, res.Result.Proof.Value)
+}
+
+func TestProvenQueriesRejectTampering(t *testing.T) {
+	seller, node, height := setupProof(t)
+
+	tests := []struct {
+		name   string
+		tamper func(resp *abci.ResponseQuery)
+	}{
+		{"tampered value", func(resp *abci.ResponseQuery) {
+			resp.Value = append(resp.Value, 0)
+		}},
+		{"value of an absent key", func(resp *abci.ResponseQuery) {
+			resp.Value = nil
+		}},
+		// the versions in the store infos of the multistore op are not hashed, the
+		// leaf of the IAVL op is
+		{"tampered proof", func(resp *abci.ResponseQuery) {
+			op := &resp.Proof.Ops[0]
+			op.Data[len(op.Data)-1] ^= 1
+		}},
+		{"proof of another key", func(resp *abci.ResponseQuery) {
+			resp.Key = types.WhoisKey("bob")
+		}},
+		{"proof of another height", func(resp *abci.ResponseQuery) {
+			resp.Height--
+		}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			server := serveProofs(seller.CLIContext(), tamperingNode{Node: node, tamper: tc.tamper}, &clienttest.Verifier{}, height)
+			defer server.Close()
+
+			status, bz := request(t, "GET", server.URL+"/nameservice/names/alice/whois?prove=true", nil)
+			require.Equal(t, http.StatusBadGateway, status, string(bz))
+		})
+	}
+
+	// proofs cannot be verified without a light client
+	server := serveProofs(seller.CLIContext(), tamperingNode{Node: node}, nil, height)
+	defer server.Close()
+	status, bz := request(t, "GET", server.URL+"/nameservice/names/alice/whois?prove=true", nil)
+	require.Equal(t, http.StatusBadGateway, status, string(bz))
+}
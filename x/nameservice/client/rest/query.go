@@ -3,11 +3,12 @@ package rest
 import (
 	"fmt"
 	"net/http"
-	"os/exec"
-	"strings"
 
 	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/crypto/keys"
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
 
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/rest"
 
 	"github.com/gorilla/mux"
@@ -33,6 +34,11 @@ func whoIsHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc
 		vars := mux.Vars(r)
 		paramType := vars[restName]
 
+		if r.URL.Query().Get(proveParam) == "true" {
+			writeProvenWhois(cliCtx, storeName, w, paramType)
+			return
+		}
+
 		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/whois/%s", storeName, paramType), nil)
 		if err != nil {
 			rest.WriteErrorResponse(w, http.StatusNotFound, err.Error())
@@ -59,6 +65,11 @@ func queryProductHandler(cliCtx context.CLIContext, storeName string) http.Handl
 		vars := mux.Vars(r)
 		productID := vars["productID"]
 
+		if r.URL.Query().Get(proveParam) == "true" {
+			writeProvenProduct(cliCtx, storeName, w, productID)
+			return
+		}
+
 		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/product/%s", storeName, productID), nil)
 		if err != nil {
 			rest.WriteErrorResponse(w, http.StatusNotFound, err.Error())
@@ -71,29 +82,281 @@ func queryProductHandler(cliCtx context.CLIContext, storeName string) http.Handl
 
 func allProductsHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/allProducts", storeName), nil)
+		query := r.URL.Query()
+
+		var owner sdk.AccAddress
+		if ownerStr := query.Get("owner"); ownerStr != "" {
+			addr, err := sdk.AccAddressFromBech32(ownerStr)
+			if err != nil {
+				rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			owner = addr
+		}
+
+		minPrice, err := sdk.ParseCoins(query.Get("min_price"))
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		maxPrice, err := sdk.ParseCoins(query.Get("max_price"))
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		params := types.NewQueryProductsParams(query.Get("category"), query.Get("tag"), owner,
+			minPrice, maxPrice, query.Get("listed") == "true")
+
+		bz, err := cliCtx.Codec.MarshalJSON(params)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/products", storeName), bz)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+			return
+		}
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+func auctionHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		productID := vars["productID"]
+
+		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/auction/%s", storeName, productID), nil)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+func auctionsHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/auctions", storeName), nil)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+			return
+		}
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+func bidsHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		productID := vars["productID"]
+
+		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/bids/%s", storeName, productID), nil)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+func storefrontHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		paramType := vars[restName]
+
+		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/storefront/%s", storeName, paramType), nil)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+func resolveProductHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		paramType := vars[restName]
+		productID := vars["productID"]
+
+		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/resolveProduct/%s/%s", storeName, paramType, productID), nil)
 		if err != nil {
 			rest.WriteErrorResponse(w, http.StatusNotFound, err.Error())
 			return
 		}
+
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+func reviewsHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		productID := vars["productID"]
+
+		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/reviews/%s", storeName, productID), nil)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+			return
+		}
+
 		rest.PostProcessResponse(w, cliCtx, res)
 	}
 }
 
-func accAddressHandler(cliCtx context.CLIContext) http.HandlerFunc {
+func ratingHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		productID := vars["productID"]
+
+		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/rating/%s", storeName, productID), nil)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+func reputationHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		address := vars["address"]
+
+		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/reputation/%s", storeName, address), nil)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+func couponHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		codeHash := vars["codeHash"]
+
+		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/coupon/%s", storeName, codeHash), nil)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+func subscriptionHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		productID := vars["productID"]
+		address := vars["address"]
+
+		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/subscription/%s/%s", storeName, productID, address), nil)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+func subscriptionsHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		productID := vars["productID"]
+
+		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/subscriptions/%s", storeName, productID), nil)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+func productHistoryHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		productID := vars["productID"]
+
+		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/productHistory/%s", storeName, productID), nil)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+func hasLicenseHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		productID := vars["productID"]
+		address := vars["address"]
+
+		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/hasLicense/%s/%s", storeName, productID, address), nil)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+// passphraseHeader carries the passphrase of the file keyring backend in requests that
+// have no body, so that it stays out of the URL
+const passphraseHeader = "X-Keyring-Passphrase"
+
+// accAddressHandler looks a key of the REST server's keyring up by name and returns
+// its name, type, address and public key
+func accAddressHandler(cliCtx context.CLIContext, kr *Keyring) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
 		name := vars["name"]
 
-		cmd := exec.Command("nscli", "keys", "show", name, "-a")
-		stdout, err := cmd.Output()
+		if err := kr.authorize(r.Header.Get(passphraseHeader)); err != nil {
+			rest.WriteErrorResponse(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		// Listing tells an unknown key apart from a keyring that cannot be read,
+		// which every backend reports with a different error
+		infos, err := kr.keybase.List()
 		if err != nil {
-			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		for _, info := range infos {
+			if info.GetName() != name {
+				continue
+			}
+
+			output, err := keys.Bech32KeyOutput(info)
+			if err != nil {
+				rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+
+			rest.PostProcessResponse(w, cliCtx, output)
 			return
 		}
 
-		address := string(stdout)
-		result := strings.Trim(address, "\n")
-		rest.PostProcessResponse(w, cliCtx, result)
+		rest.WriteErrorResponse(w, http.StatusNotFound, fmt.Sprintf("key %s not found", name))
 	}
 }
@@ -20,13 +20,60 @@ func RegisterRoutes(cliCtx context.CLIContext, r *mux.Router, storeName string)
 	r.HandleFunc(fmt.Sprintf("/%s/names/{%s}", storeName, restName), resolveNameHandler(cliCtx, storeName)).Methods("GET")
 	r.HandleFunc(fmt.Sprintf("/%s/names/{%s}/whois", storeName, restName), whoIsHandler(cliCtx, storeName)).Methods("GET")
 	r.HandleFunc(fmt.Sprintf("/%s/names", storeName), deleteNameHandler(cliCtx)).Methods("DELETE")
+	r.HandleFunc(fmt.Sprintf("/%s/names/storefrontSale", storeName), setStorefrontSaleHandler(cliCtx)).Methods("PUT")
+	r.HandleFunc(fmt.Sprintf("/%s/names/{%s}/storefront", storeName, restName), storefrontHandler(cliCtx, storeName)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/%s/names/{%s}/storefront/{productID}", storeName, restName), resolveProductHandler(cliCtx, storeName)).Methods("GET")
 
 	r.HandleFunc(fmt.Sprintf("/%s/product", storeName), createProductHandler(cliCtx)).Methods("POST")
 	r.HandleFunc(fmt.Sprintf("/%s/product", storeName), updateProductHandler(cliCtx)).Methods("PUT")
 	r.HandleFunc(fmt.Sprintf("/%s/product/buyProduct", storeName), buyProductHandler(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/product/buyProducts", storeName), buyProductsHandler(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/product/listProduct", storeName), listProductHandler(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/product/delistProduct", storeName), delistProductHandler(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/product/publishProduct", storeName), publishProductHandler(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/product/unpublishProduct", storeName), unpublishProductHandler(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/product/reviewProduct", storeName), reviewProductHandler(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/product/pricing", storeName), setProductPricingHandler(cliCtx)).Methods("PUT")
+	r.HandleFunc(fmt.Sprintf("/%s/product/subscription", storeName), setProductSubscriptionHandler(cliCtx)).Methods("PUT")
+	r.HandleFunc(fmt.Sprintf("/%s/product/cancelSubscription", storeName), cancelSubscriptionHandler(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/product/licensing", storeName), setProductLicensingHandler(cliCtx)).Methods("PUT")
 	r.HandleFunc(fmt.Sprintf("/%s/product/{productID}", storeName), queryProductHandler(cliCtx, storeName)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/%s/product/{productID}/reviews", storeName), reviewsHandler(cliCtx, storeName)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/%s/product/{productID}/rating", storeName), ratingHandler(cliCtx, storeName)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/%s/product/{productID}/hasLicense/{address}", storeName), hasLicenseHandler(cliCtx, storeName)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/%s/product/{productID}/history", storeName), productHistoryHandler(cliCtx, storeName)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/%s/product/{productID}/subscriptions", storeName), subscriptionsHandler(cliCtx, storeName)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/%s/product/{productID}/subscriptions/{address}", storeName), subscriptionHandler(cliCtx, storeName)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/%s/seller/{address}/reputation", storeName), reputationHandler(cliCtx, storeName)).Methods("GET")
 	r.HandleFunc(fmt.Sprintf("/%s/product", storeName), allProductsHandler(cliCtx, storeName)).Methods("GET")
 
-	r.HandleFunc(fmt.Sprintf("/%s/name/{name}/address", storeName), accAddressHandler(cliCtx)).Methods("GET")
-	r.HandleFunc(fmt.Sprintf("/%s/tx/sign", storeName), signTxHandler(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/auction", storeName), createAuctionHandler(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/auction/bid", storeName), placeBidHandler(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/auction", storeName), auctionsHandler(cliCtx, storeName)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/%s/auction/{productID}", storeName), auctionHandler(cliCtx, storeName)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/%s/auction/{productID}/bids", storeName), bidsHandler(cliCtx, storeName)).Methods("GET")
+
+	r.HandleFunc(fmt.Sprintf("/%s/coupon", storeName), createCouponHandler(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/coupon", storeName), revokeCouponHandler(cliCtx)).Methods("DELETE")
+	r.HandleFunc(fmt.Sprintf("/%s/coupon/commit", storeName), commitCouponHandler(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/coupon/{codeHash}", storeName), couponHandler(cliCtx, storeName)).Methods("GET")
+
+	r.HandleFunc(fmt.Sprintf("/%s/tx/prepare", storeName), prepareTxHandler(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/tx/submit", storeName), submitTxHandler(cliCtx)).Methods("POST")
+
+	r.HandleFunc(fmt.Sprintf("/%s/events", storeName), eventsHandler(cliCtx, newEventHub(cliCtx))).Methods("GET")
+
+	registerV2Routes(cliCtx, r, storeName)
+
+	// the routes are documented in openapi.go, the specification is served along with Swagger UI
+	r.HandleFunc(fmt.Sprintf("/%s/swagger", storeName), swaggerUIHandler(storeName)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/%s/swagger/openapi.json", storeName), openAPIHandler(storeName)).Methods("GET")
+	r.Handle(fmt.Sprintf("/%s/swagger/{file}", storeName), swaggerAssetsHandler(storeName)).Methods("GET")
+}
+
+// RegisterKeyringRoutes defines the routes looking keys up in and signing with the keyring
+// of the REST server, which are left out of RegisterRoutes as they need the keyring
+func RegisterKeyringRoutes(cliCtx context.CLIContext, r *mux.Router, storeName string, kr *Keyring) {
+	r.HandleFunc(fmt.Sprintf("/%s/name/{name}/address", storeName), accAddressHandler(cliCtx, kr)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/%s/tx/sign", storeName), signTxHandler(cliCtx, kr)).Methods("POST")
 }
@@ -0,0 +1,359 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/tendermint/tendermint/crypto"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/crypto/keys"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/rest"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/auth/client/utils"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
+
+// signTxReq signs a transaction generated by one of the other endpoints with a key of
+// the keyring of the REST server and broadcasts it.
+//
+// The keyring is the one the REST server was started with (--keyring-backend). Passphrase
+// is the passphrase of the file backend and is ignored by the os and test backends, which
+// only sign when the server was started with --unsafe-keyring-signing.
+// AccountNumber and Sequence are looked up on chain when left empty. Mode is one of
+// sync, async or block and defaults to sync.
+type signTxReq struct {
+	BaseReq       rest.BaseReq `json:"base_req"`
+	Tx            string       `json:"tx"`
+	Sequence      string       `json:"sequence"`
+	AccountNumber string       `json:"accountNumber"`
+	Passphrase    string       `json:"passphrase"`
+	Mode          string       `json:"mode"`
+}
+
+func signTxHandler(cliCtx context.CLIContext, kr *Keyring) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req signTxReq
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		baseReq := req.BaseReq.Sanitize()
+		if !baseReq.ValidateBasic(w) {
+			return
+		}
+
+		mode, err := parseBroadcastMode(req.Mode)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		var stdTx auth.StdTx
+		if err := cliCtx.Codec.UnmarshalJSON([]byte(req.Tx), &stdTx); err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if err := kr.checkSigning(); err != nil {
+			rest.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+			return
+		}
+		if err := kr.authorize(req.Passphrase); err != nil {
+			rest.WriteErrorResponse(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		info, err := getKeyInfo(kr.keybase, baseReq.From)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		accountNumber, sequence, err := accountNumberSequence(cliCtx, info.GetAddress(), req.AccountNumber, req.Sequence)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		txBldr := auth.NewTxBuilder(
+			utils.GetTxEncoder(cliCtx.Codec), accountNumber, sequence, 0, 0, false,
+			baseReq.ChainID, stdTx.Memo, nil, nil,
+		).WithKeybase(kr.keybase)
+
+		signedTx, err := txBldr.SignStdTx(info.GetName(), req.Passphrase, stdTx, false)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		txBytes, err := txBldr.TxEncoder()(signedTx)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		res, err := cliCtx.WithBroadcastMode(mode).BroadcastTx(txBytes)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadGateway, err.Error())
+			return
+		}
+
+		writeTxResponse(w, cliCtx, res)
+	}
+}
+
+// getKeyInfo looks a key up by name or by address
+func getKeyInfo(kb keys.Keybase, nameOrAddress string) (keys.Info, error) {
+	if addr, err := sdk.AccAddressFromBech32(nameOrAddress); err == nil {
+		return kb.GetByAddress(addr)
+	}
+	return kb.Get(nameOrAddress)
+}
+
+// accountNumberSequence parses the account number and sequence of a signer, and
+// queries the ones left empty
+func accountNumberSequence(cliCtx context.CLIContext, addr sdk.AccAddress, accountNumber, sequence string) (uint64, uint64, error) {
+	var num, seq uint64
+	var err error
+
+	if accountNumber == "" || sequence == "" {
+		num, seq, err = auth.NewAccountRetriever(cliCtx).GetAccountNumberSequence(addr)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if accountNumber != "" {
+		if num, err = strconv.ParseUint(accountNumber, 10, 64); err != nil {
+			return 0, 0, fmt.Errorf("invalid account number %s", accountNumber)
+		}
+	}
+	if sequence != "" {
+		if seq, err = strconv.ParseUint(sequence, 10, 64); err != nil {
+			return 0, 0, fmt.Errorf("invalid sequence %s", sequence)
+		}
+	}
+
+	return num, seq, nil
+}
+
+// parseBroadcastMode validates a broadcast mode, the empty mode broadcasts synchronously
+func parseBroadcastMode(mode string) (string, error) {
+	switch mode {
+	case "":
+		return flags.BroadcastSync, nil
+	case flags.BroadcastSync, flags.BroadcastAsync, flags.BroadcastBlock:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid broadcast mode %s, expected one of %s, %s or %s",
+			mode, flags.BroadcastSync, flags.BroadcastAsync, flags.BroadcastBlock)
+	}
+}
+
+// writeTxResponse writes the result of a broadcast, with a status code telling why
+// the transaction was rejected when CheckTx or DeliverTx failed
+func writeTxResponse(w http.ResponseWriter, cliCtx context.CLIContext, res sdk.TxResponse) {
+	output, err := codec.MarshalJSONIndent(cliCtx.Codec, res)
+	if err != nil {
+		rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(txStatusCode(res))
+	_, _ = w.Write(output)
+}
+
+// txStatusCode maps the ABCI code of a broadcast transaction to an HTTP status code
+func txStatusCode(res sdk.TxResponse) int {
+	if res.Code == 0 {
+		return http.StatusOK
+	}
+
+	// sync broadcasts leave the codespace out, their errors are the ones of the ante
+	// handler of the SDK
+	codespace := res.Codespace
+	if codespace == "" {
+		codespace = sdkerrors.RootCodespace
+	}
+	return errorStatusCode(sdkerrors.ABCIError(codespace, res.Code, res.RawLog))
+}
+
+// errorStatusCode maps an error of the SDK or of the module to an HTTP status code
+func errorStatusCode(err error) int {
+	switch {
+	case errors.Is(err, sdkerrors.ErrUnauthorized), errors.Is(err, sdkerrors.ErrInvalidPubKey):
+		return http.StatusForbidden
+	case errors.Is(err, sdkerrors.ErrInsufficientFunds), errors.Is(err, sdkerrors.ErrInsufficientFee):
+		return http.StatusPaymentRequired
+	case errors.Is(err, sdkerrors.ErrInvalidSequence), errors.Is(err, sdkerrors.ErrTxInMempoolCache),
+		errors.Is(err, types.ErrProductVersionMismatch):
+		return http.StatusConflict
+	case errors.Is(err, sdkerrors.ErrUnknownAddress), errors.Is(err, types.ErrNameDoesNotExist),
+		errors.Is(err, types.ErrProductDoesNotExist), errors.Is(err, types.ErrAuctionDoesNotExist),
+		errors.Is(err, types.ErrCouponDoesNotExist), errors.Is(err, types.ErrSubscriptionDoesNotExist):
+		return http.StatusNotFound
+	case errors.Is(err, sdkerrors.ErrMempoolIsFull):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusUnprocessableEntity
+	}
+}
+
+// prepareTxReq asks for what an external signer needs to sign a transaction generated by
+// one of the other endpoints. BaseReq.From is the address of the signer.
+type prepareTxReq struct {
+	BaseReq rest.BaseReq `json:"base_req"`
+	Tx      auth.StdTx   `json:"tx"`
+}
+
+// prepareTxRes carries the account metadata of the signer together with the sign
+// document, whose UTF-8 bytes are what the signer signs
+type prepareTxRes struct {
+	ChainID       string `json:"chain_id"`
+	AccountNumber uint64 `json:"account_number"`
+	Sequence      uint64 `json:"sequence"`
+	SignDoc       string `json:"sign_doc"`
+}
+
+func prepareTxHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req prepareTxReq
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		baseReq := req.BaseReq.Sanitize()
+		if !baseReq.ValidateBasic(w) {
+			return
+		}
+
+		signer, err := sdk.AccAddressFromBech32(baseReq.From)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if !isSigner(req.Tx, signer) {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("%s is not a signer of the transaction", signer))
+			return
+		}
+
+		accountNumber, sequence, err := accountNumberSequence(cliCtx, signer, "", "")
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		signBytes := auth.StdSignBytes(baseReq.ChainID, accountNumber, sequence, req.Tx.Fee, req.Tx.Msgs, req.Tx.Memo)
+
+		rest.PostProcessResponse(w, cliCtx, prepareTxRes{
+			ChainID:       baseReq.ChainID,
+			AccountNumber: accountNumber,
+			Sequence:      sequence,
+			SignDoc:       string(signBytes),
+		})
+	}
+}
+
+// submitTxReq hands in the signature made by an external signer over the sign document
+// returned by the prepare endpoint. The signature is checked before the transaction is
+// broadcast in Mode, which is one of sync, async or block and defaults to sync.
+type submitTxReq struct {
+	Tx            auth.StdTx    `json:"tx"`
+	ChainID       string        `json:"chain_id"`
+	AccountNumber string        `json:"account_number"`
+	Sequence      string        `json:"sequence"`
+	PubKey        crypto.PubKey `json:"pub_key"`
+	Signature     []byte        `json:"signature"`
+	Mode          string        `json:"mode"`
+}
+
+func submitTxHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req submitTxReq
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		if req.ChainID == "" {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "chain-id required but not specified")
+			return
+		}
+
+		if req.PubKey == nil || len(req.Signature) == 0 {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "pub_key and signature are required")
+			return
+		}
+
+		mode, err := parseBroadcastMode(req.Mode)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// Signatures are expected in the order of the signers
+		signers := req.Tx.GetSigners()
+		if len(req.Tx.Signatures) >= len(signers) {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "transaction is already signed by all its signers")
+			return
+		}
+
+		signer := sdk.AccAddress(req.PubKey.Address())
+		if !signer.Equals(signers[len(req.Tx.Signatures)]) {
+			rest.WriteErrorResponse(w, http.StatusUnauthorized,
+				fmt.Sprintf("pub_key of %s does not belong to the next signer %s", signer, signers[len(req.Tx.Signatures)]))
+			return
+		}
+
+		accountNumber, sequence, err := accountNumberSequence(cliCtx, signer, req.AccountNumber, req.Sequence)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		signBytes := auth.StdSignBytes(req.ChainID, accountNumber, sequence, req.Tx.Fee, req.Tx.Msgs, req.Tx.Memo)
+		if !req.PubKey.VerifyBytes(signBytes, req.Signature) {
+			rest.WriteErrorResponse(w, http.StatusUnauthorized, "signature does not match the sign document")
+			return
+		}
+
+		signedTx := req.Tx
+		signedTx.Signatures = append(signedTx.Signatures, auth.StdSignature{PubKey: req.PubKey, Signature: req.Signature})
+
+		txBytes, err := utils.GetTxEncoder(cliCtx.Codec)(signedTx)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		res, err := cliCtx.WithBroadcastMode(mode).BroadcastTx(txBytes)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadGateway, err.Error())
+			return
+		}
+
+		writeTxResponse(w, cliCtx, res)
+	}
+}
+
+// isSigner returns whether an address has to sign a transaction
+func isSigner(tx auth.StdTx, addr sdk.AccAddress) bool {
+	for _, signer := range tx.GetSigners() {
+		if signer.Equals(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,352 @@
+package rest_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/crypto/bcrypt"
+	"github.com/tendermint/tendermint/crypto"
+
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/crypto/keys"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkrest "github.com/cosmos/cosmos-sdk/types/rest"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/client"
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/client/clienttest"
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/client/rest"
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
+
+// setupSign starts an app in which a seller has an account, and returns the client of
+// the seller and a server of the keyring routes of the module, whose keyring is the
+// keybase of the client and asks for its passphrase
+func setupSign(t *testing.T) (client.Client, *httptest.Server) {
+	_, clients := clienttest.Setup(t, "seller")
+	seller := clients[0]
+
+	kr, err := rest.NewKeyring(seller.TxBuilder().Keybase(), clienttest.Passphrase)
+	require.NoError(t, err)
+
+	r := mux.NewRouter()
+	rest.RegisterKeyringRoutes(seller.CLIContext(), r, "nameservice", kr)
+	return seller, httptest.NewServer(r)
+}
+
+type signReq struct {
+	BaseReq    sdkrest.BaseReq `json:"base_req"`
+	Tx         string          `json:"tx"`
+	Sequence   string          `json:"sequence"`
+	Passphrase string          `json:"passphrase"`
+	Mode       string          `json:"mode"`
+}
+
+// signTx asks a server to sign and broadcast a transaction of a message with a key
+func signTx(t *testing.T, c client.Client, server *httptest.Server, req signReq, msg sdk.Msg) (int, []byte) {
+	cdc := c.CLIContext().Codec
+	tx, err := cdc.MarshalJSON(auth.NewStdTx([]sdk.Msg{msg}, auth.NewStdFee(flags.DefaultGasLimit, nil), nil, ""))
+	require.NoError(t, err)
+	req.Tx = string(tx)
+	if req.BaseReq.ChainID == "" {
+		req.BaseReq.ChainID = clienttest.ChainID
+	}
+
+	return request(t, "POST", server.URL+"/nameservice/tx/sign", cdc.MustMarshalJSON(req))
+}
+
+func TestSignTxModes(t *testing.T) {
+	seller, server := setupSign(t)
+	defer server.Close()
+	addr := seller.CLIContext().GetFromAddress()
+	price := sdk.NewCoins(sdk.NewInt64Coin("nametoken", 10))
+
+	for _, mode := range []string{"", flags.BroadcastSync, flags.BroadcastAsync, flags.BroadcastBlock} {
+		t.Run("mode "+mode, func(t *testing.T) {
+			name := "name" + mode
+			req := signReq{BaseReq: sdkrest.BaseReq{From: addr.String()}, Passphrase: clienttest.Passphrase, Mode: mode}
+			status, bz := signTx(t, seller, server, req, types.NewMsgBuyName(name, price, addr))
+			require.Equal(t, http.StatusOK, status, string(bz))
+			var res sdk.TxResponse
+			require.NoError(t, seller.CLIContext().Codec.UnmarshalJSON(bz, &res))
+			require.NotEmpty(t, res.TxHash)
+			if mode == flags.BroadcastBlock {
+				require.NotZero(t, res.Height)
+			}
+
+			whois, err := seller.Whois(name)
+			require.NoError(t, err)
+			require.Equal(t, addr, whois.Owner)
+		})
+	}
+}
+
+func TestSignTxErrors(t *testing.T) {
+	seller, server := setupSign(t)
+	defer server.Close()
+	addr := seller.CLIContext().GetFromAddress()
+	buyName := types.NewMsgBuyName("alice", sdk.NewCoins(sdk.NewInt64Coin("nametoken", 10)), addr)
+	stranger := sdk.AccAddress([]byte("stranger____________"))
+
+	tests := []struct {
+		name   string
+		req    signReq
+		msg    sdk.Msg
+		status int
+	}{
+		{"invalid mode", signReq{BaseReq: sdkrest.BaseReq{From: addr.String()}, Mode: "fast"}, buyName, http.StatusBadRequest},
+		{"unknown key", signReq{BaseReq: sdkrest.BaseReq{From: stranger.String()}}, buyName, http.StatusNotFound},
+		{"invalid sequence", signReq{BaseReq: sdkrest.BaseReq{From: addr.String()}, Sequence: "first"}, buyName, http.StatusBadRequest},
+		// signed for another sequence or chain, the signature does not verify
+		{"other sequence", signReq{BaseReq: sdkrest.BaseReq{From: addr.String()}, Sequence: "7", Mode: "block"},
+			buyName, http.StatusForbidden},
+		{"other chain", signReq{BaseReq: sdkrest.BaseReq{From: addr.String(), ChainID: "other-chain"}, Mode: "sync"},
+			buyName, http.StatusForbidden},
+		{"insufficient funds", signReq{BaseReq: sdkrest.BaseReq{From: addr.String()}, Mode: "block"},
+			types.NewMsgBuyName("alice", sdk.NewCoins(sdk.NewInt64Coin("nametoken", 5000)), addr), http.StatusPaymentRequired},
+		{"unknown name", signReq{BaseReq: sdkrest.BaseReq{From: addr.String()}, Mode: "block"},
+			types.NewMsgDeleteName("bob", addr), http.StatusNotFound},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := tc.req
+			req.Passphrase = clienttest.Passphrase
+			status, bz := signTx(t, seller, server, req, tc.msg)
+			require.Equal(t, tc.status, status, string(bz))
+		})
+	}
+}
+
+func TestSignTxKeyring(t *testing.T) {
+	seller, server := setupSign(t)
+	defer server.Close()
+	addr := seller.CLIContext().GetFromAddress()
+	buyName := types.NewMsgBuyName("alice", sdk.NewCoins(sdk.NewInt64Coin("nametoken", 10)), addr)
+
+	for _, passphrase := range []string{"", "wrong"} {
+		req := signReq{BaseReq: sdkrest.BaseReq{From: addr.String()}, Passphrase: passphrase, Mode: flags.BroadcastBlock}
+		status, _ := signTx(t, seller, server, req, buyName)
+		require.Equal(t, http.StatusUnauthorized, status)
+	}
+
+	// the os and test backends do not sign unless the server lets them
+	dir, err := ioutil.TempDir("", "keyring")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	for _, unsafeSigning := range []bool{false, true} {
+		kr, err := rest.OpenKeyring(keys.BackendTest, dir, nil, unsafeSigning)
+		require.NoError(t, err)
+		r := mux.NewRouter()
+		rest.RegisterKeyringRoutes(seller.CLIContext(), r, "nameservice", kr)
+		testServer := httptest.NewServer(r)
+		defer testServer.Close()
+
+		status, bz := signTx(t, seller, testServer, signReq{BaseReq: sdkrest.BaseReq{From: addr.String()}}, buyName)
+		if unsafeSigning {
+			// the keyring is empty
+			require.Equal(t, http.StatusNotFound, status, string(bz))
+		} else {
+			require.Equal(t, http.StatusForbidden, status, string(bz))
+		}
+	}
+}
+
+func TestOpenKeyring(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keyring")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// the server does not create the file keyring
+	_, err = rest.OpenKeyring(keys.BackendFile, dir, strings.NewReader("chosen\n"), false)
+	require.Error(t, err)
+	keyringDir := filepath.Join(dir, "keyring-"+sdk.KeyringServiceName())
+	_, err = os.Stat(keyringDir)
+	require.True(t, os.IsNotExist(err))
+
+	// and opens it with its passphrase only
+	keyhash, err := bcrypt.GenerateFromPassword(make([]byte, 16), []byte("passphrase"), 2)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(keyringDir, 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(keyringDir, "keyhash"), keyhash, 0600))
+
+	_, err = rest.OpenKeyring(keys.BackendFile, dir, strings.NewReader("wrong\n"), false)
+	require.Error(t, err)
+	_, err = rest.OpenKeyring(keys.BackendFile, dir, strings.NewReader("passphrase\n"), false)
+	require.NoError(t, err)
+}
+
+type prepareRes struct {
+	ChainID       string `json:"chain_id"`
+	AccountNumber uint64 `json:"account_number"`
+	Sequence      uint64 `json:"sequence"`
+	SignDoc       string `json:"sign_doc"`
+}
+
+type submitReq struct {
+	Tx            auth.StdTx    `json:"tx"`
+	ChainID       string        `json:"chain_id"`
+	AccountNumber string        `json:"account_number"`
+	Sequence      string        `json:"sequence"`
+	PubKey        crypto.PubKey `json:"pub_key"`
+	Signature     []byte        `json:"signature"`
+	Mode          string        `json:"mode"`
+}
+
+// prepareTx asks a server for the sign document of a transaction of a message signed by
+// the account of a client
+func prepareTx(t *testing.T, c client.Client, server *httptest.Server, msg sdk.Msg) (auth.StdTx, prepareRes) {
+	cdc := c.CLIContext().Codec
+	tx := auth.NewStdTx([]sdk.Msg{msg}, auth.NewStdFee(flags.DefaultGasLimit, nil), nil, "")
+	req := struct {
+		BaseReq sdkrest.BaseReq `json:"base_req"`
+		Tx      auth.StdTx      `json:"tx"`
+	}{sdkrest.BaseReq{From: c.CLIContext().GetFromAddress().String(), ChainID: clienttest.ChainID}, tx}
+
+	status, bz := request(t, "POST", server.URL+"/nameservice/tx/prepare", cdc.MustMarshalJSON(req))
+	require.Equal(t, http.StatusOK, status, string(bz))
+	var wrapped struct {
+		Result json.RawMessage `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(bz, &wrapped))
+	var res prepareRes
+	require.NoError(t, cdc.UnmarshalJSON(wrapped.Result, &res))
+	return tx, res
+}
+
+// signDoc signs a sign document with the key of a client
+func signDoc(t *testing.T, c client.Client, doc string) ([]byte, crypto.PubKey) {
+	sig, pubKey, err := c.TxBuilder().Keybase().Sign(c.CLIContext().GetFromName(), clienttest.Passphrase, []byte(doc))
+	require.NoError(t, err)
+	return sig, pubKey
+}
+
+func submitTx(t *testing.T, c client.Client, server *httptest.Server, req submitReq) (int, []byte) {
+	return request(t, "POST", server.URL+"/nameservice/tx/submit", c.CLIContext().Codec.MustMarshalJSON(req))
+}
+
+func TestPrepareSubmitTx(t *testing.T) {
+	_, clients := clienttest.Setup(t, "seller", "buyer")
+	seller, buyer := clients[0], clients[1]
+	addr := seller.CLIContext().GetFromAddress()
+	r := mux.NewRouter()
+	rest.RegisterRoutes(seller.CLIContext(), r, "nameservice")
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	tx, prepared := prepareTx(t, seller, server, types.NewMsgBuyName("alice", sdk.NewCoins(sdk.NewInt64Coin("nametoken", 10)), addr))
+	require.Equal(t, string(auth.StdSignBytes(clienttest.ChainID, prepared.AccountNumber, prepared.Sequence,
+		tx.Fee, tx.Msgs, tx.Memo)), prepared.SignDoc)
+	sig, pubKey := signDoc(t, seller, prepared.SignDoc)
+	valid := submitReq{
+		Tx:            tx,
+		ChainID:       prepared.ChainID,
+		AccountNumber: strconv.FormatUint(prepared.AccountNumber, 10),
+		Sequence:      strconv.FormatUint(prepared.Sequence, 10),
+		PubKey:        pubKey,
+		Signature:     sig,
+		Mode:          flags.BroadcastBlock,
+	}
+
+	// the signer has to prepare the transaction it signs
+	_, buyerPrepared := prepareTx(t, buyer, server, types.NewMsgBuyName("bob", sdk.NewCoins(sdk.NewInt64Coin("nametoken", 10)),
+		buyer.CLIContext().GetFromAddress()))
+	buyerSig, buyerPubKey := signDoc(t, buyer, buyerPrepared.SignDoc)
+
+	tamperedFee := tx
+	tamperedFee.Fee = auth.NewStdFee(flags.DefaultGasLimit, sdk.NewCoins(sdk.NewInt64Coin("nametoken", 1)))
+
+	tests := []struct {
+		name   string
+		modify func(req *submitReq)
+		status int
+	}{
+		{"pub key of another account", func(req *submitReq) { req.PubKey = buyerPubKey }, http.StatusUnauthorized},
+		{"signature of another account", func(req *submitReq) { req.PubKey, req.Signature = buyerPubKey, buyerSig },
+			http.StatusUnauthorized},
+		{"other sequence", func(req *submitReq) { req.Sequence = "7" }, http.StatusUnauthorized},
+		{"other account number", func(req *submitReq) { req.AccountNumber = "7" }, http.StatusUnauthorized},
+		{"other chain", func(req *submitReq) { req.ChainID = "other-chain" }, http.StatusUnauthorized},
+		{"tampered fee", func(req *submitReq) { req.Tx = tamperedFee }, http.StatusUnauthorized},
+		{"missing signature", func(req *submitReq) { req.Signature = nil }, http.StatusBadRequest},
+		{"invalid mode", func(req *submitReq) { req.Mode = "fast" }, http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := valid
+			tc.modify(&req)
+			status, bz := submitTx(t, seller, server, req)
+			require.Equal(t, tc.status, status, string(bz))
+		})
+	}
+
+	// none of the rejected transactions was broadcast, the valid one is
+	status, bz := submitTx(t, seller, server, valid)
+	require.Equal(t, http.StatusOK, status, string(bz))
+	whois, err := seller.Whois("alice")
+	require.NoError(t, err)
+	require.Equal(t, addr, whois.Owner)
+
+	// and cannot be replayed once its sequence is used
+	status, bz = submitTx(t, seller, server, valid)
+	require.Equal(t, http.StatusForbidden, status, string(bz))
+}
+
+func TestAccAddress(t *testing.T) {
+	seller, server := setupSign(t)
+	defer server.Close()
+
+	lookUp := func(url, passphrase string) (int, []byte) {
+		req, err := http.NewRequest("GET", url, nil)
+		require.NoError(t, err)
+		if passphrase != "" {
+			req.Header.Set("X-Keyring-Passphrase", passphrase)
+		}
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		bz, err := ioutil.ReadAll(res.Body)
+		require.NoError(t, err)
+		return res.StatusCode, bz
+	}
+
+	status, bz := lookUp(server.URL+"/nameservice/name/seller/address", clienttest.Passphrase)
+	require.Equal(t, http.StatusOK, status, string(bz))
+	var res struct {
+		Result keys.KeyOutput `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(bz, &res))
+	require.Equal(t, "seller", res.Result.Name)
+	require.Equal(t, seller.CLIContext().GetFromAddress().String(), res.Result.Address)
+
+	status, bz = lookUp(server.URL+"/nameservice/name/buyer/address", clienttest.Passphrase)
+	require.Equal(t, http.StatusNotFound, status, string(bz))
+
+	// the passphrase of the keyring is checked before the keys are listed
+	for _, passphrase := range []string{"", "wrong"} {
+		status, bz = lookUp(server.URL+"/nameservice/name/seller/address", passphrase)
+		require.Equal(t, http.StatusUnauthorized, status, string(bz))
+	}
+
+	// and not asked for by keyrings without one
+	kr, err := rest.NewKeyring(seller.TxBuilder().Keybase(), "")
+	require.NoError(t, err)
+	r := mux.NewRouter()
+	rest.RegisterKeyringRoutes(seller.CLIContext(), r, "nameservice", kr)
+	open := httptest.NewServer(r)
+	defer open.Close()
+
+	status, bz = lookUp(open.URL+"/nameservice/name/seller/address", "")
+	require.Equal(t, http.StatusOK, status, string(bz))
+}
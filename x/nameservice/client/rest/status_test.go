@@ -0,0 +1,69 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
+
+func TestErrorStatusCode(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+	}{
+		{sdkerrors.ErrUnauthorized, http.StatusForbidden},
+		{sdkerrors.ErrInvalidPubKey, http.StatusForbidden},
+		{sdkerrors.ErrInsufficientFunds, http.StatusPaymentRequired},
+		{sdkerrors.ErrInsufficientFee, http.StatusPaymentRequired},
+		{sdkerrors.ErrInvalidSequence, http.StatusConflict},
+		{sdkerrors.ErrTxInMempoolCache, http.StatusConflict},
+		{types.ErrProductVersionMismatch, http.StatusConflict},
+		{sdkerrors.ErrUnknownAddress, http.StatusNotFound},
+		{types.ErrNameDoesNotExist, http.StatusNotFound},
+		{types.ErrProductDoesNotExist, http.StatusNotFound},
+		{types.ErrAuctionDoesNotExist, http.StatusNotFound},
+		{types.ErrCouponDoesNotExist, http.StatusNotFound},
+		{types.ErrSubscriptionDoesNotExist, http.StatusNotFound},
+		{sdkerrors.ErrMempoolIsFull, http.StatusServiceUnavailable},
+		{sdkerrors.ErrInvalidCoins, http.StatusUnprocessableEntity},
+		{errors.New("unknown"), http.StatusUnprocessableEntity},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.err.Error(), func(t *testing.T) {
+			require.Equal(t, tc.status, errorStatusCode(tc.err))
+			require.Equal(t, tc.status, errorStatusCode(sdkerrors.Wrap(tc.err, "wrapped")))
+		})
+	}
+}
+
+func TestTxStatusCode(t *testing.T) {
+	codespace, code, _ := sdkerrors.ABCIInfo(types.ErrNameDoesNotExist, false)
+
+	tests := []struct {
+		name   string
+		res    sdk.TxResponse
+		status int
+	}{
+		{"accepted", sdk.TxResponse{}, http.StatusOK},
+		{"module error", sdk.TxResponse{Codespace: codespace, Code: code}, http.StatusNotFound},
+		{"sdk error", sdk.TxResponse{Codespace: sdkerrors.RootCodespace, Code: sdkerrors.ErrInsufficientFee.ABCICode()},
+			http.StatusPaymentRequired},
+		// sync broadcasts leave the codespace of the ante handler out
+		{"sync error", sdk.TxResponse{Code: sdkerrors.ErrUnauthorized.ABCICode()}, http.StatusForbidden},
+		{"unknown error", sdk.TxResponse{Codespace: "other", Code: code}, http.StatusUnprocessableEntity},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.status, txStatusCode(tc.res))
+		})
+	}
+}
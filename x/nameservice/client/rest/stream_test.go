@@ -0,0 +1,230 @@
+package rest_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/client"
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/client/clienttest"
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/client/rest"
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
+
+type change struct {
+	Height        int64  `json:"height,string"`
+	TxHash        string `json:"txhash"`
+	Action        string `json:"action"`
+	Name          string `json:"name"`
+	ProductID     string `json:"product_id"`
+	Owner         string `json:"owner"`
+	PreviousOwner string `json:"previous_owner"`
+}
+
+// racingNode commits a block of its own when it is first asked for its status after
+// being armed, as the replay of a stream does, so that the block reaches the stream
+// both live and in the replay
+type racingNode struct {
+	*clienttest.Node
+	mtx    sync.Mutex
+	commit func()
+}
+
+func (n *racingNode) Status() (*ctypes.ResultStatus, error) {
+	n.mtx.Lock()
+	commit := n.commit
+	n.commit = nil
+	n.mtx.Unlock()
+
+	if commit != nil {
+		commit()
+	}
+	return n.Node.Status()
+}
+
+func (n *racingNode) arm(commit func()) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	n.commit = commit
+}
+
+// setupEvents starts an app in which a seller owns the name alice and the products
+// book1, listed, and book2, published under alice, and returns the clients of the
+// seller and of a buyer, the node and a server of the routes of the module
+func setupEvents(t *testing.T) (seller, buyer client.Client, node *racingNode, server *httptest.Server) {
+	inner, clients := clienttest.Setup(t, "seller", "buyer")
+	seller, buyer = clients[0], clients[1]
+	node = &racingNode{Node: inner}
+
+	price := sdk.NewCoins(sdk.NewInt64Coin("nametoken", 10))
+	_, err := seller.BuyName("alice", price)
+	require.NoError(t, err)
+	for _, productID := range []string{"book1", "book2"} {
+		_, err = seller.CreateProduct(productID, "a book", price, "books", nil, types.Content{})
+		require.NoError(t, err)
+	}
+	_, err = seller.ListProduct("book1")
+	require.NoError(t, err)
+	_, err = seller.PublishProduct("alice", "book2")
+	require.NoError(t, err)
+	_, err = seller.SetStorefrontSale("alice", true)
+	require.NoError(t, err)
+
+	r := mux.NewRouter()
+	rest.RegisterRoutes(seller.CLIContext().WithClient(node), r, "nameservice")
+	return seller, buyer, node, httptest.NewServer(r)
+}
+
+func dialEvents(t *testing.T, server *httptest.Server, query string) *websocket.Conn {
+	url := fmt.Sprintf("ws%s/nameservice/events?%s", strings.TrimPrefix(server.URL, "http"), query)
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	return conn
+}
+
+// readChanges reads n changes from a stream
+func readChanges(t *testing.T, conn *websocket.Conn, n int) []change {
+	changes := make([]change, n)
+	for i := range changes {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(30*time.Second)))
+		_, bz, err := conn.ReadMessage()
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(bz, &changes[i]))
+	}
+	return changes
+}
+
+// summary leaves the action, name, product and owners of changes
+func summary(changes []change) []string {
+	lines := make([]string, len(changes))
+	for i, c := range changes {
+		lines[i] = strings.Join([]string{c.Action, c.Name, c.ProductID, c.Owner, c.PreviousOwner}, " ")
+	}
+	return lines
+}
+
+func TestEventStreamOwner(t *testing.T) {
+	seller, buyer, node, server := setupEvents(t)
+	defer server.Close()
+	sellerAddr := seller.CLIContext().GetFromAddress().String()
+	buyerAddr := buyer.CLIContext().GetFromAddress().String()
+
+	conn := dialEvents(t, server, "owner="+sellerAddr+"&from_height=2")
+	defer conn.Close()
+
+	// the replay tells the changes of the setup, with the name of the storefront of book2
+	require.Equal(t, []string{
+		"buy_name alice  " + sellerAddr + " ",
+		"create_product  book1 " + sellerAddr + " ",
+		"create_product  book2 " + sellerAddr + " ",
+		"list_product  book1 " + sellerAddr + " ",
+		"publish_product alice book2 " + sellerAddr + " ",
+		"set_storefront_sale alice  " + sellerAddr + " ",
+	}, summary(readChanges(t, conn, 6)))
+
+	// a sale by someone else reaches the seller
+	_, err := buyer.BuyProduct("book1", "nametoken", "", 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"buy_product  book1 " + buyerAddr + " " + sellerAddr,
+	}, summary(readChanges(t, conn, 1)))
+
+	// so does the settlement of an auction at the end of a block
+	price := sdk.NewCoins(sdk.NewInt64Coin("nametoken", 10))
+	_, err = seller.CreateProduct("album1", "an album", price, "music", nil, types.Content{})
+	require.NoError(t, err)
+	_, err = seller.CreateAuction("album1", types.AuctionEnglish, price, nil, nil, 2)
+	require.NoError(t, err)
+	_, err = buyer.PlaceBid("album1", sdk.NewCoins(sdk.NewInt64Coin("nametoken", 20)))
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		node.CommitBlock()
+	}
+
+	changes := readChanges(t, conn, 5)
+	require.Equal(t, []string{
+		"create_product  album1 " + sellerAddr + " ",
+		"create_auction  album1 " + sellerAddr + " ",
+		"place_bid  album1 " + sellerAddr + " ",
+		"end_block  album1 " + buyerAddr + " " + sellerAddr,
+		"end_block  album1 " + sellerAddr + " ",
+	}, summary(changes))
+	require.Empty(t, changes[3].TxHash)
+
+	// and the handover of a storefront
+	_, err = buyer.BuyName("alice", sdk.NewCoins(sdk.NewInt64Coin("nametoken", 20)))
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"buy_name alice book2 " + buyerAddr + " " + sellerAddr,
+		"buy_name alice  " + buyerAddr + " " + sellerAddr,
+	}, summary(readChanges(t, conn, 2)))
+}
+
+func TestEventStreamReplay(t *testing.T) {
+	seller, _, node, server := setupEvents(t)
+	defer server.Close()
+	latest := node.Height()
+
+	// a block is committed once the stream listens, before its replay reads the latest
+	// height: it is replayed and must not be sent live again
+	var racingErr error
+	node.arm(func() { _, racingErr = seller.DelistProduct("book1") })
+
+	conn := dialEvents(t, server, fmt.Sprintf("from_height=%d", latest))
+	defer conn.Close()
+
+	changes := readChanges(t, conn, 2)
+	require.NoError(t, racingErr)
+	require.Equal(t, []string{"set_storefront_sale", "delist_product"}, []string{changes[0].Action, changes[1].Action})
+	require.Equal(t, []int64{latest, latest + 1}, []int64{changes[0].Height, changes[1].Height})
+
+	_, err := seller.ListProduct("book1")
+	require.NoError(t, err)
+	changes = readChanges(t, conn, 1)
+	require.Equal(t, "list_product", changes[0].Action)
+	require.Equal(t, latest+2, changes[0].Height)
+
+	// replays are bounded
+	code, _ := request(t, "GET", server.URL+"/nameservice/events?from_height=0", nil)
+	require.Equal(t, 400, code)
+}
+
+func TestEventStreamReleasesSubscription(t *testing.T) {
+	seller, _, node, server := setupEvents(t)
+	defer server.Close()
+	require.Equal(t, 0, node.Subscriptions())
+
+	first := dialEvents(t, server, "product_id=book1")
+	second := dialEvents(t, server, "product_id=book1")
+	require.Equal(t, 1, node.Subscriptions())
+
+	require.NoError(t, first.Close())
+	_, err := seller.DelistProduct("book1")
+	require.NoError(t, err)
+	require.Equal(t, "delist_product", readChanges(t, second, 1)[0].Action)
+	require.Equal(t, 1, node.Subscriptions())
+
+	// the subscription to the node is released along with the last stream
+	require.NoError(t, second.Close())
+	require.Eventually(t, func() bool { return node.Subscriptions() == 0 }, 5*time.Second, 10*time.Millisecond)
+
+	// and opened again for the next one
+	third := dialEvents(t, server, "product_id=book1")
+	defer third.Close()
+	require.Equal(t, 1, node.Subscriptions())
+
+	_, err = seller.ListProduct("book1")
+	require.NoError(t, err)
+	require.Equal(t, "list_product", readChanges(t, third, 1)[0].Action)
+}
@@ -0,0 +1,79 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/rakyll/statik/fs"
+
+	"github.com/cosmos/cosmos-sdk/types/rest"
+
+	// the Swagger UI bundled with the SDK
+	_ "github.com/cosmos/cosmos-sdk/client/lcd/statik"
+)
+
+// swaggerUIPage loads the Swagger UI assets and the specification from the routes under
+// /<storeName>/swagger
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+  <head>
+    <meta charset="UTF-8">
+    <title>Nameservice REST API</title>
+    <link rel="stylesheet" type="text/css" href="/%[1]s/swagger/swagger-ui.css">
+  </head>
+  <body>
+    <div id="swagger-ui"></div>
+    <script src="/%[1]s/swagger/swagger-ui-bundle.js"></script>
+    <script src="/%[1]s/swagger/swagger-ui-standalone-preset.js"></script>
+    <script>
+      window.onload = function() {
+        window.ui = SwaggerUIBundle({
+          url: "/%[1]s/swagger/openapi.json",
+          dom_id: "#swagger-ui",
+          deepLinking: true,
+          presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+          layout: "StandaloneLayout"
+        })
+      }
+    </script>
+  </body>
+</html>
+`
+
+// swaggerUIHandler serves a Swagger UI page browsing the specification of the routes
+func swaggerUIHandler(storeName string) http.HandlerFunc {
+	page := fmt.Sprintf(swaggerUIPage, storeName)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(page))
+	}
+}
+
+// openAPIHandler serves the OpenAPI specification of the routes registered under a store name
+func openAPIHandler(storeName string) http.HandlerFunc {
+	spec, err := json.MarshalIndent(newOpenAPISpec(storeName), "", "  ")
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(spec)
+	}
+}
+
+// swaggerAssetsHandler serves the assets of the Swagger UI bundled with the SDK
+func swaggerAssetsHandler(storeName string) http.Handler {
+	statikFS, err := fs.New()
+	if err != nil {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		})
+	}
+
+	return http.StripPrefix(fmt.Sprintf("/%s/swagger", storeName), http.FileServer(statikFS))
+}
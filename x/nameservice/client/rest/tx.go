@@ -1,9 +1,8 @@
 package rest
 
 import (
-	"io/ioutil"
 	"net/http"
-	"os/exec"
+	"strconv"
 
 	"github.com/cosmos/cosmos-sdk/client/context"
 	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
@@ -134,10 +133,13 @@ func deleteNameHandler(cliCtx context.CLIContext) http.HandlerFunc {
 }
 
 type createProductReq struct {
-	BaseReq     rest.BaseReq `json:"base_req"`
-	ProductID   string       `json:"productID"`
-	Description string       `json:"description"`
-	Price       string       `json:"price"`
+	BaseReq     rest.BaseReq  `json:"base_req"`
+	ProductID   string        `json:"productID"`
+	Description string        `json:"description"`
+	Price       string        `json:"price"`
+	Category    string        `json:"category"`
+	Tags        []string      `json:"tags"`
+	Content     types.Content `json:"content"`
 }
 
 func createProductHandler(cliCtx context.CLIContext) http.HandlerFunc {
@@ -167,7 +169,7 @@ func createProductHandler(cliCtx context.CLIContext) http.HandlerFunc {
 		}
 
 		// create the message
-		msg := types.NewMsgCreateProduct(req.ProductID, req.Description, price, signer)
+		msg := types.NewMsgCreateProduct(req.ProductID, req.Description, price, req.Category, req.Tags, req.Content, signer)
 		err = msg.ValidateBasic()
 		if err != nil {
 			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
@@ -179,10 +181,13 @@ func createProductHandler(cliCtx context.CLIContext) http.HandlerFunc {
 }
 
 type updateProductReq struct {
-	BaseReq     rest.BaseReq `json:"base_req"`
-	ProductID   string       `json:"productID"`
-	Description string       `json:"description"`
-	Price       string       `json:"price"`
+	BaseReq     rest.BaseReq  `json:"base_req"`
+	ProductID   string        `json:"productID"`
+	Description string        `json:"description"`
+	Price       string        `json:"price"`
+	Category    string        `json:"category"`
+	Tags        []string      `json:"tags"`
+	Content     types.Content `json:"content"`
 }
 
 func updateProductHandler(cliCtx context.CLIContext) http.HandlerFunc {
@@ -212,7 +217,7 @@ func updateProductHandler(cliCtx context.CLIContext) http.HandlerFunc {
 		}
 
 		// create the message
-		msg := types.NewMsgUpdateProduct(req.ProductID, req.Description, price, signer)
+		msg := types.NewMsgUpdateProduct(req.ProductID, req.Description, price, req.Category, req.Tags, req.Content, signer)
 		err = msg.ValidateBasic()
 		if err != nil {
 			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
@@ -263,6 +268,10 @@ func deleteProductHandler(cliCtx context.CLIContext) http.HandlerFunc {
 type buyProductReq struct {
 	BaseReq   rest.BaseReq `json:"base_req"`
 	ProductID string       `json:"productID"`
+	Denom     string       `json:"denom"`
+	Coupon    string       `json:"coupon"`
+	// ExpectedVersion fails the purchase if the product was updated since, zero to skip the check
+	ExpectedVersion uint64 `json:"expected_version"`
 }
 
 func buyProductHandler(cliCtx context.CLIContext) http.HandlerFunc {
@@ -286,7 +295,7 @@ func buyProductHandler(cliCtx context.CLIContext) http.HandlerFunc {
 		}
 
 		// create the message
-		msg := types.NewMsgBuyProduct(req.ProductID, signer)
+		msg := types.NewMsgBuyProduct(req.ProductID, req.Denom, req.Coupon, req.ExpectedVersion, signer)
 		err = msg.ValidateBasic()
 		if err != nil {
 			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
@@ -297,17 +306,563 @@ func buyProductHandler(cliCtx context.CLIContext) http.HandlerFunc {
 	}
 }
 
-type signTxReq struct {
-	BaseReq       rest.BaseReq `json:"base_req"`
-	Tx            string       `json:"tx"`
-	Sequence      string       `json:"sequence"`
-	AccountNumber string       `json:"accountNumber"`
+type listProductReq struct {
+	BaseReq   rest.BaseReq `json:"base_req"`
+	ProductID string       `json:"productID"`
+}
+
+func listProductHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req listProductReq
+
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		baseReq := req.BaseReq.Sanitize()
+		if !baseReq.ValidateBasic(w) {
+			return
+		}
+
+		signer, err := sdk.AccAddressFromBech32(req.BaseReq.From)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// create the message
+		msg := types.NewMsgListProduct(req.ProductID, signer)
+		err = msg.ValidateBasic()
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, baseReq, []sdk.Msg{msg})
+	}
+}
+
+type delistProductReq struct {
+	BaseReq   rest.BaseReq `json:"base_req"`
+	ProductID string       `json:"productID"`
+}
+
+func delistProductHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req delistProductReq
+
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		baseReq := req.BaseReq.Sanitize()
+		if !baseReq.ValidateBasic(w) {
+			return
+		}
+
+		signer, err := sdk.AccAddressFromBech32(req.BaseReq.From)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// create the message
+		msg := types.NewMsgDelistProduct(req.ProductID, signer)
+		err = msg.ValidateBasic()
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, baseReq, []sdk.Msg{msg})
+	}
+}
+
+type createAuctionReq struct {
+	BaseReq      rest.BaseReq `json:"base_req"`
+	ProductID    string       `json:"productID"`
+	AuctionType  string       `json:"auction_type"`
+	StartPrice   string       `json:"start_price"`
+	ReservePrice string       `json:"reserve_price"`
+	Decrement    string       `json:"decrement"`
+	Duration     string       `json:"duration"`
+}
+
+func createAuctionHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req createAuctionReq
+
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		baseReq := req.BaseReq.Sanitize()
+		if !baseReq.ValidateBasic(w) {
+			return
+		}
+
+		signer, err := sdk.AccAddressFromBech32(req.BaseReq.From)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		startPrice, err := sdk.ParseCoins(req.StartPrice)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		reservePrice, err := sdk.ParseCoins(req.ReservePrice)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		decrement, err := sdk.ParseCoins(req.Decrement)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		duration, err := strconv.ParseInt(req.Duration, 10, 64)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// create the message
+		msg := types.NewMsgCreateAuction(req.ProductID, req.AuctionType, startPrice, reservePrice, decrement, duration, signer)
+		err = msg.ValidateBasic()
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, baseReq, []sdk.Msg{msg})
+	}
+}
+
+type placeBidReq struct {
+	BaseReq   rest.BaseReq `json:"base_req"`
+	ProductID string       `json:"productID"`
+	Amount    string       `json:"amount"`
+}
+
+func placeBidHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req placeBidReq
+
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		baseReq := req.BaseReq.Sanitize()
+		if !baseReq.ValidateBasic(w) {
+			return
+		}
+
+		bidder, err := sdk.AccAddressFromBech32(req.BaseReq.From)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		amount, err := sdk.ParseCoins(req.Amount)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// create the message
+		msg := types.NewMsgPlaceBid(req.ProductID, amount, bidder)
+		err = msg.ValidateBasic()
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, baseReq, []sdk.Msg{msg})
+	}
+}
+
+type publishProductReq struct {
+	BaseReq   rest.BaseReq `json:"base_req"`
+	Name      string       `json:"name"`
+	ProductID string       `json:"productID"`
+}
+
+func publishProductHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req publishProductReq
+
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		baseReq := req.BaseReq.Sanitize()
+		if !baseReq.ValidateBasic(w) {
+			return
+		}
+
+		signer, err := sdk.AccAddressFromBech32(req.BaseReq.From)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// create the message
+		msg := types.NewMsgPublishProduct(req.Name, req.ProductID, signer)
+		err = msg.ValidateBasic()
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, baseReq, []sdk.Msg{msg})
+	}
+}
+
+type unpublishProductReq struct {
+	BaseReq   rest.BaseReq `json:"base_req"`
+	ProductID string       `json:"productID"`
+}
+
+func unpublishProductHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req unpublishProductReq
+
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		baseReq := req.BaseReq.Sanitize()
+		if !baseReq.ValidateBasic(w) {
+			return
+		}
+
+		signer, err := sdk.AccAddressFromBech32(req.BaseReq.From)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// create the message
+		msg := types.NewMsgUnpublishProduct(req.ProductID, signer)
+		err = msg.ValidateBasic()
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, baseReq, []sdk.Msg{msg})
+	}
+}
+
+type setStorefrontSaleReq struct {
+	BaseReq  rest.BaseReq `json:"base_req"`
+	Name     string       `json:"name"`
+	Included bool         `json:"included"`
+	Owner    string       `json:"owner"`
+}
+
+func setStorefrontSaleHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req setStorefrontSaleReq
+
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		baseReq := req.BaseReq.Sanitize()
+		if !baseReq.ValidateBasic(w) {
+			return
+		}
+
+		addr, err := sdk.AccAddressFromBech32(req.Owner)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// create the message
+		msg := types.NewMsgSetStorefrontSale(req.Name, req.Included, addr)
+		err = msg.ValidateBasic()
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, baseReq, []sdk.Msg{msg})
+	}
+}
+
+type reviewProductReq struct {
+	BaseReq   rest.BaseReq `json:"base_req"`
+	ProductID string       `json:"productID"`
+	Rating    uint8        `json:"rating"`
+	Text      string       `json:"text"`
+}
+
+func reviewProductHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req reviewProductReq
+
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		baseReq := req.BaseReq.Sanitize()
+		if !baseReq.ValidateBasic(w) {
+			return
+		}
+
+		reviewer, err := sdk.AccAddressFromBech32(req.BaseReq.From)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// create the message
+		msg := types.NewMsgReviewProduct(req.ProductID, req.Rating, req.Text, reviewer)
+		err = msg.ValidateBasic()
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, baseReq, []sdk.Msg{msg})
+	}
+}
+
+type setProductPricingReq struct {
+	BaseReq        rest.BaseReq `json:"base_req"`
+	ProductID      string       `json:"productID"`
+	AcceptedPrices string       `json:"accepted_prices"`
+	ReferencePrice string       `json:"reference_price"`
+}
+
+func setProductPricingHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req setProductPricingReq
+
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		baseReq := req.BaseReq.Sanitize()
+		if !baseReq.ValidateBasic(w) {
+			return
+		}
+
+		signer, err := sdk.AccAddressFromBech32(req.BaseReq.From)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		acceptedPrices, err := sdk.ParseCoins(req.AcceptedPrices)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		referencePrice, err := sdk.ParseDecCoins(req.ReferencePrice)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// create the message
+		msg := types.NewMsgSetProductPricing(req.ProductID, acceptedPrices, referencePrice, signer)
+		err = msg.ValidateBasic()
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, baseReq, []sdk.Msg{msg})
+	}
+}
+
+type createCouponReq struct {
+	BaseReq      rest.BaseReq `json:"base_req"`
+	Code         string       `json:"code"`
+	ProductID    string       `json:"productID"`
+	Percent      uint64       `json:"percent"`
+	Amount       string       `json:"amount"`
+	ExpiryHeight int64        `json:"expiry_height"`
+	MaxUses      uint64       `json:"max_uses"`
+}
+
+func createCouponHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req createCouponReq
+
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		baseReq := req.BaseReq.Sanitize()
+		if !baseReq.ValidateBasic(w) {
+			return
+		}
+
+		issuer, err := sdk.AccAddressFromBech32(req.BaseReq.From)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		amount, err := sdk.ParseCoins(req.Amount)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// create the message
+		msg := types.NewMsgCreateCoupon(types.HashCouponCode(req.Code), req.ProductID, req.Percent, amount, req.ExpiryHeight, req.MaxUses, issuer)
+		err = msg.ValidateBasic()
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, baseReq, []sdk.Msg{msg})
+	}
+}
+
+type revokeCouponReq struct {
+	BaseReq rest.BaseReq `json:"base_req"`
+	Code    string       `json:"code"`
+}
+
+func revokeCouponHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req revokeCouponReq
+
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		baseReq := req.BaseReq.Sanitize()
+		if !baseReq.ValidateBasic(w) {
+			return
+		}
+
+		issuer, err := sdk.AccAddressFromBech32(req.BaseReq.From)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// create the message
+		msg := types.NewMsgRevokeCoupon(types.HashCouponCode(req.Code), issuer)
+		err = msg.ValidateBasic()
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, baseReq, []sdk.Msg{msg})
+	}
 }
 
-func signTxHandler(cliCtx context.CLIContext) http.HandlerFunc {
+type commitCouponReq struct {
+	BaseReq rest.BaseReq `json:"base_req"`
+	Code    string       `json:"code"`
+}
+
+func commitCouponHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req commitCouponReq
+
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		baseReq := req.BaseReq.Sanitize()
+		if !baseReq.ValidateBasic(w) {
+			return
+		}
+
+		buyer, err := sdk.AccAddressFromBech32(req.BaseReq.From)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// create the message
+		msg := types.NewMsgCommitCoupon(types.HashCouponCode(req.Code), types.HashCouponCommitment(req.Code, buyer), buyer)
+		err = msg.ValidateBasic()
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, baseReq, []sdk.Msg{msg})
+	}
+}
+
+type setProductSubscriptionReq struct {
+	BaseReq   rest.BaseReq `json:"base_req"`
+	ProductID string       `json:"productID"`
+	Period    int64        `json:"period"`
+}
+
+func setProductSubscriptionHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req setProductSubscriptionReq
+
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		baseReq := req.BaseReq.Sanitize()
+		if !baseReq.ValidateBasic(w) {
+			return
+		}
+
+		signer, err := sdk.AccAddressFromBech32(req.BaseReq.From)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// create the message
+		msg := types.NewMsgSetProductSubscription(req.ProductID, req.Period, signer)
+		err = msg.ValidateBasic()
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, baseReq, []sdk.Msg{msg})
+	}
+}
+
+type cancelSubscriptionReq struct {
+	BaseReq   rest.BaseReq `json:"base_req"`
+	ProductID string       `json:"productID"`
+}
+
+func cancelSubscriptionHandler(cliCtx context.CLIContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		var req cancelSubscriptionReq
 
-		var req signTxReq
 		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
 			rest.WriteErrorResponse(w, http.StatusBadRequest, "failed to parse request")
 			return
@@ -318,35 +873,96 @@ func signTxHandler(cliCtx context.CLIContext) http.HandlerFunc {
 			return
 		}
 
-		filePath := "unsignedTx.json"
-		data := []byte(req.Tx)
+		subscriber, err := sdk.AccAddressFromBech32(req.BaseReq.From)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// create the message
+		msg := types.NewMsgCancelSubscription(req.ProductID, subscriber)
+		err = msg.ValidateBasic()
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, baseReq, []sdk.Msg{msg})
+	}
+}
+
+type buyProductsReq struct {
+	BaseReq rest.BaseReq     `json:"base_req"`
+	Items   []types.CartItem `json:"items"`
+}
+
+func buyProductsHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req buyProductsReq
+
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		baseReq := req.BaseReq.Sanitize()
+		if !baseReq.ValidateBasic(w) {
+			return
+		}
 
-		err := ioutil.WriteFile(filePath, data, 0644)
+		signer, err := sdk.AccAddressFromBech32(req.BaseReq.From)
 		if err != nil {
-			panic(err)
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
 		}
 
-		cmd := exec.Command("nscli", "tx", "sign", filePath, "--from", req.BaseReq.From, "--offline", "--chain-id", req.BaseReq.ChainID, "--sequence", req.Sequence, "--account-number", req.AccountNumber)
-		stdout, err := cmd.Output()
+		// create the message
+		msg := types.NewMsgBuyProducts(req.Items, signer)
+		err = msg.ValidateBasic()
 		if err != nil {
 			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
 			return
 		}
 
-		filePath = "signedTx.json"
-		data = []byte(string(stdout))
-		err = ioutil.WriteFile(filePath, data, 0644)
+		utils.WriteGenerateStdTxResponse(w, cliCtx, baseReq, []sdk.Msg{msg})
+	}
+}
+
+type setProductLicensingReq struct {
+	BaseReq   rest.BaseReq `json:"base_req"`
+	ProductID string       `json:"productID"`
+	Licensing bool         `json:"licensing"`
+	Duration  int64        `json:"duration"`
+}
+
+func setProductLicensingHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req setProductLicensingReq
+
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		baseReq := req.BaseReq.Sanitize()
+		if !baseReq.ValidateBasic(w) {
+			return
+		}
+
+		signer, err := sdk.AccAddressFromBech32(req.BaseReq.From)
 		if err != nil {
-			panic(err)
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
 		}
 
-		cmd = exec.Command("nscli", "tx", "broadcast", filePath)
-		stdout, err = cmd.Output()
+		// create the message
+		msg := types.NewMsgSetProductLicensing(req.ProductID, req.Licensing, req.Duration, signer)
+		err = msg.ValidateBasic()
 		if err != nil {
 			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
 			return
 		}
 
-		rest.PostProcessResponse(w, cliCtx, string(stdout))
+		utils.WriteGenerateStdTxResponse(w, cliCtx, baseReq, []sdk.Msg{msg})
 	}
 }
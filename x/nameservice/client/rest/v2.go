@@ -0,0 +1,738 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/rest"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/auth/client/utils"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/keeper"
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
+
+// The v2 routes address names, products, auctions and coupons as resources under
+// /<storeName>/v2: the path identifies the resource, the method tells what is done with
+// it and the signer of the generated transactions is always base_req.from. Queries take
+// a height query parameter. Every error is written as a v2ErrorResponse carrying the
+// codespace and code the error was registered with, e.g. nameservice 2 for
+// types.ErrProductDoesNotExist.
+
+const (
+	v2Path      = "/v2"
+	heightParam = "height"
+)
+
+// registerV2Routes registers the v2 routes under a store name
+func registerV2Routes(cliCtx context.CLIContext, r *mux.Router, storeName string) {
+	prefix := fmt.Sprintf("/%s%s", storeName, v2Path)
+
+	r.HandleFunc(prefix+"/names", v2QueryHandler(cliCtx, storeName, keeper.QueryNames)).Methods("GET")
+	r.HandleFunc(prefix+"/names/{name}", v2WhoisHandler(cliCtx, storeName)).Methods("GET")
+	r.HandleFunc(prefix+"/names/{name}", v2TxHandler(cliCtx, v2SignerMsg(deleteNameMsg))).Methods("DELETE")
+	r.HandleFunc(prefix+"/names/{name}/value", v2ValueHandler(cliCtx, storeName)).Methods("GET")
+	r.HandleFunc(prefix+"/names/{name}/value", v2TxHandler(cliCtx, func() v2TxReq { return &v2SetValueReq{} })).Methods("PUT")
+	r.HandleFunc(prefix+"/names/{name}/purchase", v2TxHandler(cliCtx, func() v2TxReq { return &v2PurchaseNameReq{} })).Methods("POST")
+	r.HandleFunc(prefix+"/names/{name}/storefront-sale", v2TxHandler(cliCtx, func() v2TxReq { return &v2StorefrontSaleReq{} })).Methods("PUT")
+	r.HandleFunc(prefix+"/names/{name}/products", v2QueryHandler(cliCtx, storeName, keeper.QueryStorefront, restName)).Methods("GET")
+	r.HandleFunc(prefix+"/names/{name}/products/{productID}", v2QueryHandler(cliCtx, storeName, keeper.QueryResolveProduct, restName, "productID")).Methods("GET")
+
+	r.HandleFunc(prefix+"/products", v2ProductsHandler(cliCtx, storeName)).Methods("GET")
+	r.HandleFunc(prefix+"/products", v2TxHandler(cliCtx, func() v2TxReq { return &v2CreateProductReq{} })).Methods("POST")
+	r.HandleFunc(prefix+"/products/{productID}", v2ProductHandler(cliCtx, storeName)).Methods("GET")
+	r.HandleFunc(prefix+"/products/{productID}", v2TxHandler(cliCtx, func() v2TxReq { return &v2UpdateProductReq{} })).Methods("PUT")
+	r.HandleFunc(prefix+"/products/{productID}/purchase", v2TxHandler(cliCtx, func() v2TxReq { return &v2PurchaseProductReq{} })).Methods("POST")
+	r.HandleFunc(prefix+"/products/{productID}/listing", v2TxHandler(cliCtx, func() v2TxReq { return &v2ListingReq{} })).Methods("PUT")
+	r.HandleFunc(prefix+"/products/{productID}/storefront", v2TxHandler(cliCtx, func() v2TxReq { return &v2StorefrontReq{} })).Methods("PUT")
+	r.HandleFunc(prefix+"/products/{productID}/storefront", v2TxHandler(cliCtx, v2SignerMsg(unpublishProductMsg))).Methods("DELETE")
+	r.HandleFunc(prefix+"/products/{productID}/pricing", v2TxHandler(cliCtx, func() v2TxReq { return &v2PricingReq{} })).Methods("PUT")
+	r.HandleFunc(prefix+"/products/{productID}/subscription", v2TxHandler(cliCtx, func() v2TxReq { return &v2SubscriptionTermsReq{} })).Methods("PUT")
+	r.HandleFunc(prefix+"/products/{productID}/licensing", v2TxHandler(cliCtx, func() v2TxReq { return &v2LicensingReq{} })).Methods("PUT")
+	r.HandleFunc(prefix+"/products/{productID}/reviews", v2QueryHandler(cliCtx, storeName, keeper.QueryReviews, "productID")).Methods("GET")
+	r.HandleFunc(prefix+"/products/{productID}/reviews", v2TxHandler(cliCtx, func() v2TxReq { return &v2ReviewReq{} })).Methods("POST")
+	r.HandleFunc(prefix+"/products/{productID}/rating", v2QueryHandler(cliCtx, storeName, keeper.QueryRating, "productID")).Methods("GET")
+	r.HandleFunc(prefix+"/products/{productID}/history", v2QueryHandler(cliCtx, storeName, keeper.QueryProductHistory, "productID")).Methods("GET")
+	r.HandleFunc(prefix+"/products/{productID}/subscriptions", v2QueryHandler(cliCtx, storeName, keeper.QuerySubscriptions, "productID")).Methods("GET")
+	r.HandleFunc(prefix+"/products/{productID}/subscriptions/{address}", v2QueryHandler(cliCtx, storeName, keeper.QuerySubscription, "productID", "address")).Methods("GET")
+	r.HandleFunc(prefix+"/products/{productID}/subscriptions/{address}", v2TxHandler(cliCtx, v2SignerMsg(cancelSubscriptionMsg))).Methods("DELETE")
+	r.HandleFunc(prefix+"/products/{productID}/licenses/{address}", v2QueryHandler(cliCtx, storeName, keeper.QueryHasLicense, "productID", "address")).Methods("GET")
+	r.HandleFunc(prefix+"/products/{productID}/auction", v2QueryHandler(cliCtx, storeName, keeper.QueryAuction, "productID")).Methods("GET")
+	r.HandleFunc(prefix+"/products/{productID}/auction", v2TxHandler(cliCtx, func() v2TxReq { return &v2AuctionReq{} })).Methods("POST")
+	r.HandleFunc(prefix+"/products/{productID}/auction/bids", v2QueryHandler(cliCtx, storeName, keeper.QueryBids, "productID")).Methods("GET")
+	r.HandleFunc(prefix+"/products/{productID}/auction/bids", v2TxHandler(cliCtx, func() v2TxReq { return &v2BidReq{} })).Methods("POST")
+	r.HandleFunc(prefix+"/purchases", v2TxHandler(cliCtx, func() v2TxReq { return &v2PurchaseProductsReq{} })).Methods("POST")
+
+	r.HandleFunc(prefix+"/auctions", v2QueryHandler(cliCtx, storeName, keeper.QueryAuctions)).Methods("GET")
+	r.HandleFunc(prefix+"/sellers/{address}/reputation", v2QueryHandler(cliCtx, storeName, keeper.QueryReputation, "address")).Methods("GET")
+
+	r.HandleFunc(prefix+"/coupons", v2TxHandler(cliCtx, func() v2TxReq { return &v2CouponReq{} })).Methods("POST")
+	r.HandleFunc(prefix+"/coupons/{codeHash}", v2QueryHandler(cliCtx, storeName, keeper.QueryCoupon, "codeHash")).Methods("GET")
+	r.HandleFunc(prefix+"/coupons/{codeHash}", v2TxHandler(cliCtx, v2SignerMsg(revokeCouponMsg))).Methods("DELETE")
+	r.HandleFunc(prefix+"/coupons/commitments", v2TxHandler(cliCtx, func() v2TxReq { return &v2CommitCouponReq{} })).Methods("POST")
+}
+
+// v2Error tells why a request failed, with the codespace and code the error was registered with
+type v2Error struct {
+	Codespace string `json:"codespace"`
+	Code      uint32 `json:"code"`
+	Message   string `json:"message"`
+}
+
+// v2ErrorResponse is the body of every failed v2 request
+type v2ErrorResponse struct {
+	Error v2Error `json:"error"`
+}
+
+// writeV2Error writes an error in the envelope of the v2 routes
+func writeV2Error(w http.ResponseWriter, err error) {
+	codespace, code, _ := sdkerrors.ABCIInfo(err, false)
+	bz, _ := json.Marshal(v2ErrorResponse{Error: v2Error{Codespace: codespace, Code: code, Message: err.Error()}})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(v2StatusCode(err))
+	_, _ = w.Write(bz)
+}
+
+// v2StatusCode maps an error to an HTTP status code, requests the module or the SDK
+// rejected before reaching the chain are bad requests
+func v2StatusCode(err error) int {
+	switch {
+	case errors.Is(err, sdkerrors.ErrInvalidRequest), errors.Is(err, sdkerrors.ErrUnknownRequest),
+		errors.Is(err, sdkerrors.ErrJSONUnmarshal), errors.Is(err, sdkerrors.ErrInvalidAddress),
+		errors.Is(err, sdkerrors.ErrInvalidCoins), errors.Is(err, types.ErrInvalidContent):
+		return http.StatusBadRequest
+	case errors.Is(err, types.ErrProductNotPublished):
+		return http.StatusNotFound
+	}
+
+	if codespace, _, _ := sdkerrors.ABCIInfo(err, false); codespace == sdkerrors.UndefinedCodespace {
+		return http.StatusInternalServerError
+	}
+	return errorStatusCode(err)
+}
+
+// v2Height returns the context querying at the height of the request, the latest one by default
+func v2Height(cliCtx context.CLIContext, r *http.Request) (context.CLIContext, error) {
+	heightStr := r.URL.Query().Get(heightParam)
+	if heightStr == "" {
+		return cliCtx.WithHeight(0), nil
+	}
+
+	height, err := strconv.ParseInt(heightStr, 10, 64)
+	if err != nil || height < 0 {
+		return cliCtx, sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "invalid height %s", heightStr)
+	}
+	return cliCtx.WithHeight(height), nil
+}
+
+// queryV2 runs a custom query of the module and returns its result along with the
+// height it was answered at. The node is asked directly, the context would turn the
+// response of a failed query into an error without its code. The results of custom
+// queries cannot be proven, so no proof is asked for.
+func queryV2(cliCtx context.CLIContext, route string, data []byte) ([]byte, int64, error) {
+	node, err := cliCtx.GetNode()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := rpcclient.ABCIQueryOptions{Height: cliCtx.Height}
+	result, err := node.ABCIQueryWithOptions(route, data, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp := result.Response
+	if !resp.IsOK() {
+		return nil, 0, sdkerrors.ABCIError(resp.Codespace, resp.Code, resp.Log)
+	}
+	return resp.Value, resp.Height, nil
+}
+
+// v2Query runs a custom query of the module at the height of the request. The path of
+// the query is made of the query name and the values of the vars of the route.
+func v2Query(cliCtx context.CLIContext, r *http.Request, storeName, query string, data []byte, vars ...string) (context.CLIContext, []byte, error) {
+	cliCtx, err := v2Height(cliCtx, r)
+	if err != nil {
+		return cliCtx, nil, err
+	}
+
+	route := fmt.Sprintf("custom/%s/%s", storeName, query)
+	for _, v := range vars {
+		route = fmt.Sprintf("%s/%s", route, mux.Vars(r)[v])
+	}
+
+	res, height, err := queryV2(cliCtx, route, data)
+	if err != nil {
+		return cliCtx, nil, err
+	}
+	return cliCtx.WithHeight(height), res, nil
+}
+
+// v2QueryHandler answers a route with the result of a custom query of the module
+func v2QueryHandler(cliCtx context.CLIContext, storeName, query string, vars ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cliCtx, res, err := v2Query(cliCtx, r, storeName, query, nil, vars...)
+		if err != nil {
+			writeV2Error(w, err)
+			return
+		}
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+// v2WhoisHandler returns the record of a name, names nobody owns do not exist
+func v2WhoisHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cliCtx, whois, err := v2Whois(cliCtx, r, storeName)
+		if err != nil {
+			writeV2Error(w, err)
+			return
+		}
+		rest.PostProcessResponse(w, cliCtx, whois)
+	}
+}
+
+// v2ValueHandler resolves a name to its value
+func v2ValueHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cliCtx, whois, err := v2Whois(cliCtx, r, storeName)
+		if err != nil {
+			writeV2Error(w, err)
+			return
+		}
+		rest.PostProcessResponse(w, cliCtx, types.QueryResResolve{Value: whois.Value})
+	}
+}
+
+func v2Whois(cliCtx context.CLIContext, r *http.Request, storeName string) (context.CLIContext, types.Whois, error) {
+	cliCtx, res, err := v2Query(cliCtx, r, storeName, keeper.QueryWhois, nil, restName)
+	if err != nil {
+		return cliCtx, types.Whois{}, err
+	}
+
+	var whois types.Whois
+	if err := cliCtx.Codec.UnmarshalJSON(res, &whois); err != nil {
+		return cliCtx, types.Whois{}, sdkerrors.Wrap(sdkerrors.ErrJSONUnmarshal, err.Error())
+	}
+	if whois.Owner.Empty() {
+		return cliCtx, types.Whois{}, sdkerrors.Wrap(types.ErrNameDoesNotExist, mux.Vars(r)[restName])
+	}
+	return cliCtx, whois, nil
+}
+
+// v2ProductHandler returns a product
+func v2ProductHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cliCtx, res, err := v2Query(cliCtx, r, storeName, keeper.QueryProduct, nil, "productID")
+		if err != nil {
+			writeV2Error(w, err)
+			return
+		}
+
+		var product types.Product
+		if err := cliCtx.Codec.UnmarshalJSON(res, &product); err != nil {
+			writeV2Error(w, sdkerrors.Wrap(sdkerrors.ErrJSONUnmarshal, err.Error()))
+			return
+		}
+		if product.ProductID == "" {
+			writeV2Error(w, sdkerrors.Wrap(types.ErrProductDoesNotExist, mux.Vars(r)["productID"]))
+			return
+		}
+
+		rest.PostProcessResponse(w, cliCtx, product)
+	}
+}
+
+// v2ProductsHandler lists the products matching the filters of the query parameters
+func v2ProductsHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		var owner sdk.AccAddress
+		if ownerStr := query.Get("owner"); ownerStr != "" {
+			addr, err := sdk.AccAddressFromBech32(ownerStr)
+			if err != nil {
+				writeV2Error(w, sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, err.Error()))
+				return
+			}
+			owner = addr
+		}
+
+		minPrice, err := sdk.ParseCoins(query.Get("min_price"))
+		if err != nil {
+			writeV2Error(w, sdkerrors.Wrap(sdkerrors.ErrInvalidCoins, err.Error()))
+			return
+		}
+
+		maxPrice, err := sdk.ParseCoins(query.Get("max_price"))
+		if err != nil {
+			writeV2Error(w, sdkerrors.Wrap(sdkerrors.ErrInvalidCoins, err.Error()))
+			return
+		}
+
+		params := types.NewQueryProductsParams(query.Get("category"), query.Get("tag"), owner,
+			minPrice, maxPrice, query.Get("listed") == "true")
+
+		bz, err := cliCtx.Codec.MarshalJSON(params)
+		if err != nil {
+			writeV2Error(w, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error()))
+			return
+		}
+
+		cliCtx, res, err := v2Query(cliCtx, r, storeName, keeper.QueryProducts, bz)
+		if err != nil {
+			writeV2Error(w, err)
+			return
+		}
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+// v2TxReq is the body of a route generating a transaction
+type v2TxReq interface {
+	baseReq() rest.BaseReq
+	// msg creates the message signed by the signer, from the body and the vars of the route
+	msg(vars map[string]string, signer sdk.AccAddress) (sdk.Msg, error)
+}
+
+// v2TxHandler generates the unsigned transaction of the message of a request
+func v2TxHandler(cliCtx context.CLIContext, newReq func() v2TxReq) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := newReq()
+
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			writeV2Error(w, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, err.Error()))
+			return
+		}
+		if err := cliCtx.Codec.UnmarshalJSON(body, req); err != nil {
+			writeV2Error(w, sdkerrors.Wrap(sdkerrors.ErrJSONUnmarshal, err.Error()))
+			return
+		}
+
+		baseReq := req.baseReq().Sanitize()
+		signer, err := validateV2BaseReq(baseReq)
+		if err != nil {
+			writeV2Error(w, err)
+			return
+		}
+
+		msg, err := req.msg(mux.Vars(r), signer)
+		if err != nil {
+			writeV2Error(w, err)
+			return
+		}
+		if err := msg.ValidateBasic(); err != nil {
+			writeV2Error(w, err)
+			return
+		}
+
+		writeV2GeneratedTx(w, cliCtx, baseReq, []sdk.Msg{msg})
+	}
+}
+
+// validateV2BaseReq checks a base request like rest.BaseReq.ValidateBasic does and
+// returns its signer
+func validateV2BaseReq(br rest.BaseReq) (sdk.AccAddress, error) {
+	if !br.Simulate {
+		switch {
+		case len(br.ChainID) == 0:
+			return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "chain-id required but not specified")
+		case !br.Fees.IsZero() && !br.GasPrices.IsZero():
+			return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "cannot provide both fees and gas prices")
+		case !br.Fees.IsValid() && !br.GasPrices.IsValid():
+			return nil, sdkerrors.Wrap(sdkerrors.ErrInsufficientFee, "invalid fees or gas prices provided")
+		}
+	}
+
+	signer, err := sdk.AccAddressFromBech32(br.From)
+	if err != nil || signer.Empty() {
+		return nil, sdkerrors.Wrapf(sdkerrors.ErrInvalidAddress, "invalid from address: %s", br.From)
+	}
+	return signer, nil
+}
+
+// writeV2GeneratedTx writes the unsigned transaction of messages, or its simulated gas,
+// like utils.WriteGenerateStdTxResponse does
+func writeV2GeneratedTx(w http.ResponseWriter, cliCtx context.CLIContext, br rest.BaseReq, msgs []sdk.Msg) {
+	gasAdj := flags.DefaultGasAdjustment
+	if br.GasAdjustment != "" {
+		adj, err := strconv.ParseFloat(br.GasAdjustment, 64)
+		if err != nil || adj < 0 {
+			writeV2Error(w, sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "invalid gas adjustment %s", br.GasAdjustment))
+			return
+		}
+		gasAdj = adj
+	}
+
+	simAndExec, gas, err := flags.ParseGas(br.Gas)
+	if err != nil {
+		writeV2Error(w, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, err.Error()))
+		return
+	}
+
+	txBldr := auth.NewTxBuilder(
+		utils.GetTxEncoder(cliCtx.Codec), br.AccountNumber, br.Sequence, gas, gasAdj,
+		br.Simulate, br.ChainID, br.Memo, br.Fees, br.GasPrices,
+	)
+
+	if br.Simulate || simAndExec {
+		txBldr, err = utils.EnrichWithGas(txBldr, cliCtx, msgs)
+		if err != nil {
+			writeV2Error(w, err)
+			return
+		}
+
+		if br.Simulate {
+			rest.WriteSimulationResponse(w, cliCtx.Codec, txBldr.Gas())
+			return
+		}
+	}
+
+	stdMsg, err := txBldr.BuildSignMsg(msgs)
+	if err != nil {
+		writeV2Error(w, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, err.Error()))
+		return
+	}
+
+	output, err := cliCtx.Codec.MarshalJSON(auth.NewStdTx(stdMsg.Msgs, stdMsg.Fee, nil, stdMsg.Memo))
+	if err != nil {
+		writeV2Error(w, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(output)
+}
+
+// v2SignerReq is the body of the routes whose message is made of the vars of the route
+// and of the signer only
+type v2SignerReq struct {
+	BaseReq rest.BaseReq `json:"base_req"`
+
+	newMsg func(vars map[string]string, signer sdk.AccAddress) (sdk.Msg, error)
+}
+
+func (req v2SignerReq) baseReq() rest.BaseReq { return req.BaseReq }
+
+func (req v2SignerReq) msg(vars map[string]string, signer sdk.AccAddress) (sdk.Msg, error) {
+	return req.newMsg(vars, signer)
+}
+
+func v2SignerMsg(newMsg func(vars map[string]string, signer sdk.AccAddress) (sdk.Msg, error)) func() v2TxReq {
+	return func() v2TxReq { return &v2SignerReq{newMsg: newMsg} }
+}
+
+func deleteNameMsg(vars map[string]string, signer sdk.AccAddress) (sdk.Msg, error) {
+	return types.NewMsgDeleteName(vars[restName], signer), nil
+}
+
+func unpublishProductMsg(vars map[string]string, signer sdk.AccAddress) (sdk.Msg, error) {
+	return types.NewMsgUnpublishProduct(vars["productID"], signer), nil
+}
+
+func revokeCouponMsg(vars map[string]string, signer sdk.AccAddress) (sdk.Msg, error) {
+	return types.NewMsgRevokeCoupon(vars["codeHash"], signer), nil
+}
+
+// cancelSubscriptionMsg cancels the subscription of the signer, which the path addresses
+func cancelSubscriptionMsg(vars map[string]string, signer sdk.AccAddress) (sdk.Msg, error) {
+	if vars["address"] != signer.String() {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "subscriptions are cancelled by their subscriber")
+	}
+	return types.NewMsgCancelSubscription(vars["productID"], signer), nil
+}
+
+func parseV2Coins(coins string) (sdk.Coins, error) {
+	parsed, err := sdk.ParseCoins(coins)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidCoins, err.Error())
+	}
+	return parsed, nil
+}
+
+// v2SetValueReq sets the value of a name
+type v2SetValueReq struct {
+	BaseReq rest.BaseReq `json:"base_req"`
+	Value   string       `json:"value"`
+}
+
+func (req v2SetValueReq) baseReq() rest.BaseReq { return req.BaseReq }
+
+func (req v2SetValueReq) msg(vars map[string]string, signer sdk.AccAddress) (sdk.Msg, error) {
+	return types.NewMsgSetName(vars[restName], req.Value, signer), nil
+}
+
+// v2PurchaseNameReq bids an amount for a name
+type v2PurchaseNameReq struct {
+	BaseReq rest.BaseReq `json:"base_req"`
+	Amount  string       `json:"amount"`
+}
+
+func (req v2PurchaseNameReq) baseReq() rest.BaseReq { return req.BaseReq }
+
+func (req v2PurchaseNameReq) msg(vars map[string]string, signer sdk.AccAddress) (sdk.Msg, error) {
+	amount, err := parseV2Coins(req.Amount)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewMsgBuyName(vars[restName], amount, signer), nil
+}
+
+// v2StorefrontSaleReq sets whether the sales of a name include its storefront
+type v2StorefrontSaleReq struct {
+	BaseReq  rest.BaseReq `json:"base_req"`
+	Included bool         `json:"included"`
+}
+
+func (req v2StorefrontSaleReq) baseReq() rest.BaseReq { return req.BaseReq }
+
+func (req v2StorefrontSaleReq) msg(vars map[string]string, signer sdk.AccAddress) (sdk.Msg, error) {
+	return types.NewMsgSetStorefrontSale(vars[restName], req.Included, signer), nil
+}
+
+// v2CreateProductReq creates a product
+type v2CreateProductReq struct {
+	BaseReq     rest.BaseReq  `json:"base_req"`
+	ProductID   string        `json:"productID"`
+	Description string        `json:"description"`
+	Price       string        `json:"price"`
+	Category    string        `json:"category"`
+	Tags        []string      `json:"tags"`
+	Content     types.Content `json:"content"`
+}
+
+func (req v2CreateProductReq) baseReq() rest.BaseReq { return req.BaseReq }
+
+func (req v2CreateProductReq) msg(vars map[string]string, signer sdk.AccAddress) (sdk.Msg, error) {
+	price, err := parseV2Coins(req.Price)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewMsgCreateProduct(req.ProductID, req.Description, price, req.Category, req.Tags, req.Content, signer), nil
+}
+
+// v2UpdateProductReq updates the terms of a product
+type v2UpdateProductReq struct {
+	BaseReq     rest.BaseReq  `json:"base_req"`
+	Description string        `json:"description"`
+	Price       string        `json:"price"`
+	Category    string        `json:"category"`
+	Tags        []string      `json:"tags"`
+	Content     types.Content `json:"content"`
+}
+
+func (req v2UpdateProductReq) baseReq() rest.BaseReq { return req.BaseReq }
+
+func (req v2UpdateProductReq) msg(vars map[string]string, signer sdk.AccAddress) (sdk.Msg, error) {
+	price, err := parseV2Coins(req.Price)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewMsgUpdateProduct(vars["productID"], req.Description, price, req.Category, req.Tags, req.Content, signer), nil
+}
+
+// v2PurchaseProductReq buys a product
+type v2PurchaseProductReq struct {
+	BaseReq rest.BaseReq `json:"base_req"`
+	Denom   string       `json:"denom"`
+	Coupon  string       `json:"coupon"`
+	// ExpectedVersion fails the purchase if the product was updated since, zero to skip the check
+	ExpectedVersion uint64 `json:"expected_version"`
+}
+
+func (req v2PurchaseProductReq) baseReq() rest.BaseReq { return req.BaseReq }
+
+func (req v2PurchaseProductReq) msg(vars map[string]string, signer sdk.AccAddress) (sdk.Msg, error) {
+	return types.NewMsgBuyProduct(vars["productID"], req.Denom, req.Coupon, req.ExpectedVersion, signer), nil
+}
+
+// v2PurchaseProductsReq buys several products at once
+type v2PurchaseProductsReq struct {
+	BaseReq rest.BaseReq     `json:"base_req"`
+	Items   []types.CartItem `json:"items"`
+}
+
+func (req v2PurchaseProductsReq) baseReq() rest.BaseReq { return req.BaseReq }
+
+func (req v2PurchaseProductsReq) msg(vars map[string]string, signer sdk.AccAddress) (sdk.Msg, error) {
+	return types.NewMsgBuyProducts(req.Items, signer), nil
+}
+
+// v2ListingReq lists a product for sale or delists it
+type v2ListingReq struct {
+	BaseReq rest.BaseReq `json:"base_req"`
+	Listed  bool         `json:"listed"`
+}
+
+func (req v2ListingReq) baseReq() rest.BaseReq { return req.BaseReq }
+
+func (req v2ListingReq) msg(vars map[string]string, signer sdk.AccAddress) (sdk.Msg, error) {
+	if req.Listed {
+		return types.NewMsgListProduct(vars["productID"], signer), nil
+	}
+	return types.NewMsgDelistProduct(vars["productID"], signer), nil
+}
+
+// v2StorefrontReq publishes a product under a name
+type v2StorefrontReq struct {
+	BaseReq rest.BaseReq `json:"base_req"`
+	Name    string       `json:"name"`
+}
+
+func (req v2StorefrontReq) baseReq() rest.BaseReq { return req.BaseReq }
+
+func (req v2StorefrontReq) msg(vars map[string]string, signer sdk.AccAddress) (sdk.Msg, error) {
+	return types.NewMsgPublishProduct(req.Name, vars["productID"], signer), nil
+}
+
+// v2PricingReq sets the accepted and reference prices of a product
+type v2PricingReq struct {
+	BaseReq        rest.BaseReq `json:"base_req"`
+	AcceptedPrices string       `json:"accepted_prices"`
+	ReferencePrice string       `json:"reference_price"`
+}
+
+func (req v2PricingReq) baseReq() rest.BaseReq { return req.BaseReq }
+
+func (req v2PricingReq) msg(vars map[string]string, signer sdk.AccAddress) (sdk.Msg, error) {
+	acceptedPrices, err := parseV2Coins(req.AcceptedPrices)
+	if err != nil {
+		return nil, err
+	}
+
+	referencePrice, err := sdk.ParseDecCoins(req.ReferencePrice)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidCoins, err.Error())
+	}
+
+	return types.NewMsgSetProductPricing(vars["productID"], acceptedPrices, referencePrice, signer), nil
+}
+
+// v2SubscriptionTermsReq sells a product as a subscription, a zero period sells it at once
+type v2SubscriptionTermsReq struct {
+	BaseReq rest.BaseReq `json:"base_req"`
+	Period  int64        `json:"period"`
+}
+
+func (req v2SubscriptionTermsReq) baseReq() rest.BaseReq { return req.BaseReq }
+
+func (req v2SubscriptionTermsReq) msg(vars map[string]string, signer sdk.AccAddress) (sdk.Msg, error) {
+	return types.NewMsgSetProductSubscription(vars["productID"], req.Period, signer), nil
+}
+
+// v2LicensingReq sells licenses of a product
+type v2LicensingReq struct {
+	BaseReq   rest.BaseReq `json:"base_req"`
+	Licensing bool         `json:"licensing"`
+	Duration  int64        `json:"duration"`
+}
+
+func (req v2LicensingReq) baseReq() rest.BaseReq { return req.BaseReq }
+
+func (req v2LicensingReq) msg(vars map[string]string, signer sdk.AccAddress) (sdk.Msg, error) {
+	return types.NewMsgSetProductLicensing(vars["productID"], req.Licensing, req.Duration, signer), nil
+}
+
+// v2ReviewReq reviews a product
+type v2ReviewReq struct {
+	BaseReq rest.BaseReq `json:"base_req"`
+	Rating  uint8        `json:"rating"`
+	Text    string       `json:"text"`
+}
+
+func (req v2ReviewReq) baseReq() rest.BaseReq { return req.BaseReq }
+
+func (req v2ReviewReq) msg(vars map[string]string, signer sdk.AccAddress) (sdk.Msg, error) {
+	return types.NewMsgReviewProduct(vars["productID"], req.Rating, req.Text, signer), nil
+}
+
+// v2AuctionReq auctions a product for a duration in blocks
+type v2AuctionReq struct {
+	BaseReq      rest.BaseReq `json:"base_req"`
+	AuctionType  string       `json:"auction_type"`
+	StartPrice   string       `json:"start_price"`
+	ReservePrice string       `json:"reserve_price"`
+	Decrement    string       `json:"decrement"`
+	Duration     int64        `json:"duration"`
+}
+
+func (req v2AuctionReq) baseReq() rest.BaseReq { return req.BaseReq }
+
+func (req v2AuctionReq) msg(vars map[string]string, signer sdk.AccAddress) (sdk.Msg, error) {
+	startPrice, err := parseV2Coins(req.StartPrice)
+	if err != nil {
+		return nil, err
+	}
+	reservePrice, err := parseV2Coins(req.ReservePrice)
+	if err != nil {
+		return nil, err
+	}
+	decrement, err := parseV2Coins(req.Decrement)
+	if err != nil {
+		return nil, err
+	}
+
+	return types.NewMsgCreateAuction(vars["productID"], req.AuctionType, startPrice, reservePrice, decrement, req.Duration, signer), nil
+}
+
+// v2BidReq bids on the auction of a product
+type v2BidReq struct {
+	BaseReq rest.BaseReq `json:"base_req"`
+	Amount  string       `json:"amount"`
+}
+
+func (req v2BidReq) baseReq() rest.BaseReq { return req.BaseReq }
+
+func (req v2BidReq) msg(vars map[string]string, signer sdk.AccAddress) (sdk.Msg, error) {
+	amount, err := parseV2Coins(req.Amount)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewMsgPlaceBid(vars["productID"], amount, signer), nil
+}
+
+// v2CouponReq creates a coupon, the code is hashed before it is sent to the chain
+type v2CouponReq struct {
+	BaseReq      rest.BaseReq `json:"base_req"`
+	Code         string       `json:"code"`
+	ProductID    string       `json:"productID"`
+	Percent      uint64       `json:"percent"`
+	Amount       string       `json:"amount"`
+	ExpiryHeight int64        `json:"expiry_height"`
+	MaxUses      uint64       `json:"max_uses"`
+}
+
+func (req v2CouponReq) baseReq() rest.BaseReq { return req.BaseReq }
+
+func (req v2CouponReq) msg(vars map[string]string, signer sdk.AccAddress) (sdk.Msg, error) {
+	amount, err := parseV2Coins(req.Amount)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewMsgCreateCoupon(types.HashCouponCode(req.Code), req.ProductID, req.Percent, amount, req.ExpiryHeight, req.MaxUses, signer), nil
+}
+
+// v2CommitCouponReq commits the signer to a coupon code, only hashes are sent to the chain
+type v2CommitCouponReq struct {
+	BaseReq rest.BaseReq `json:"base_req"`
+	Code    string       `json:"code"`
+}
+
+func (req v2CommitCouponReq) baseReq() rest.BaseReq { return req.BaseReq }
+
+func (req v2CommitCouponReq) msg(vars map[string]string, signer sdk.AccAddress) (sdk.Msg, error) {
+	return types.NewMsgCommitCoupon(types.HashCouponCode(req.Code), types.HashCouponCommitment(req.Code, signer), signer), nil
+}
@@ -0,0 +1,160 @@
+package rest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/client"
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/client/clienttest"
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/client/rest"
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
+
+// setupV2 starts an app in which the account of a client owns the name alice, and
+// returns the client and a server of the routes of the module
+func setupV2(t *testing.T) (client.Client, *httptest.Server) {
+	_, clients := clienttest.Setup(t, "owner")
+	owner := clients[0]
+
+	_, err := owner.BuyName("alice", sdk.NewCoins(sdk.NewInt64Coin("nametoken", 10)))
+	require.NoError(t, err)
+	_, err = owner.SetName("alice", "8.8.8.8")
+	require.NoError(t, err)
+
+	r := mux.NewRouter()
+	rest.RegisterRoutes(owner.CLIContext().WithTrustNode(false), r, "nameservice")
+	return owner, httptest.NewServer(r)
+}
+
+type v2Error struct {
+	Error struct {
+		Codespace string `json:"codespace"`
+		Code      uint32 `json:"code"`
+		Message   string `json:"message"`
+	} `json:"error"`
+}
+
+func request(t *testing.T, method, url string, body []byte) (int, []byte) {
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	require.NoError(t, err)
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	bz, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+	return res.StatusCode, bz
+}
+
+func TestV2Queries(t *testing.T) {
+	owner, server := setupV2(t)
+	defer server.Close()
+	cdc := owner.CLIContext().Codec
+
+	status, bz := request(t, "GET", server.URL+"/nameservice/v2/names/alice", nil)
+	require.Equal(t, http.StatusOK, status, string(bz))
+	var res struct {
+		Height int64           `json:"height,string"`
+		Result json.RawMessage `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(bz, &res))
+	require.Equal(t, int64(3), res.Height)
+	var whois types.Whois
+	require.NoError(t, cdc.UnmarshalJSON(res.Result, &whois))
+	require.Equal(t, owner.CLIContext().GetFromAddress(), whois.Owner)
+	require.Equal(t, "8.8.8.8", whois.Value)
+
+	// the name was bought at height 2 and its value set at height 3
+	status, bz = request(t, "GET", server.URL+"/nameservice/v2/names/alice/value?height=2", nil)
+	require.Equal(t, http.StatusOK, status, string(bz))
+	require.NoError(t, json.Unmarshal(bz, &res))
+	require.Equal(t, int64(2), res.Height)
+	var value types.QueryResResolve
+	require.NoError(t, cdc.UnmarshalJSON(res.Result, &value))
+	require.Empty(t, value.Value)
+
+	// errors carry the codes of the module and of the SDK
+	for _, tc := range []struct {
+		path      string
+		status    int
+		codespace string
+		code      uint32
+	}{
+		{"/nameservice/v2/names/bob", http.StatusNotFound, types.ModuleName, 1},
+		{"/nameservice/v2/names/alice?height=1", http.StatusNotFound, types.ModuleName, 1},
+		{"/nameservice/v2/products/book1", http.StatusNotFound, types.ModuleName, 2},
+		{"/nameservice/v2/products/book1/auction", http.StatusNotFound, types.ModuleName, 5},
+		{"/nameservice/v2/names/alice?height=-1", http.StatusBadRequest, "sdk", 18},
+		{"/nameservice/v2/products?owner=alice", http.StatusBadRequest, "sdk", 7},
+	} {
+		status, bz := request(t, "GET", server.URL+tc.path, nil)
+		require.Equal(t, tc.status, status, tc.path)
+
+		var e v2Error
+		require.NoError(t, json.Unmarshal(bz, &e), tc.path)
+		require.Equal(t, tc.codespace, e.Error.Codespace, tc.path)
+		require.Equal(t, tc.code, e.Error.Code, tc.path)
+		require.NotEmpty(t, e.Error.Message, tc.path)
+	}
+
+	// the v1 routes keep working
+	status, bz = request(t, "GET", server.URL+"/nameservice/names/alice/whois", nil)
+	require.Equal(t, http.StatusOK, status, string(bz))
+}
+
+func TestV2Transactions(t *testing.T) {
+	owner, server := setupV2(t)
+	defer server.Close()
+	cdc := owner.CLIContext().Codec
+	from := owner.CLIContext().GetFromAddress()
+
+	body := func(fields map[string]interface{}) []byte {
+		fields["base_req"] = map[string]string{"from": from.String(), "chain_id": clienttest.ChainID}
+		bz, err := json.Marshal(fields)
+		require.NoError(t, err)
+		return bz
+	}
+
+	status, bz := request(t, "PUT", server.URL+"/nameservice/v2/names/alice/value", body(map[string]interface{}{"value": "1.1.1.1"}))
+	require.Equal(t, http.StatusOK, status, string(bz))
+	var tx auth.StdTx
+	require.NoError(t, cdc.UnmarshalJSON(bz, &tx))
+	require.Equal(t, []sdk.Msg{types.NewMsgSetName("alice", "1.1.1.1", from)}, tx.Msgs)
+
+	status, bz = request(t, "PUT", server.URL+"/nameservice/v2/products/book1/listing", body(map[string]interface{}{"listed": false}))
+	require.Equal(t, http.StatusOK, status, string(bz))
+	require.NoError(t, cdc.UnmarshalJSON(bz, &tx))
+	require.Equal(t, []sdk.Msg{types.NewMsgDelistProduct("book1", from)}, tx.Msgs)
+
+	status, bz = request(t, "DELETE", server.URL+"/nameservice/v2/names/alice", body(map[string]interface{}{}))
+	require.Equal(t, http.StatusOK, status, string(bz))
+	require.NoError(t, cdc.UnmarshalJSON(bz, &tx))
+	require.Equal(t, []sdk.Msg{types.NewMsgDeleteName("alice", from)}, tx.Msgs)
+
+	// invalid requests are rejected with the code of the error
+	status, bz = request(t, "POST", server.URL+"/nameservice/v2/names/alice/purchase", body(map[string]interface{}{"amount": "ten"}))
+	require.Equal(t, http.StatusBadRequest, status)
+	var e v2Error
+	require.NoError(t, json.Unmarshal(bz, &e))
+	require.Equal(t, "sdk", e.Error.Codespace)
+	require.Equal(t, uint32(10), e.Error.Code)
+
+	other := sdk.AccAddress([]byte("other_subscriber____")).String()
+	status, bz = request(t, "DELETE", server.URL+"/nameservice/v2/products/book1/subscriptions/"+other, body(map[string]interface{}{}))
+	require.Equal(t, http.StatusForbidden, status)
+	require.NoError(t, json.Unmarshal(bz, &e))
+	require.Equal(t, uint32(4), e.Error.Code)
+
+	status, _ = request(t, "POST", server.URL+"/nameservice/v2/products", []byte("{"))
+	require.Equal(t, http.StatusBadRequest, status)
+}
@@ -0,0 +1,135 @@
+package client
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
+
+// SetName sets the value a name resolves to
+func (c Client) SetName(name, value string) (sdk.TxResponse, error) {
+	return c.Broadcast(types.NewMsgSetName(name, value, c.cliCtx.GetFromAddress()))
+}
+
+// BuyName buys a name
+func (c Client) BuyName(name string, bid sdk.Coins) (sdk.TxResponse, error) {
+	return c.Broadcast(types.NewMsgBuyName(name, bid, c.cliCtx.GetFromAddress()))
+}
+
+// DeleteName deletes a name
+func (c Client) DeleteName(name string) (sdk.TxResponse, error) {
+	return c.Broadcast(types.NewMsgDeleteName(name, c.cliCtx.GetFromAddress()))
+}
+
+// CreateProduct creates a product
+func (c Client) CreateProduct(productID, description string, price sdk.Coins, category string, tags []string,
+	content types.Content) (sdk.TxResponse, error) {
+	return c.Broadcast(types.NewMsgCreateProduct(productID, description, price, category, tags, content,
+		c.cliCtx.GetFromAddress()))
+}
+
+// UpdateProduct updates the description, price, category, tags and content of a product
+func (c Client) UpdateProduct(productID, description string, price sdk.Coins, category string, tags []string,
+	content types.Content) (sdk.TxResponse, error) {
+	return c.Broadcast(types.NewMsgUpdateProduct(productID, description, price, category, tags, content,
+		c.cliCtx.GetFromAddress()))
+}
+
+// DeleteProduct deletes a product
+func (c Client) DeleteProduct(productID string) (sdk.TxResponse, error) {
+	return c.Broadcast(types.NewMsgDeleteProduct(productID, c.cliCtx.GetFromAddress()))
+}
+
+// BuyProduct buys a product, see types.MsgBuyProduct for the optional arguments
+func (c Client) BuyProduct(productID, denom, coupon string, expectedVersion uint64) (sdk.TxResponse, error) {
+	return c.Broadcast(types.NewMsgBuyProduct(productID, denom, coupon, expectedVersion, c.cliCtx.GetFromAddress()))
+}
+
+// BuyProducts buys several products in one transaction
+func (c Client) BuyProducts(items []types.CartItem) (sdk.TxResponse, error) {
+	return c.Broadcast(types.NewMsgBuyProducts(items, c.cliCtx.GetFromAddress()))
+}
+
+// ListProduct puts a product up for sale
+func (c Client) ListProduct(productID string) (sdk.TxResponse, error) {
+	return c.Broadcast(types.NewMsgListProduct(productID, c.cliCtx.GetFromAddress()))
+}
+
+// DelistProduct takes a product off sale
+func (c Client) DelistProduct(productID string) (sdk.TxResponse, error) {
+	return c.Broadcast(types.NewMsgDelistProduct(productID, c.cliCtx.GetFromAddress()))
+}
+
+// CreateAuction auctions a product
+func (c Client) CreateAuction(productID, auctionType string, startPrice, reservePrice, decrement sdk.Coins,
+	duration int64) (sdk.TxResponse, error) {
+	return c.Broadcast(types.NewMsgCreateAuction(productID, auctionType, startPrice, reservePrice, decrement, duration,
+		c.cliCtx.GetFromAddress()))
+}
+
+// PlaceBid bids on the auction of a product
+func (c Client) PlaceBid(productID string, amount sdk.Coins) (sdk.TxResponse, error) {
+	return c.Broadcast(types.NewMsgPlaceBid(productID, amount, c.cliCtx.GetFromAddress()))
+}
+
+// PublishProduct publishes a product in the storefront of a name
+func (c Client) PublishProduct(name, productID string) (sdk.TxResponse, error) {
+	return c.Broadcast(types.NewMsgPublishProduct(name, productID, c.cliCtx.GetFromAddress()))
+}
+
+// UnpublishProduct removes a product from its storefront
+func (c Client) UnpublishProduct(productID string) (sdk.TxResponse, error) {
+	return c.Broadcast(types.NewMsgUnpublishProduct(productID, c.cliCtx.GetFromAddress()))
+}
+
+// SetStorefrontSale includes or excludes the storefront of a name from its sale
+func (c Client) SetStorefrontSale(name string, included bool) (sdk.TxResponse, error) {
+	return c.Broadcast(types.NewMsgSetStorefrontSale(name, included, c.cliCtx.GetFromAddress()))
+}
+
+// ReviewProduct reviews a purchased product
+func (c Client) ReviewProduct(productID string, rating uint8, text string) (sdk.TxResponse, error) {
+	return c.Broadcast(types.NewMsgReviewProduct(productID, rating, text, c.cliCtx.GetFromAddress()))
+}
+
+// SetProductPricing sets the accepted prices and the reference price of a product
+func (c Client) SetProductPricing(productID string, acceptedPrices sdk.Coins,
+	referencePrice sdk.DecCoins) (sdk.TxResponse, error) {
+	return c.Broadcast(types.NewMsgSetProductPricing(productID, acceptedPrices, referencePrice,
+		c.cliCtx.GetFromAddress()))
+}
+
+// CreateCoupon creates a coupon, only the hash of its code is sent
+func (c Client) CreateCoupon(code, productID string, percent uint64, amount sdk.Coins, expiryHeight int64,
+	maxUses uint64) (sdk.TxResponse, error) {
+	return c.Broadcast(types.NewMsgCreateCoupon(types.HashCouponCode(code), productID, percent, amount,
+		expiryHeight, maxUses, c.cliCtx.GetFromAddress()))
+}
+
+// RevokeCoupon revokes a coupon
+func (c Client) RevokeCoupon(code string) (sdk.TxResponse, error) {
+	return c.Broadcast(types.NewMsgRevokeCoupon(types.HashCouponCode(code), c.cliCtx.GetFromAddress()))
+}
+
+// CommitCoupon commits to a coupon code, which can be redeemed from the next block on.
+// Neither the code nor anything that redeems it without the key of the buyer is sent.
+func (c Client) CommitCoupon(code string) (sdk.TxResponse, error) {
+	buyer := c.cliCtx.GetFromAddress()
+	return c.Broadcast(types.NewMsgCommitCoupon(types.HashCouponCode(code), types.HashCouponCommitment(code, buyer), buyer))
+}
+
+// SetProductSubscription sells a product as a subscription renewed every period blocks,
+// a zero period sells it outright again
+func (c Client) SetProductSubscription(productID string, period int64) (sdk.TxResponse, error) {
+	return c.Broadcast(types.NewMsgSetProductSubscription(productID, period, c.cliCtx.GetFromAddress()))
+}
+
+// CancelSubscription cancels a subscription to a product
+func (c Client) CancelSubscription(productID string) (sdk.TxResponse, error) {
+	return c.Broadcast(types.NewMsgCancelSubscription(productID, c.cliCtx.GetFromAddress()))
+}
+
+// SetProductLicensing sells licenses of a product instead of the product itself
+func (c Client) SetProductLicensing(productID string, licensing bool, duration int64) (sdk.TxResponse, error) {
+	return c.Broadcast(types.NewMsgSetProductLicensing(productID, licensing, duration, c.cliCtx.GetFromAddress()))
+}
@@ -1,55 +1,142 @@
 package nameservice
 
 import (
-	"fmt"
+	"strings"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
-)
-
-type GenesisState struct {
-	WhoisRecords []Whois `json:"whois_records"`
-}
-
-func NewGenesisState(whoIsRecords []Whois) GenesisState {
-	return GenesisState{WhoisRecords: nil}
-}
-
-func ValidateGenesis(data GenesisState) error {
-	for _, record := range data.WhoisRecords {
-		if record.Owner == nil {
-			return fmt.Errorf("invalid WhoisRecord: Value: %s. Error: Missing Owner", record.Value)
-		}
-		if record.Value == "" {
-			return fmt.Errorf("invalid WhoisRecord: Owner: %s. Error: Missing Value", record.Owner)
-		}
-		if record.Price == nil {
-			return fmt.Errorf("invalid WhoisRecord: Value: %s. Error: Missing Price", record.Value)
-		}
-	}
-	return nil
-}
 
-func DefaultGenesisState() GenesisState {
-	return GenesisState{
-		WhoisRecords: []Whois{},
-	}
-}
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
 
 func InitGenesis(ctx sdk.Context, keeper Keeper, data GenesisState) {
+	// a chain started from genesis is written in the current layout from its first block
+	keeper.SetLayoutVersion(ctx, types.LayoutVersion)
 	for _, record := range data.WhoisRecords {
-		keeper.SetWhois(ctx, record.Value, record)
+		keeper.SetWhois(ctx, record.Name, record.Whois)
+	}
+	// SetProduct rebuilds the category, tag and storefront indexes
+	for _, product := range data.Products {
+		keeper.SetProduct(ctx, types.ProductPrefix+product.ProductID, product)
+	}
+	for _, entry := range data.ProductHistory {
+		keeper.SetProductHistoryEntry(ctx, entry)
+	}
+	for _, auction := range data.Auctions {
+		keeper.SetAuction(ctx, auction)
+	}
+	for _, purchase := range data.Purchases {
+		keeper.SetPurchase(ctx, purchase)
+	}
+	// SetReview rebuilds the product and seller ratings
+	for _, review := range data.Reviews {
+		keeper.SetReview(ctx, review)
+	}
+	for _, coupon := range data.Coupons {
+		keeper.SetCoupon(ctx, coupon)
+	}
+	for _, commitment := range data.CouponCommitments {
+		keeper.SetCouponCommitment(ctx, commitment)
+	}
+	for _, subscription := range data.Subscriptions {
+		keeper.SetSubscription(ctx, subscription)
+	}
+	for _, license := range data.Licenses {
+		keeper.SetLicense(ctx, license)
 	}
 }
 
 func ExportGenesis(ctx sdk.Context, k Keeper) GenesisState {
-	var records []Whois
+	var records []WhoisRecord
 	iterator := k.GetNamesIterator(ctx)
 	for ; iterator.Valid(); iterator.Next() {
 
-		name := string(iterator.Key())
+		name := strings.TrimPrefix(string(iterator.Key()), types.WhoisPrefix)
 		whois := k.GetWhois(ctx, name)
-		records = append(records, whois)
+		records = append(records, WhoisRecord{Name: name, Whois: whois})
+
+	}
+	iterator.Close()
+
+	var products []Product
+	iterator = k.GetProductsIterator(ctx)
+	for ; iterator.Valid(); iterator.Next() {
+		var product Product
+		ModuleCdc.MustUnmarshalBinaryBare(iterator.Value(), &product)
+		products = append(products, product)
+	}
+	iterator.Close()
+
+	var history []ProductHistoryEntry
+	iterator = k.GetProductHistoryIterator(ctx)
+	for ; iterator.Valid(); iterator.Next() {
+		var entry ProductHistoryEntry
+		ModuleCdc.MustUnmarshalBinaryBare(iterator.Value(), &entry)
+		history = append(history, entry)
+	}
+	iterator.Close()
+
+	var auctions []Auction
+	iterator = k.GetAuctionsIterator(ctx)
+	for ; iterator.Valid(); iterator.Next() {
+		var auction Auction
+		ModuleCdc.MustUnmarshalBinaryBare(iterator.Value(), &auction)
+		auctions = append(auctions, auction)
+	}
+	iterator.Close()
+
+	var purchases []Purchase
+	iterator = k.GetPurchasesIterator(ctx)
+	for ; iterator.Valid(); iterator.Next() {
+		var purchase Purchase
+		ModuleCdc.MustUnmarshalBinaryBare(iterator.Value(), &purchase)
+		purchases = append(purchases, purchase)
+	}
+	iterator.Close()
+
+	var reviews []Review
+	iterator = k.GetReviewsIterator(ctx)
+	for ; iterator.Valid(); iterator.Next() {
+		var review Review
+		ModuleCdc.MustUnmarshalBinaryBare(iterator.Value(), &review)
+		reviews = append(reviews, review)
+	}
+	iterator.Close()
+
+	var coupons []Coupon
+	iterator = k.GetCouponsIterator(ctx)
+	for ; iterator.Valid(); iterator.Next() {
+		var coupon Coupon
+		ModuleCdc.MustUnmarshalBinaryBare(iterator.Value(), &coupon)
+		coupons = append(coupons, coupon)
+	}
+	iterator.Close()
+
+	var commitments []CouponCommitment
+	iterator = k.GetCouponCommitmentsIterator(ctx)
+	for ; iterator.Valid(); iterator.Next() {
+		var commitment CouponCommitment
+		ModuleCdc.MustUnmarshalBinaryBare(iterator.Value(), &commitment)
+		commitments = append(commitments, commitment)
+	}
+	iterator.Close()
+
+	var subscriptions []Subscription
+	iterator = k.GetSubscriptionsIterator(ctx)
+	for ; iterator.Valid(); iterator.Next() {
+		var subscription Subscription
+		ModuleCdc.MustUnmarshalBinaryBare(iterator.Value(), &subscription)
+		subscriptions = append(subscriptions, subscription)
+	}
+	iterator.Close()
 
+	var licenses []License
+	iterator = k.GetLicensesIterator(ctx)
+	for ; iterator.Valid(); iterator.Next() {
+		var license License
+		ModuleCdc.MustUnmarshalBinaryBare(iterator.Value(), &license)
+		licenses = append(licenses, license)
 	}
-	return GenesisState{WhoisRecords: records}
+	iterator.Close()
+
+	return NewGenesisState(records, products, history, auctions, purchases, reviews, coupons, subscriptions, licenses, commitments)
 }
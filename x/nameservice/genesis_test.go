@@ -0,0 +1,34 @@
+package nameservice
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/keeper"
+)
+
+func TestExportImportGenesis(t *testing.T) {
+	input, _ := setupHandlerTest(t)
+
+	exported := ExportGenesis(input.Ctx, input.Keeper)
+	require.NoError(t, ValidateGenesis(exported))
+	require.Len(t, exported.WhoisRecords, 1)
+	require.Len(t, exported.Products, 6)
+	require.Len(t, exported.ProductHistory, 6)
+	require.Len(t, exported.Auctions, 1)
+	require.Len(t, exported.Purchases, 2)
+	require.Len(t, exported.Reviews, 1)
+	require.Len(t, exported.Coupons, 1)
+	require.Len(t, exported.CouponCommitments, 1)
+	require.Len(t, exported.Subscriptions, 1)
+	require.Len(t, exported.Licenses, 1)
+
+	imported := keeper.CreateTestInput(t)
+	InitGenesis(imported.Ctx, imported.Keeper, exported)
+	require.Equal(t, exported, ExportGenesis(imported.Ctx, imported.Keeper))
+
+	// the indexes and ratings are rebuilt on import
+	require.Equal(t, []string{"book1"}, imported.Keeper.GetStorefrontProductIDs(imported.Ctx, "alice"))
+	require.Equal(t, input.Keeper.GetSellerRating(input.Ctx, owner), imported.Keeper.GetSellerRating(imported.Ctx, owner))
+}
@@ -2,6 +2,8 @@ package nameservice
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
 
@@ -12,6 +14,8 @@ import (
 // NewHandler returns a handler for "nameservice" type messages.
 func NewHandler(keeper Keeper) sdk.Handler {
 	return func(ctx sdk.Context, msg sdk.Msg) (*sdk.Result, error) {
+		ctx = ctx.WithEventManager(sdk.NewEventManager())
+
 		switch msg := msg.(type) {
 		case MsgSetName:
 			return handleMsgSetName(ctx, keeper, msg)
@@ -27,6 +31,38 @@ func NewHandler(keeper Keeper) sdk.Handler {
 			return handleMsgDeleteProduct(ctx, keeper, msg)
 		case MsgBuyProduct:
 			return handleMsgBuyProduct(ctx, keeper, msg)
+		case MsgBuyProducts:
+			return handleMsgBuyProducts(ctx, keeper, msg)
+		case MsgListProduct:
+			return handleMsgListProduct(ctx, keeper, msg)
+		case MsgDelistProduct:
+			return handleMsgDelistProduct(ctx, keeper, msg)
+		case MsgCreateAuction:
+			return handleMsgCreateAuction(ctx, keeper, msg)
+		case MsgPlaceBid:
+			return handleMsgPlaceBid(ctx, keeper, msg)
+		case MsgPublishProduct:
+			return handleMsgPublishProduct(ctx, keeper, msg)
+		case MsgUnpublishProduct:
+			return handleMsgUnpublishProduct(ctx, keeper, msg)
+		case MsgSetStorefrontSale:
+			return handleMsgSetStorefrontSale(ctx, keeper, msg)
+		case MsgReviewProduct:
+			return handleMsgReviewProduct(ctx, keeper, msg)
+		case MsgSetProductPricing:
+			return handleMsgSetProductPricing(ctx, keeper, msg)
+		case MsgCreateCoupon:
+			return handleMsgCreateCoupon(ctx, keeper, msg)
+		case MsgRevokeCoupon:
+			return handleMsgRevokeCoupon(ctx, keeper, msg)
+		case MsgCommitCoupon:
+			return handleMsgCommitCoupon(ctx, keeper, msg)
+		case MsgSetProductSubscription:
+			return handleMsgSetProductSubscription(ctx, keeper, msg)
+		case MsgCancelSubscription:
+			return handleMsgCancelSubscription(ctx, keeper, msg)
+		case MsgSetProductLicensing:
+			return handleMsgSetProductLicensing(ctx, keeper, msg)
 		default:
 			return nil, sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, fmt.Sprintf("Unrecognized nameservice Msg type: %v", msg.Type()))
 		}
@@ -39,7 +75,7 @@ func handleMsgSetName(ctx sdk.Context, keeper Keeper, msg MsgSetName) (*sdk.Resu
 		return nil, sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "Incorrect Owner") // If not, throw an error
 	}
 	keeper.SetName(ctx, msg.Name, msg.Value) // If so, set the name to the value specified in the msg.
-	return &sdk.Result{}, nil                // return
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
 }
 
 // Handle a message to buy name
@@ -54,14 +90,20 @@ func handleMsgBuyName(ctx sdk.Context, keeper Keeper, msg MsgBuyName) (*sdk.Resu
 			return nil, err
 		}
 	} else {
-		_, err := keeper.CoinKeeper.SubtractCoins(ctx, msg.Buyer, msg.Bid) // If so, deduct the Bid amount from the sender
+		// Burn the bid through the module account so the total supply stays in line with the balances
+		err := keeper.SupplyKeeper.SendCoinsFromAccountToModule(ctx, msg.Buyer, types.ModuleName, msg.Bid)
+		if err != nil {
+			return nil, err
+		}
+		err = keeper.SupplyKeeper.BurnCoins(ctx, types.ModuleName, msg.Bid)
 		if err != nil {
 			return nil, err
 		}
 	}
+	handOverStorefront(ctx, keeper, msg.Name, msg.Buyer)
 	keeper.SetOwner(ctx, msg.Name, msg.Buyer)
 	keeper.SetPrice(ctx, msg.Name, msg.Bid)
-	return &sdk.Result{}, nil
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
 }
 
 // Handle a message to delete name
@@ -73,13 +115,14 @@ func handleMsgDeleteName(ctx sdk.Context, keeper Keeper, msg MsgDeleteName) (*sd
 		return nil, sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "Incorrect Owner")
 	}
 
+	closeStorefront(ctx, keeper, msg.Name)
 	keeper.DeleteWhois(ctx, msg.Name)
-	return &sdk.Result{}, nil
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
 }
 
 // Handle a message to create product
 func handleMsgCreateProduct(ctx sdk.Context, keeper Keeper, msg MsgCreateProduct) (*sdk.Result, error) {
-	key := "Product-" + msg.ProductID
+	key := types.ProductPrefix + msg.ProductID
 
 	if keeper.IsProductPresent(ctx, key) {
 		return nil, sdkerrors.Wrap(types.ErrProductAlreadyExists, msg.ProductID)
@@ -90,15 +133,20 @@ func handleMsgCreateProduct(ctx sdk.Context, keeper Keeper, msg MsgCreateProduct
 		Description: msg.Description,
 		Price:       msg.Price,
 		Owner:       msg.Signer,
+		Listed:      true,
+		Category:    msg.Category,
+		Tags:        msg.Tags,
+		Content:     msg.Content,
 	}
+	product = keeper.BumpProductVersion(ctx, Product{}, product)
 
 	keeper.SetProduct(ctx, key, product) // If so, set the name to the value specified in the msg.
-	return &sdk.Result{}, nil            // return
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
 }
 
 // Handle a message to update product
 func handleMsgUpdateProduct(ctx sdk.Context, keeper Keeper, msg MsgUpdateProduct) (*sdk.Result, error) {
-	key := "Product-" + msg.ProductID
+	key := types.ProductPrefix + msg.ProductID
 
 	if !keeper.IsProductPresent(ctx, key) {
 		return nil, sdkerrors.Wrap(types.ErrProductDoesNotExist, msg.ProductID)
@@ -110,16 +158,26 @@ func handleMsgUpdateProduct(ctx sdk.Context, keeper Keeper, msg MsgUpdateProduct
 		return nil, sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "Incorrect Owner") // If not, throw an error
 	}
 
+	if keeper.IsAuctionPresent(ctx, msg.ProductID) {
+		return nil, sdkerrors.Wrap(types.ErrAuctionAlreadyExists, msg.ProductID)
+	}
+
+	previous := product
+
 	product.Description = msg.Description
 	product.Price = msg.Price
+	product.Category = msg.Category
+	product.Tags = msg.Tags
+	product.Content = msg.Content
+	product = keeper.BumpProductVersion(ctx, previous, product)
 
 	keeper.SetProduct(ctx, key, product) // If so, set the name to the value specified in the msg.
-	return &sdk.Result{}, nil            // return
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
 }
 
 // Handle a message to delete product
 func handleMsgDeleteProduct(ctx sdk.Context, keeper Keeper, msg MsgDeleteProduct) (*sdk.Result, error) {
-	key := "Product-" + msg.ProductID
+	key := types.ProductPrefix + msg.ProductID
 
 	if !keeper.IsProductPresent(ctx, key) {
 		return nil, sdkerrors.Wrap(types.ErrNameDoesNotExist, msg.ProductID)
@@ -128,31 +186,717 @@ func handleMsgDeleteProduct(ctx sdk.Context, keeper Keeper, msg MsgDeleteProduct
 		return nil, sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "Incorrect Owner")
 	}
 
+	if keeper.IsAuctionPresent(ctx, msg.ProductID) {
+		return nil, sdkerrors.Wrap(types.ErrAuctionAlreadyExists, msg.ProductID)
+	}
+
 	keeper.DeleteProduct(ctx, key)
-	return &sdk.Result{}, nil
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
 }
 
 // Handle a message to buy product
 func handleMsgBuyProduct(ctx sdk.Context, keeper Keeper, msg MsgBuyProduct) (*sdk.Result, error) {
-	key := "Product-" + msg.ProductID
+	if _, err := buyProduct(ctx, keeper, msg); err != nil {
+		return nil, err
+	}
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
+
+// Handle a message to buy several products at once. Any failing purchase fails
+// the whole message, so either every product is bought or none is.
+func handleMsgBuyProducts(ctx sdk.Context, keeper Keeper, msg MsgBuyProducts) (*sdk.Result, error) {
+	var total sdk.Coins
+	for _, item := range msg.Items {
+		price, err := previewPrice(ctx, keeper, item.Msg(msg.Signer))
+		if err != nil {
+			return nil, err
+		}
+		total = total.Add(price...)
+	}
+
+	if !keeper.CoinKeeper.GetCoins(ctx, msg.Signer).IsAllGTE(total) {
+		return nil, sdkerrors.Wrapf(sdkerrors.ErrInsufficientFunds, "cart total is %s", total)
+	}
+
+	var paid sdk.Coins
+	productIDs := make([]string, len(msg.Items))
+	for i, item := range msg.Items {
+		price, err := buyProduct(ctx, keeper, item.Msg(msg.Signer))
+		if err != nil {
+			return nil, sdkerrors.Wrap(err, item.ProductID)
+		}
+		paid = paid.Add(price...)
+		productIDs[i] = item.ProductID
+	}
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeBuyProducts,
+			sdk.NewAttribute(sdk.AttributeKeyModule, types.AttributeValueCategory),
+			sdk.NewAttribute(types.AttributeKeyBuyer, msg.Signer.String()),
+			sdk.NewAttribute(types.AttributeKeyProductIDs, strings.Join(productIDs, ",")),
+			sdk.NewAttribute(sdk.AttributeKeyAmount, paid.String()),
+		),
+	)
+
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
+
+// quoteProduct runs the checks of a product purchase and returns the product
+// with the price the buyer pays before any coupon discount
+func quoteProduct(ctx sdk.Context, keeper Keeper, msg MsgBuyProduct) (types.Product, sdk.Coins, error) {
+	key := types.ProductPrefix + msg.ProductID
 
 	if !keeper.IsProductPresent(ctx, key) {
-		return nil, sdkerrors.Wrap(types.ErrNameDoesNotExist, msg.ProductID)
+		return types.Product{}, nil, sdkerrors.Wrap(types.ErrNameDoesNotExist, msg.ProductID)
 	}
 
 	product := keeper.GetProduct(ctx, key)
 
 	if msg.Signer.Equals(product.Owner) {
-		return nil, sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "You are product owner")
+		return types.Product{}, nil, sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "You are product owner")
 	}
 
-	err := keeper.CoinKeeper.SendCoins(ctx, msg.Signer, product.Owner, product.Price)
+	if !product.Listed {
+		return types.Product{}, nil, sdkerrors.Wrap(types.ErrProductNotForSale, msg.ProductID)
+	}
+
+	if msg.ExpectedVersion != 0 && msg.ExpectedVersion != product.Version {
+		return types.Product{}, nil, sdkerrors.Wrapf(types.ErrProductVersionMismatch,
+			"%s is at version %d, expected %d", msg.ProductID, product.Version, msg.ExpectedVersion)
+	}
+
+	if subscription, found := keeper.GetSubscription(ctx, msg.ProductID, msg.Signer); found && !subscription.Cancelled {
+		return types.Product{}, nil, sdkerrors.Wrap(types.ErrAlreadySubscribed, msg.ProductID)
+	}
+
+	if license, found := keeper.GetLicense(ctx, msg.ProductID, msg.Signer); found && license.IsPerpetual() {
+		return types.Product{}, nil, sdkerrors.Wrap(types.ErrAlreadyLicensed, msg.ProductID)
+	}
+
+	price, err := keeper.GetProductPrice(ctx, product, msg.Denom)
+	if err != nil {
+		return types.Product{}, nil, err
+	}
+
+	return product, price, nil
+}
+
+// previewPrice returns what buying a product would cost without changing any state
+func previewPrice(ctx sdk.Context, keeper Keeper, msg MsgBuyProduct) (sdk.Coins, error) {
+	product, price, err := quoteProduct(ctx, keeper, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := resumableSubscription(ctx, keeper, product, msg.Signer); ok {
+		return nil, nil
+	}
+
+	if msg.Coupon != "" {
+		coupon, err := keeper.GetRedeemableCoupon(ctx, msg.Coupon, msg.Signer, product)
+		if err != nil {
+			return nil, err
+		}
+		price = coupon.Apply(price)
+	}
+
+	return price, nil
+}
+
+// buyProduct buys a product or subscribes to it and returns the price paid
+func buyProduct(ctx sdk.Context, keeper Keeper, msg MsgBuyProduct) (sdk.Coins, error) {
+	product, price, err := quoteProduct(ctx, keeper, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	if subscription, ok := resumableSubscription(ctx, keeper, product, msg.Signer); ok {
+		resumeSubscription(ctx, keeper, subscription)
+		return nil, nil
+	}
+
+	// Coupons only discount the first payment of a subscription
+	fullPrice := price
+
+	if msg.Coupon != "" {
+		discounted, err := keeper.RedeemCoupon(ctx, msg.Coupon, msg.Signer, product, price)
+		if err != nil {
+			return nil, err
+		}
+
+		ctx.EventManager().EmitEvent(
+			sdk.NewEvent(
+				types.EventTypeRedeemCoupon,
+				sdk.NewAttribute(sdk.AttributeKeyModule, types.AttributeValueCategory),
+				sdk.NewAttribute(types.AttributeKeyCouponHash, types.HashCouponCode(msg.Coupon)),
+				sdk.NewAttribute(types.AttributeKeyProductID, msg.ProductID),
+				sdk.NewAttribute(types.AttributeKeyBuyer, msg.Signer.String()),
+				sdk.NewAttribute(types.AttributeKeyDiscount, price.Sub(discounted).String()),
+			),
+		)
+		price = discounted
+	}
+
+	err = keeper.CoinKeeper.SendCoins(ctx, msg.Signer, product.Owner, price)
 	if err != nil {
 		return nil, err
 	}
 
+	keeper.SetPurchase(ctx, types.Purchase{
+		ProductID: msg.ProductID,
+		Buyer:     msg.Signer,
+		Seller:    product.Owner,
+		Price:     price,
+		Height:    ctx.BlockHeight(),
+	})
+
+	if product.IsSubscription() {
+		subscription := types.NewSubscription(msg.ProductID, msg.Signer, fullPrice, product.SubscriptionPeriod, ctx.BlockHeight())
+		keeper.SetSubscription(ctx, subscription)
+
+		ctx.EventManager().EmitEvent(
+			sdk.NewEvent(
+				types.EventTypeSubscribe,
+				sdk.NewAttribute(sdk.AttributeKeyModule, types.AttributeValueCategory),
+				sdk.NewAttribute(types.AttributeKeyProductID, msg.ProductID),
+				sdk.NewAttribute(types.AttributeKeySubscriber, msg.Signer.String()),
+				sdk.NewAttribute(types.AttributeKeyPaidUntil, strconv.FormatInt(subscription.PaidUntil, 10)),
+			),
+		)
+
+		// The seller keeps a subscription product
+		return price, nil
+	}
+
+	if product.Licensing {
+		license := grantLicense(ctx, keeper, product, msg.Signer)
+
+		ctx.EventManager().EmitEvent(
+			sdk.NewEvent(
+				types.EventTypeGrantLicense,
+				sdk.NewAttribute(sdk.AttributeKeyModule, types.AttributeValueCategory),
+				sdk.NewAttribute(types.AttributeKeyProductID, msg.ProductID),
+				sdk.NewAttribute(types.AttributeKeyHolder, msg.Signer.String()),
+				sdk.NewAttribute(types.AttributeKeyExpiry, strconv.FormatInt(license.ExpiryHeight, 10)),
+			),
+		)
+
+		// The seller keeps a licensing product and goes on selling licenses
+		return price, nil
+	}
+
 	product.Owner = msg.Signer
+	product.Listed = false  // The new owner has to relist the product to sell it again
+	product.Storefront = "" // and publish it in a storefront of their own
+
+	keeper.SetProduct(ctx, types.ProductPrefix+msg.ProductID, product)
+	return price, nil
+}
+
+// Handle a message to list product for sale
+func handleMsgListProduct(ctx sdk.Context, keeper Keeper, msg MsgListProduct) (*sdk.Result, error) {
+	key := types.ProductPrefix + msg.ProductID
+
+	if !keeper.IsProductPresent(ctx, key) {
+		return nil, sdkerrors.Wrap(types.ErrProductDoesNotExist, msg.ProductID)
+	}
+
+	product := keeper.GetProduct(ctx, key)
+
+	if !msg.Signer.Equals(product.Owner) {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "Incorrect Owner")
+	}
+
+	if keeper.IsAuctionPresent(ctx, msg.ProductID) {
+		return nil, sdkerrors.Wrap(types.ErrAuctionAlreadyExists, msg.ProductID)
+	}
+
+	product.Listed = true
+
+	keeper.SetProduct(ctx, key, product)
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
+
+// Handle a message to withdraw product from sale
+func handleMsgDelistProduct(ctx sdk.Context, keeper Keeper, msg MsgDelistProduct) (*sdk.Result, error) {
+	key := types.ProductPrefix + msg.ProductID
+
+	if !keeper.IsProductPresent(ctx, key) {
+		return nil, sdkerrors.Wrap(types.ErrProductDoesNotExist, msg.ProductID)
+	}
+
+	product := keeper.GetProduct(ctx, key)
+
+	if !msg.Signer.Equals(product.Owner) {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "Incorrect Owner")
+	}
+
+	product.Listed = false
+
+	keeper.SetProduct(ctx, key, product)
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
+
+// Handle a message to put a product up for auction
+func handleMsgCreateAuction(ctx sdk.Context, keeper Keeper, msg MsgCreateAuction) (*sdk.Result, error) {
+	key := types.ProductPrefix + msg.ProductID
+
+	if !keeper.IsProductPresent(ctx, key) {
+		return nil, sdkerrors.Wrap(types.ErrProductDoesNotExist, msg.ProductID)
+	}
+
+	product := keeper.GetProduct(ctx, key)
+
+	if !msg.Signer.Equals(product.Owner) {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "Incorrect Owner")
+	}
+
+	if keeper.IsAuctionPresent(ctx, msg.ProductID) {
+		return nil, sdkerrors.Wrap(types.ErrAuctionAlreadyExists, msg.ProductID)
+	}
+
+	if product.IsSubscription() {
+		return nil, sdkerrors.Wrap(types.ErrSubscriptionProduct, msg.ProductID)
+	}
+
+	if product.Licensing {
+		return nil, sdkerrors.Wrap(types.ErrLicensedProduct, msg.ProductID)
+	}
+
+	// A product in auction can only be bought through a bid
+	product.Listed = false
+	keeper.SetProduct(ctx, key, product)
+
+	auction := types.NewAuction(msg.ProductID, msg.Signer, msg.AuctionType, msg.StartPrice, msg.ReservePrice,
+		msg.Decrement, ctx.BlockHeight(), ctx.BlockHeight()+msg.Duration)
+
+	keeper.SetAuction(ctx, auction)
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
+
+// Handle a message to bid on an auction
+func handleMsgPlaceBid(ctx sdk.Context, keeper Keeper, msg MsgPlaceBid) (*sdk.Result, error) {
+	if !keeper.IsAuctionPresent(ctx, msg.ProductID) {
+		return nil, sdkerrors.Wrap(types.ErrAuctionDoesNotExist, msg.ProductID)
+	}
+
+	auction := keeper.GetAuction(ctx, msg.ProductID)
+
+	if auction.IsFinished(ctx.BlockHeight()) {
+		return nil, sdkerrors.Wrap(types.ErrAuctionFinished, msg.ProductID)
+	}
+
+	if msg.Bidder.Equals(auction.Seller) {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "You are product owner")
+	}
+
+	price := auction.CurrentPrice(ctx.BlockHeight())
+	if !msg.Amount.IsAllGTE(price) {
+		return nil, sdkerrors.Wrapf(types.ErrBidTooLow, "bid must be at least %s", price)
+	}
+
+	bid := types.Bid{Bidder: msg.Bidder, Amount: msg.Amount, Height: ctx.BlockHeight()}
+
+	switch auction.AuctionType {
+	case types.AuctionDutch:
+		// The first bidder wins a dutch auction at the current price
+		bid.Amount = price
+	default:
+		highest, ok := auction.HighestBid()
+		if ok && !msg.Amount.IsAllGT(highest.Amount) {
+			return nil, sdkerrors.Wrapf(types.ErrBidTooLow, "bid must be higher than %s", highest.Amount)
+		}
+		if ok {
+			// Refund the outbid bidder from escrow
+			err := keeper.SupplyKeeper.SendCoinsFromModuleToAccount(ctx, types.ModuleName, highest.Bidder, highest.Amount)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	err := keeper.SupplyKeeper.SendCoinsFromAccountToModule(ctx, msg.Bidder, types.ModuleName, bid.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	auction.Bids = append(auction.Bids, bid)
+
+	keeper.SetAuction(ctx, auction)
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
+
+// Handle a message to publish a product in the storefront of a name
+func handleMsgPublishProduct(ctx sdk.Context, keeper Keeper, msg MsgPublishProduct) (*sdk.Result, error) {
+	if !msg.Signer.Equals(keeper.GetOwner(ctx, msg.Name)) { // Only the name owner can publish under the name
+		return nil, sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "Incorrect Name Owner")
+	}
+
+	key := types.ProductPrefix + msg.ProductID
+
+	if !keeper.IsProductPresent(ctx, key) {
+		return nil, sdkerrors.Wrap(types.ErrProductDoesNotExist, msg.ProductID)
+	}
+
+	product := keeper.GetProduct(ctx, key)
+
+	if !msg.Signer.Equals(product.Owner) {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "Incorrect Owner")
+	}
+
+	product.Storefront = msg.Name
 
 	keeper.SetProduct(ctx, key, product)
-	return &sdk.Result{}, nil
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
+
+// Handle a message to remove a product from its storefront
+func handleMsgUnpublishProduct(ctx sdk.Context, keeper Keeper, msg MsgUnpublishProduct) (*sdk.Result, error) {
+	key := types.ProductPrefix + msg.ProductID
+
+	if !keeper.IsProductPresent(ctx, key) {
+		return nil, sdkerrors.Wrap(types.ErrProductDoesNotExist, msg.ProductID)
+	}
+
+	product := keeper.GetProduct(ctx, key)
+
+	if !msg.Signer.Equals(product.Owner) {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "Incorrect Owner")
+	}
+
+	if product.Storefront == "" {
+		return nil, sdkerrors.Wrap(types.ErrProductNotPublished, msg.ProductID)
+	}
+
+	product.Storefront = ""
+
+	keeper.SetProduct(ctx, key, product)
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
+
+// Handle a message to choose whether a name is sold along with its storefront
+func handleMsgSetStorefrontSale(ctx sdk.Context, keeper Keeper, msg MsgSetStorefrontSale) (*sdk.Result, error) {
+	if !msg.Owner.Equals(keeper.GetOwner(ctx, msg.Name)) {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "Incorrect Owner")
+	}
+
+	whois := keeper.GetWhois(ctx, msg.Name)
+	whois.StorefrontIncluded = msg.Included
+
+	keeper.SetWhois(ctx, msg.Name, whois)
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
+
+// handOverStorefront transfers the products published under a name to its buyer when
+// the storefront is part of the sale, and unpublishes them otherwise.
+func handOverStorefront(ctx sdk.Context, keeper Keeper, name string, buyer sdk.AccAddress) {
+	if !keeper.GetWhois(ctx, name).StorefrontIncluded {
+		closeStorefront(ctx, keeper, name)
+		return
+	}
+
+	for _, productID := range keeper.GetStorefrontProductIDs(ctx, name) {
+		key := types.ProductPrefix + productID
+		product := keeper.GetProduct(ctx, key)
+
+		// A product in auction stays with the seller until the auction settles
+		if keeper.IsAuctionPresent(ctx, productID) {
+			product.Storefront = ""
+		} else {
+			product.Owner = buyer
+		}
+
+		keeper.SetProduct(ctx, key, product)
+	}
+}
+
+// closeStorefront unpublishes every product published under a name
+func closeStorefront(ctx sdk.Context, keeper Keeper, name string) {
+	for _, productID := range keeper.GetStorefrontProductIDs(ctx, name) {
+		key := types.ProductPrefix + productID
+		product := keeper.GetProduct(ctx, key)
+		product.Storefront = ""
+		keeper.SetProduct(ctx, key, product)
+	}
+}
+
+// Handle a message to review a purchased product
+func handleMsgReviewProduct(ctx sdk.Context, keeper Keeper, msg MsgReviewProduct) (*sdk.Result, error) {
+	purchase, ok := keeper.GetPurchase(ctx, msg.ProductID, msg.Reviewer)
+	if !ok {
+		return nil, sdkerrors.Wrap(types.ErrNotPurchased, msg.ProductID)
+	}
+
+	if keeper.HasReview(ctx, msg.ProductID, msg.Reviewer) {
+		return nil, sdkerrors.Wrap(types.ErrAlreadyReviewed, msg.ProductID)
+	}
+
+	keeper.SetReview(ctx, types.Review{
+		ProductID: msg.ProductID,
+		Reviewer:  msg.Reviewer,
+		Seller:    purchase.Seller,
+		Rating:    msg.Rating,
+		Text:      msg.Text,
+		Height:    ctx.BlockHeight(),
+	})
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
+
+// Handle a message to set the alternative and pegged prices of a product
+func handleMsgSetProductPricing(ctx sdk.Context, keeper Keeper, msg MsgSetProductPricing) (*sdk.Result, error) {
+	key := types.ProductPrefix + msg.ProductID
+
+	if !keeper.IsProductPresent(ctx, key) {
+		return nil, sdkerrors.Wrap(types.ErrProductDoesNotExist, msg.ProductID)
+	}
+
+	product := keeper.GetProduct(ctx, key)
+
+	if !msg.Signer.Equals(product.Owner) {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "Incorrect Owner")
+	}
+
+	previous := product
+
+	product.AcceptedPrices = msg.AcceptedPrices
+	product.ReferencePrice = msg.ReferencePrice
+	product = keeper.BumpProductVersion(ctx, previous, product)
+
+	keeper.SetProduct(ctx, key, product)
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
+
+// Handle a message to create a discount coupon
+func handleMsgCreateCoupon(ctx sdk.Context, keeper Keeper, msg MsgCreateCoupon) (*sdk.Result, error) {
+	if _, found := keeper.GetCoupon(ctx, msg.CodeHash); found {
+		return nil, sdkerrors.Wrap(types.ErrCouponAlreadyExists, msg.CodeHash)
+	}
+
+	if msg.ProductID != "" {
+		key := types.ProductPrefix + msg.ProductID
+
+		if !keeper.IsProductPresent(ctx, key) {
+			return nil, sdkerrors.Wrap(types.ErrProductDoesNotExist, msg.ProductID)
+		}
+
+		if !msg.Issuer.Equals(keeper.GetProduct(ctx, key).Owner) {
+			return nil, sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "Incorrect Owner")
+		}
+	}
+
+	if msg.ExpiryHeight != 0 && msg.ExpiryHeight < ctx.BlockHeight() {
+		return nil, sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "Expiry height %d has already passed", msg.ExpiryHeight)
+	}
+
+	keeper.SetCoupon(ctx, types.Coupon{
+		CodeHash:     msg.CodeHash,
+		Issuer:       msg.Issuer,
+		ProductID:    msg.ProductID,
+		Percent:      msg.Percent,
+		Amount:       msg.Amount,
+		ExpiryHeight: msg.ExpiryHeight,
+		MaxUses:      msg.MaxUses,
+	})
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeCreateCoupon,
+			sdk.NewAttribute(sdk.AttributeKeyModule, types.AttributeValueCategory),
+			sdk.NewAttribute(types.AttributeKeyCouponHash, msg.CodeHash),
+			sdk.NewAttribute(types.AttributeKeyIssuer, msg.Issuer.String()),
+			sdk.NewAttribute(types.AttributeKeyProductID, msg.ProductID),
+		),
+	)
+
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
+
+// Handle a message to revoke a discount coupon
+func handleMsgRevokeCoupon(ctx sdk.Context, keeper Keeper, msg MsgRevokeCoupon) (*sdk.Result, error) {
+	coupon, found := keeper.GetCoupon(ctx, msg.CodeHash)
+	if !found {
+		return nil, sdkerrors.Wrap(types.ErrCouponDoesNotExist, msg.CodeHash)
+	}
+
+	if !msg.Issuer.Equals(coupon.Issuer) {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "Incorrect Issuer")
+	}
+
+	keeper.DeleteCoupon(ctx, msg.CodeHash)
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeRevokeCoupon,
+			sdk.NewAttribute(sdk.AttributeKeyModule, types.AttributeValueCategory),
+			sdk.NewAttribute(types.AttributeKeyCouponHash, msg.CodeHash),
+			sdk.NewAttribute(types.AttributeKeyIssuer, msg.Issuer.String()),
+		),
+	)
+
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
+
+// Handle a message to commit to the code of a coupon before redeeming it
+func handleMsgCommitCoupon(ctx sdk.Context, keeper Keeper, msg MsgCommitCoupon) (*sdk.Result, error) {
+	if _, found := keeper.GetCoupon(ctx, msg.CodeHash); !found {
+		return nil, sdkerrors.Wrap(types.ErrCouponDoesNotExist, msg.CodeHash)
+	}
+
+	// Committing again keeps the height of the first commitment
+	if _, found := keeper.GetCouponCommitment(ctx, msg.CodeHash, msg.Commitment); !found {
+		keeper.SetCouponCommitment(ctx, types.CouponCommitment{
+			CodeHash:   msg.CodeHash,
+			Commitment: msg.Commitment,
+			Signer:     msg.Signer,
+			Height:     ctx.BlockHeight(),
+		})
+	}
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeCommitCoupon,
+			sdk.NewAttribute(sdk.AttributeKeyModule, types.AttributeValueCategory),
+			sdk.NewAttribute(types.AttributeKeyCouponHash, msg.CodeHash),
+			sdk.NewAttribute(types.AttributeKeyBuyer, msg.Signer.String()),
+		),
+	)
+
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
+
+// Handle a message to sell a product by subscription or as a one-shot sale again
+func handleMsgSetProductSubscription(ctx sdk.Context, keeper Keeper, msg MsgSetProductSubscription) (*sdk.Result, error) {
+	key := types.ProductPrefix + msg.ProductID
+
+	if !keeper.IsProductPresent(ctx, key) {
+		return nil, sdkerrors.Wrap(types.ErrProductDoesNotExist, msg.ProductID)
+	}
+
+	product := keeper.GetProduct(ctx, key)
+
+	if !msg.Signer.Equals(product.Owner) {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "Incorrect Owner")
+	}
+
+	if keeper.IsAuctionPresent(ctx, msg.ProductID) {
+		return nil, sdkerrors.Wrap(types.ErrAuctionAlreadyExists, msg.ProductID)
+	}
+
+	if product.Licensing && msg.Period > 0 {
+		return nil, sdkerrors.Wrap(types.ErrLicensedProduct, msg.ProductID)
+	}
+
+	previous := product
+
+	// Existing subscriptions keep the period they were started with
+	product.SubscriptionPeriod = msg.Period
+	product = keeper.BumpProductVersion(ctx, previous, product)
+
+	keeper.SetProduct(ctx, key, product)
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
+
+// Handle a message to cancel a subscription, paid access lasts until the end of the current period
+func handleMsgCancelSubscription(ctx sdk.Context, keeper Keeper, msg MsgCancelSubscription) (*sdk.Result, error) {
+	subscription, found := keeper.GetSubscription(ctx, msg.ProductID, msg.Subscriber)
+	if !found || subscription.Cancelled {
+		return nil, sdkerrors.Wrap(types.ErrSubscriptionDoesNotExist, msg.ProductID)
+	}
+
+	subscription.Cancelled = true
+	keeper.SetSubscription(ctx, subscription)
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeCancelSubscription,
+			sdk.NewAttribute(sdk.AttributeKeyModule, types.AttributeValueCategory),
+			sdk.NewAttribute(types.AttributeKeyProductID, msg.ProductID),
+			sdk.NewAttribute(types.AttributeKeySubscriber, msg.Subscriber.String()),
+			sdk.NewAttribute(types.AttributeKeyReason, types.AttributeValueCancelledBySubscriber),
+		),
+	)
+
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
+
+// resumableSubscription returns the cancelled subscription of a subscriber that is
+// still paid for, which buying the product again resumes without charging
+func resumableSubscription(ctx sdk.Context, keeper Keeper, product types.Product, subscriber sdk.AccAddress) (types.Subscription, bool) {
+	if !product.IsSubscription() {
+		return types.Subscription{}, false
+	}
+
+	subscription, found := keeper.GetSubscription(ctx, product.ProductID, subscriber)
+	if !found || !subscription.Cancelled || !subscription.IsActive(ctx.BlockHeight()) {
+		return types.Subscription{}, false
+	}
+	return subscription, true
+}
+
+// resumeSubscription renews a cancelled subscription that is still paid for
+func resumeSubscription(ctx sdk.Context, keeper Keeper, subscription types.Subscription) {
+	subscription.Cancelled = false
+	keeper.SetSubscription(ctx, subscription)
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeSubscribe,
+			sdk.NewAttribute(sdk.AttributeKeyModule, types.AttributeValueCategory),
+			sdk.NewAttribute(types.AttributeKeyProductID, subscription.ProductID),
+			sdk.NewAttribute(types.AttributeKeySubscriber, subscription.Subscriber.String()),
+			sdk.NewAttribute(types.AttributeKeyPaidUntil, strconv.FormatInt(subscription.PaidUntil, 10)),
+		),
+	)
+}
+
+// Handle a message to sell licenses of a product instead of the product itself
+func handleMsgSetProductLicensing(ctx sdk.Context, keeper Keeper, msg MsgSetProductLicensing) (*sdk.Result, error) {
+	key := types.ProductPrefix + msg.ProductID
+
+	if !keeper.IsProductPresent(ctx, key) {
+		return nil, sdkerrors.Wrap(types.ErrProductDoesNotExist, msg.ProductID)
+	}
+
+	product := keeper.GetProduct(ctx, key)
+
+	if !msg.Signer.Equals(product.Owner) {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "Incorrect Owner")
+	}
+
+	if keeper.IsAuctionPresent(ctx, msg.ProductID) {
+		return nil, sdkerrors.Wrap(types.ErrAuctionAlreadyExists, msg.ProductID)
+	}
+
+	if product.IsSubscription() && msg.Licensing {
+		return nil, sdkerrors.Wrap(types.ErrSubscriptionProduct, msg.ProductID)
+	}
+
+	previous := product
+
+	// Licenses already granted keep their expiry
+	product.Licensing = msg.Licensing
+	product.LicenseDuration = msg.Duration
+	product = keeper.BumpProductVersion(ctx, previous, product)
+
+	keeper.SetProduct(ctx, key, product)
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
+
+// grantLicense records the license bought by a holder. Buying a timed license
+// again while it is still valid extends it by another duration.
+func grantLicense(ctx sdk.Context, keeper Keeper, product types.Product, holder sdk.AccAddress) types.License {
+	license, found := keeper.GetLicense(ctx, product.ProductID, holder)
+
+	switch {
+	case found && license.IsValid(ctx.BlockHeight()) && product.LicenseDuration > 0:
+		license.ExpiryHeight += product.LicenseDuration
+	case found && license.IsValid(ctx.BlockHeight()):
+		license.ExpiryHeight = 0
+	default:
+		license = types.NewLicense(product.ProductID, holder, ctx.BlockHeight(), product.LicenseDuration)
+	}
+
+	keeper.SetLicense(ctx, license)
+	return license
 }
@@ -0,0 +1,289 @@
+package nameservice
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/keeper"
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
+
+var (
+	owner    = keeper.Addrs[0]
+	buyer    = keeper.Addrs[1]
+	stranger = keeper.Addrs[2]
+
+	price     = sdk.NewCoins(sdk.NewInt64Coin("nametoken", 10))
+	highPrice = sdk.NewCoins(sdk.NewInt64Coin("nametoken", 5000))
+)
+
+// setupHandlerTest stores a name owned by owner together with products covering
+// every sale mode, an auction, a coupon buyer committed to and the purchases made by
+// buyer and stranger
+func setupHandlerTest(t *testing.T) (keeper.TestInput, sdk.Handler) {
+	input := keeper.CreateTestInput(t)
+	ctx, k := input.Ctx, input.Keeper
+
+	k.SetWhois(ctx, "alice", types.Whois{Value: "1.1.1.1", Owner: owner, Price: price})
+
+	products := []types.Product{
+		{ProductID: "book1", Owner: owner, Price: price, Listed: true, Storefront: "alice"},
+		{ProductID: "album1", Owner: owner, Price: price},
+		{ProductID: "yacht1", Owner: owner, Price: highPrice, Listed: true},
+		{ProductID: "rare1", Owner: owner, Price: price},
+		{ProductID: "magazine1", Owner: owner, Price: price, Listed: true, SubscriptionPeriod: 10},
+		{ProductID: "ebook1", Owner: owner, Price: price, Listed: true, Licensing: true},
+	}
+	for _, product := range products {
+		k.SetProduct(ctx, "Product-"+product.ProductID, k.BumpProductVersion(ctx, types.Product{}, product))
+	}
+
+	k.SetAuction(ctx, types.NewAuction("rare1", owner, types.AuctionEnglish, price, nil, nil, ctx.BlockHeight(), ctx.BlockHeight()+10))
+
+	k.SetCoupon(ctx, types.Coupon{CodeHash: types.HashCouponCode("HALF"), Issuer: owner, Percent: 50})
+	k.SetCouponCommitment(ctx, types.CouponCommitment{CodeHash: types.HashCouponCode("HALF"),
+		Commitment: types.HashCouponCommitment("HALF", buyer), Signer: buyer, Height: ctx.BlockHeight() - 1})
+
+	k.SetPurchase(ctx, types.Purchase{ProductID: "book1", Buyer: buyer, Seller: owner, Price: price, Height: 1})
+	k.SetPurchase(ctx, types.Purchase{ProductID: "magazine1", Buyer: stranger, Seller: owner, Price: price, Height: 1})
+	k.SetReview(ctx, types.Review{ProductID: "magazine1", Reviewer: stranger, Seller: owner, Rating: 5, Height: 1})
+	k.SetSubscription(ctx, types.NewSubscription("magazine1", stranger, price, 10, 1))
+	k.SetLicense(ctx, types.NewLicense("ebook1", stranger, 1, 0))
+
+	return input, NewHandler(k)
+}
+
+func TestHandler(t *testing.T) {
+	bookItem := types.NewCartItem("book1", "", "", 0)
+
+	tests := []struct {
+		name   string
+		msg    sdk.Msg
+		expErr error
+	}{
+		{"set name", NewMsgSetName("alice", "8.8.8.8", owner), nil},
+		{"set name of another owner", NewMsgSetName("alice", "8.8.8.8", stranger), sdkerrors.ErrUnauthorized},
+
+		{"buy new name", NewMsgBuyName("bob", price, buyer), nil},
+		{"buy owned name", NewMsgBuyName("alice", price.Add(price...), buyer), nil},
+		{"buy name with low bid", NewMsgBuyName("alice", sdk.NewCoins(sdk.NewInt64Coin("nametoken", 5)), buyer), sdkerrors.ErrInsufficientFunds},
+		{"buy name without funds", NewMsgBuyName("alice", highPrice, buyer), sdkerrors.ErrInsufficientFunds},
+		{"buy new name without funds", NewMsgBuyName("bob", highPrice, buyer), sdkerrors.ErrInsufficientFunds},
+
+		{"delete name", NewMsgDeleteName("alice", owner), nil},
+		{"delete missing name", NewMsgDeleteName("bob", owner), types.ErrNameDoesNotExist},
+		{"delete name of another owner", NewMsgDeleteName("alice", stranger), sdkerrors.ErrUnauthorized},
+
+		{"create product", NewMsgCreateProduct("pen1", "a pen", price, "", nil, types.Content{}, buyer), nil},
+		{"create existing product", NewMsgCreateProduct("book1", "a book", price, "", nil, types.Content{}, buyer), types.ErrProductAlreadyExists},
+
+		{"update product", NewMsgUpdateProduct("book1", "a book", price, "", nil, types.Content{}, owner), nil},
+		{"update missing product", NewMsgUpdateProduct("pen1", "a pen", price, "", nil, types.Content{}, owner), types.ErrProductDoesNotExist},
+		{"update product of another owner", NewMsgUpdateProduct("book1", "a book", price, "", nil, types.Content{}, stranger), sdkerrors.ErrUnauthorized},
+		{"update product in auction", NewMsgUpdateProduct("rare1", "a rarity", price, "", nil, types.Content{}, owner), types.ErrAuctionAlreadyExists},
+
+		{"delete product", NewMsgDeleteProduct("book1", owner), nil},
+		{"delete missing product", NewMsgDeleteProduct("pen1", owner), types.ErrNameDoesNotExist},
+		{"delete product of another owner", NewMsgDeleteProduct("book1", stranger), sdkerrors.ErrUnauthorized},
+		{"delete product in auction", NewMsgDeleteProduct("rare1", owner), types.ErrAuctionAlreadyExists},
+
+		{"buy product", NewMsgBuyProduct("book1", "", "", 0, buyer), nil},
+		{"buy product with coupon", NewMsgBuyProduct("book1", "", "HALF", 0, buyer), nil},
+		{"buy product at expected version", NewMsgBuyProduct("book1", "", "", 1, buyer), nil},
+		{"buy missing product", NewMsgBuyProduct("pen1", "", "", 0, buyer), types.ErrNameDoesNotExist},
+		{"buy own product", NewMsgBuyProduct("book1", "", "", 0, owner), sdkerrors.ErrUnauthorized},
+		{"buy unlisted product", NewMsgBuyProduct("album1", "", "", 0, buyer), types.ErrProductNotForSale},
+		{"buy product without funds", NewMsgBuyProduct("yacht1", "", "", 0, buyer), sdkerrors.ErrInsufficientFunds},
+		{"buy product at stale version", NewMsgBuyProduct("book1", "", "", 2, buyer), types.ErrProductVersionMismatch},
+		{"buy product with missing coupon", NewMsgBuyProduct("book1", "", "NONE", 0, buyer), types.ErrCouponDoesNotExist},
+		{"buy product with uncommitted coupon", NewMsgBuyProduct("book1", "", "HALF", 0, stranger), types.ErrCouponNotCommitted},
+		{"buy product in unaccepted denomination", NewMsgBuyProduct("book1", "atom", "", 0, buyer), types.ErrDenomNotAccepted},
+		{"subscribe", NewMsgBuyProduct("magazine1", "", "", 0, buyer), nil},
+		{"subscribe again", NewMsgBuyProduct("magazine1", "", "", 0, stranger), types.ErrAlreadySubscribed},
+		{"buy license", NewMsgBuyProduct("ebook1", "", "", 0, buyer), nil},
+		{"buy perpetual license again", NewMsgBuyProduct("ebook1", "", "", 0, stranger), types.ErrAlreadyLicensed},
+
+		{"buy cart", types.NewMsgBuyProducts([]types.CartItem{bookItem, types.NewCartItem("ebook1", "", "", 0)}, buyer), nil},
+		{"buy cart with missing product", types.NewMsgBuyProducts([]types.CartItem{bookItem, types.NewCartItem("pen1", "", "", 0)}, buyer), types.ErrNameDoesNotExist},
+		{"buy cart without funds", types.NewMsgBuyProducts([]types.CartItem{bookItem, types.NewCartItem("yacht1", "", "", 0)}, buyer), sdkerrors.ErrInsufficientFunds},
+
+		{"list product", NewMsgListProduct("album1", owner), nil},
+		{"list missing product", NewMsgListProduct("pen1", owner), types.ErrProductDoesNotExist},
+		{"list product of another owner", NewMsgListProduct("album1", stranger), sdkerrors.ErrUnauthorized},
+		{"list product in auction", NewMsgListProduct("rare1", owner), types.ErrAuctionAlreadyExists},
+
+		{"delist product", NewMsgDelistProduct("book1", owner), nil},
+		{"delist missing product", NewMsgDelistProduct("pen1", owner), types.ErrProductDoesNotExist},
+		{"delist product of another owner", NewMsgDelistProduct("book1", stranger), sdkerrors.ErrUnauthorized},
+
+		{"create auction", NewMsgCreateAuction("album1", types.AuctionEnglish, price, nil, nil, 10, owner), nil},
+		{"create auction of missing product", NewMsgCreateAuction("pen1", types.AuctionEnglish, price, nil, nil, 10, owner), types.ErrProductDoesNotExist},
+		{"create auction of another owner", NewMsgCreateAuction("album1", types.AuctionEnglish, price, nil, nil, 10, stranger), sdkerrors.ErrUnauthorized},
+		{"create second auction", NewMsgCreateAuction("rare1", types.AuctionEnglish, price, nil, nil, 10, owner), types.ErrAuctionAlreadyExists},
+		{"create auction of subscription", NewMsgCreateAuction("magazine1", types.AuctionEnglish, price, nil, nil, 10, owner), types.ErrSubscriptionProduct},
+		{"create auction of licensed product", NewMsgCreateAuction("ebook1", types.AuctionEnglish, price, nil, nil, 10, owner), types.ErrLicensedProduct},
+
+		{"place bid", NewMsgPlaceBid("rare1", price, buyer), nil},
+		{"place bid on missing auction", NewMsgPlaceBid("book1", price, buyer), types.ErrAuctionDoesNotExist},
+		{"place bid on own auction", NewMsgPlaceBid("rare1", price, owner), sdkerrors.ErrUnauthorized},
+		{"place low bid", NewMsgPlaceBid("rare1", sdk.NewCoins(sdk.NewInt64Coin("nametoken", 5)), buyer), types.ErrBidTooLow},
+		{"place bid without funds", NewMsgPlaceBid("rare1", highPrice, buyer), sdkerrors.ErrInsufficientFunds},
+
+		{"publish product", NewMsgPublishProduct("alice", "album1", owner), nil},
+		{"publish under another name", NewMsgPublishProduct("bob", "album1", owner), sdkerrors.ErrUnauthorized},
+		{"publish missing product", NewMsgPublishProduct("alice", "pen1", owner), types.ErrProductDoesNotExist},
+
+		{"unpublish product", NewMsgUnpublishProduct("book1", owner), nil},
+		{"unpublish missing product", NewMsgUnpublishProduct("pen1", owner), types.ErrProductDoesNotExist},
+		{"unpublish product of another owner", NewMsgUnpublishProduct("book1", stranger), sdkerrors.ErrUnauthorized},
+		{"unpublish unpublished product", NewMsgUnpublishProduct("album1", owner), types.ErrProductNotPublished},
+
+		{"set storefront sale", NewMsgSetStorefrontSale("alice", true, owner), nil},
+		{"set storefront sale of another owner", NewMsgSetStorefrontSale("alice", true, stranger), sdkerrors.ErrUnauthorized},
+
+		{"review product", NewMsgReviewProduct("book1", 4, "good", buyer), nil},
+		{"review product not purchased", NewMsgReviewProduct("book1", 4, "good", stranger), types.ErrNotPurchased},
+		{"review product twice", NewMsgReviewProduct("magazine1", 4, "good", stranger), types.ErrAlreadyReviewed},
+
+		{"set product pricing", NewMsgSetProductPricing("book1", highPrice, nil, owner), nil},
+		{"set pricing of missing product", NewMsgSetProductPricing("pen1", highPrice, nil, owner), types.ErrProductDoesNotExist},
+		{"set pricing of another owner", NewMsgSetProductPricing("book1", highPrice, nil, stranger), sdkerrors.ErrUnauthorized},
+
+		{"create coupon", NewMsgCreateCoupon(types.HashCouponCode("TENOFF"), "book1", 10, nil, 0, 0, owner), nil},
+		{"create existing coupon", NewMsgCreateCoupon(types.HashCouponCode("HALF"), "", 10, nil, 0, 0, owner), types.ErrCouponAlreadyExists},
+		{"create coupon for missing product", NewMsgCreateCoupon(types.HashCouponCode("TENOFF"), "pen1", 10, nil, 0, 0, owner), types.ErrProductDoesNotExist},
+		{"create coupon for another owner", NewMsgCreateCoupon(types.HashCouponCode("TENOFF"), "book1", 10, nil, 0, 0, stranger), sdkerrors.ErrUnauthorized},
+
+		{"revoke coupon", NewMsgRevokeCoupon(types.HashCouponCode("HALF"), owner), nil},
+		{"revoke missing coupon", NewMsgRevokeCoupon(types.HashCouponCode("NONE"), owner), types.ErrCouponDoesNotExist},
+		{"revoke coupon of another issuer", NewMsgRevokeCoupon(types.HashCouponCode("HALF"), stranger), sdkerrors.ErrUnauthorized},
+
+		{"commit coupon", NewMsgCommitCoupon(types.HashCouponCode("HALF"), types.HashCouponCommitment("HALF", stranger), stranger), nil},
+		{"commit missing coupon", NewMsgCommitCoupon(types.HashCouponCode("NONE"), types.HashCouponCommitment("NONE", stranger), stranger), types.ErrCouponDoesNotExist},
+
+		{"set product subscription", NewMsgSetProductSubscription("book1", 10, owner), nil},
+		{"set subscription of missing product", NewMsgSetProductSubscription("pen1", 10, owner), types.ErrProductDoesNotExist},
+		{"set subscription of another owner", NewMsgSetProductSubscription("book1", 10, stranger), sdkerrors.ErrUnauthorized},
+		{"set subscription in auction", NewMsgSetProductSubscription("rare1", 10, owner), types.ErrAuctionAlreadyExists},
+		{"set subscription of licensed product", NewMsgSetProductSubscription("ebook1", 10, owner), types.ErrLicensedProduct},
+
+		{"cancel subscription", NewMsgCancelSubscription("magazine1", stranger), nil},
+		{"cancel missing subscription", NewMsgCancelSubscription("magazine1", buyer), types.ErrSubscriptionDoesNotExist},
+
+		{"set product licensing", NewMsgSetProductLicensing("book1", true, 0, owner), nil},
+		{"set licensing of missing product", NewMsgSetProductLicensing("pen1", true, 0, owner), types.ErrProductDoesNotExist},
+		{"set licensing of another owner", NewMsgSetProductLicensing("book1", true, 0, stranger), sdkerrors.ErrUnauthorized},
+		{"set licensing in auction", NewMsgSetProductLicensing("rare1", true, 0, owner), types.ErrAuctionAlreadyExists},
+		{"set licensing of subscription", NewMsgSetProductLicensing("magazine1", true, 0, owner), types.ErrSubscriptionProduct},
+
+		{"unknown message", bank.NewMsgSend(owner, buyer, price), sdkerrors.ErrUnknownRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			input, handler := setupHandlerTest(t)
+
+			_, err := handler(input.Ctx, tc.msg)
+			if tc.expErr != nil {
+				require.True(t, errors.Is(err, tc.expErr), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestHandleMsgBuyName(t *testing.T) {
+	input, handler := setupHandlerTest(t)
+	ctx, k := input.Ctx, input.Keeper
+	supplyBefore := input.SupplyKeeper.GetSupply(ctx).GetTotal()
+
+	// the bid for a name nobody owns yet is burned
+	_, err := handler(ctx, NewMsgBuyName("bob", price, buyer))
+	require.NoError(t, err)
+	require.Equal(t, buyer, k.GetOwner(ctx, "bob"))
+	require.Equal(t, keeper.InitCoins.Sub(price), input.BankKeeper.GetCoins(ctx, buyer))
+	require.Equal(t, supplyBefore.Sub(price), input.SupplyKeeper.GetSupply(ctx).GetTotal())
+
+	// the bid for an owned name goes to its owner, who keeps the storefront products
+	bid := price.Add(price...)
+	_, err = handler(ctx, NewMsgBuyName("alice", bid, buyer))
+	require.NoError(t, err)
+	require.Equal(t, buyer, k.GetOwner(ctx, "alice"))
+	require.Equal(t, bid, k.GetPrice(ctx, "alice"))
+	require.Equal(t, keeper.InitCoins.Add(bid...), input.BankKeeper.GetCoins(ctx, owner))
+
+	book := k.GetProduct(ctx, "Product-book1")
+	require.Equal(t, owner, book.Owner)
+	require.Empty(t, book.Storefront)
+}
+
+func TestHandleMsgBuyProduct(t *testing.T) {
+	input, handler := setupHandlerTest(t)
+	ctx, k := input.Ctx, input.Keeper
+
+	_, err := handler(ctx, NewMsgBuyProduct("book1", "", "HALF", 0, buyer))
+	require.NoError(t, err)
+
+	paid := sdk.NewCoins(sdk.NewInt64Coin("nametoken", 5))
+	require.Equal(t, keeper.InitCoins.Sub(paid), input.BankKeeper.GetCoins(ctx, buyer))
+	require.Equal(t, keeper.InitCoins.Add(paid...), input.BankKeeper.GetCoins(ctx, owner))
+
+	book := k.GetProduct(ctx, "Product-book1")
+	require.Equal(t, buyer, book.Owner)
+	require.False(t, book.Listed)
+	require.Empty(t, book.Storefront)
+
+	purchase, found := k.GetPurchase(ctx, "book1", buyer)
+	require.True(t, found)
+	require.Equal(t, paid, purchase.Price)
+}
+
+func TestHandleMsgBuyProducts(t *testing.T) {
+	input, handler := setupHandlerTest(t)
+	ctx, k := input.Ctx, input.Keeper
+
+	// a cart with a failing item leaves every product and balance untouched
+	items := []types.CartItem{types.NewCartItem("book1", "", "", 0), types.NewCartItem("album1", "", "", 0)}
+	_, err := handler(ctx, types.NewMsgBuyProducts(items, buyer))
+	require.True(t, errors.Is(err, types.ErrProductNotForSale))
+	require.Equal(t, owner, k.GetProduct(ctx, "Product-book1").Owner)
+	require.Equal(t, keeper.InitCoins, input.BankKeeper.GetCoins(ctx, buyer))
+
+	items = []types.CartItem{types.NewCartItem("book1", "", "", 0), types.NewCartItem("ebook1", "", "", 0)}
+	_, err = handler(ctx, types.NewMsgBuyProducts(items, buyer))
+	require.NoError(t, err)
+	require.Equal(t, buyer, k.GetProduct(ctx, "Product-book1").Owner)
+	require.True(t, k.HasLicense(ctx, "ebook1", buyer))
+	require.Equal(t, keeper.InitCoins.Sub(price.Add(price...)), input.BankKeeper.GetCoins(ctx, buyer))
+}
+
+func TestHandleMsgPlaceBid(t *testing.T) {
+	input, handler := setupHandlerTest(t)
+	ctx, k := input.Ctx, input.Keeper
+	escrow := input.SupplyKeeper.GetModuleAddress(types.ModuleName)
+
+	_, err := handler(ctx, NewMsgPlaceBid("rare1", price, buyer))
+	require.NoError(t, err)
+	require.Equal(t, price, input.BankKeeper.GetCoins(ctx, escrow))
+
+	// an english auction needs a higher bid and refunds the outbid bidder
+	_, err = handler(ctx, NewMsgPlaceBid("rare1", price, stranger))
+	require.True(t, errors.Is(err, types.ErrBidTooLow))
+
+	bid := price.Add(price...)
+	_, err = handler(ctx, NewMsgPlaceBid("rare1", bid, stranger))
+	require.NoError(t, err)
+	require.Equal(t, bid, input.BankKeeper.GetCoins(ctx, escrow))
+	require.Equal(t, keeper.InitCoins, input.BankKeeper.GetCoins(ctx, buyer))
+
+	highest, ok := k.GetAuction(ctx, "rare1").HighestBid()
+	require.True(t, ok)
+	require.Equal(t, stranger, highest.Bidder)
+}
@@ -0,0 +1,52 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
+
+// emitNameChange emits the change of the record of a name. The owner is the one after
+// the change, empty when the name was deleted, and the previous owner is added when it
+// differs, so that both parties of a transfer see it.
+func emitNameChange(ctx sdk.Context, name string, owner, previousOwner sdk.AccAddress) {
+	attributes := []sdk.Attribute{
+		sdk.NewAttribute(sdk.AttributeKeyModule, types.AttributeValueCategory),
+		sdk.NewAttribute(types.AttributeKeyName, name),
+	}
+	attributes = append(attributes, ownerAttributes(owner, previousOwner)...)
+
+	ctx.EventManager().EmitEvent(sdk.NewEvent(types.EventTypeNameChange, attributes...))
+}
+
+// emitProductChange emits the change of the record of a product or of its auction. The
+// storefront is the name the product is published under after the change, or before it
+// when the product was unpublished.
+func emitProductChange(ctx sdk.Context, productID string, owner, previousOwner sdk.AccAddress,
+	storefront, previousStorefront string) {
+	attributes := []sdk.Attribute{
+		sdk.NewAttribute(sdk.AttributeKeyModule, types.AttributeValueCategory),
+		sdk.NewAttribute(types.AttributeKeyProductID, productID),
+	}
+	attributes = append(attributes, ownerAttributes(owner, previousOwner)...)
+
+	if storefront == "" {
+		storefront = previousStorefront
+	}
+	if storefront != "" {
+		attributes = append(attributes, sdk.NewAttribute(types.AttributeKeyStorefront, storefront))
+	}
+
+	ctx.EventManager().EmitEvent(sdk.NewEvent(types.EventTypeProductChange, attributes...))
+}
+
+func ownerAttributes(owner, previousOwner sdk.AccAddress) []sdk.Attribute {
+	var attributes []sdk.Attribute
+	if !owner.Empty() {
+		attributes = append(attributes, sdk.NewAttribute(types.AttributeKeyOwner, owner.String()))
+	}
+	if !previousOwner.Empty() && !previousOwner.Equals(owner) {
+		attributes = append(attributes, sdk.NewAttribute(types.AttributeKeyPreviousOwner, previousOwner.String()))
+	}
+	return attributes
+}
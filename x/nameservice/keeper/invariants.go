@@ -0,0 +1,197 @@
+package keeper
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
+
+// RegisterInvariants registers all nameservice invariants
+func RegisterInvariants(ir sdk.InvariantRegistry, k Keeper) {
+	ir.RegisterRoute(types.ModuleName, "whois-records", WhoisRecordsInvariant(k))
+	ir.RegisterRoute(types.ModuleName, "product-records", ProductRecordsInvariant(k))
+	ir.RegisterRoute(types.ModuleName, "product-indexes", ProductIndexesInvariant(k))
+	ir.RegisterRoute(types.ModuleName, "auction-escrow", AuctionEscrowInvariant(k))
+}
+
+// AllInvariants runs all invariants of the nameservice module
+func AllInvariants(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		for _, invariant := range []sdk.Invariant{
+			WhoisRecordsInvariant(k),
+			ProductRecordsInvariant(k),
+			ProductIndexesInvariant(k),
+			AuctionEscrowInvariant(k),
+		} {
+			if res, stop := invariant(ctx); stop {
+				return res, stop
+			}
+		}
+		return "", false
+	}
+}
+
+// WhoisRecordsInvariant checks that every name record has an owner and a valid price
+func WhoisRecordsInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		var msg string
+		var broken int
+
+		iterator := k.GetNamesIterator(ctx)
+		defer iterator.Close()
+
+		for ; iterator.Valid(); iterator.Next() {
+			name := strings.TrimPrefix(string(iterator.Key()), types.WhoisPrefix)
+
+			var whois types.Whois
+			if err := k.cdc.UnmarshalBinaryBare(iterator.Value(), &whois); err != nil {
+				broken++
+				msg += fmt.Sprintf("\tname %s does not decode: %v\n", name, err)
+				continue
+			}
+			if whois.Owner.Empty() {
+				broken++
+				msg += fmt.Sprintf("\tname %s has no owner\n", name)
+			}
+			if !whois.Price.IsValid() {
+				broken++
+				msg += fmt.Sprintf("\tname %s has invalid price %s\n", name, whois.Price)
+			}
+		}
+
+		return sdk.FormatInvariant(types.ModuleName, "whois-records",
+			fmt.Sprintf("%d invalid name records found\n%s", broken, msg)), broken != 0
+	}
+}
+
+// ProductRecordsInvariant checks that every product key holds a product with the
+// product ID of its key and an owner
+func ProductRecordsInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		var msg string
+		var broken int
+
+		iterator := k.GetProductsIterator(ctx)
+		defer iterator.Close()
+
+		for ; iterator.Valid(); iterator.Next() {
+			productID := strings.TrimPrefix(string(iterator.Key()), types.ProductPrefix)
+
+			var product types.Product
+			if err := k.cdc.UnmarshalBinaryBare(iterator.Value(), &product); err != nil {
+				broken++
+				msg += fmt.Sprintf("\tproduct %s does not decode: %v\n", productID, err)
+				continue
+			}
+			if product.ProductID != productID {
+				broken++
+				msg += fmt.Sprintf("\tproduct key %s holds product %s\n", productID, product.ProductID)
+			}
+			if product.Owner.Empty() {
+				broken++
+				msg += fmt.Sprintf("\tproduct %s has no owner\n", productID)
+			}
+		}
+
+		return sdk.FormatInvariant(types.ModuleName, "product-records",
+			fmt.Sprintf("%d invalid product records found\n%s", broken, msg)), broken != 0
+	}
+}
+
+// ProductIndexesInvariant checks that the category, tag and storefront indexes
+// hold exactly the entries of the stored products
+func ProductIndexesInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		var msg string
+		var broken int
+
+		store := ctx.KVStore(k.storeKey)
+
+		// every product is indexed under its category, tags and storefront
+		products := k.GetProductsIterator(ctx)
+		for ; products.Valid(); products.Next() {
+			var product types.Product
+			k.cdc.MustUnmarshalBinaryBare(products.Value(), &product)
+
+			if product.Category != "" && !store.Has(append(types.CategoryIndexPrefix(product.Category), product.ProductID...)) {
+				broken++
+				msg += fmt.Sprintf("\tproduct %s missing from category %s\n", product.ProductID, product.Category)
+			}
+			for _, tag := range product.Tags {
+				if !store.Has(append(types.TagIndexPrefix(tag), product.ProductID...)) {
+					broken++
+					msg += fmt.Sprintf("\tproduct %s missing from tag %s\n", product.ProductID, tag)
+				}
+			}
+			if product.Storefront != "" && !store.Has(append(types.StorefrontIndexPrefix(product.Storefront), product.ProductID...)) {
+				broken++
+				msg += fmt.Sprintf("\tproduct %s missing from storefront %s\n", product.ProductID, product.Storefront)
+			}
+		}
+		products.Close()
+
+		// every index entry points to a product that carries the indexed value
+		indexes := []struct {
+			prefix  string
+			matches func(product types.Product, value string) bool
+		}{
+			{types.CategoryPrefix, func(product types.Product, value string) bool { return product.Category == value }},
+			{types.TagPrefix, func(product types.Product, value string) bool { return product.HasTag(value) }},
+			{types.StorefrontPrefix, func(product types.Product, value string) bool { return product.Storefront == value }},
+		}
+
+		for _, index := range indexes {
+			iterator := sdk.KVStorePrefixIterator(store, []byte(index.prefix))
+			for ; iterator.Valid(); iterator.Next() {
+				entry := strings.TrimPrefix(string(iterator.Key()), index.prefix)
+
+				// neither indexed values nor product IDs contain the separator
+				parts := strings.SplitN(entry, types.IndexSeparator, 2)
+				if len(parts) != 2 {
+					broken++
+					msg += fmt.Sprintf("\tmalformed index key %s%s\n", index.prefix, entry)
+					continue
+				}
+
+				value, productID := parts[0], parts[1]
+				key := types.ProductPrefix + productID
+				if !k.IsProductPresent(ctx, key) || !index.matches(k.GetProduct(ctx, key), value) {
+					broken++
+					msg += fmt.Sprintf("\tstale index entry %s%s\n", index.prefix, entry)
+				}
+			}
+			iterator.Close()
+		}
+
+		return sdk.FormatInvariant(types.ModuleName, "product-indexes",
+			fmt.Sprintf("%d inconsistent index entries found\n%s", broken, msg)), broken != 0
+	}
+}
+
+// AuctionEscrowInvariant checks that the module account holds exactly the
+// highest bids of the outstanding auctions
+func AuctionEscrowInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		var escrowed sdk.Coins
+
+		iterator := k.GetAuctionsIterator(ctx)
+		for ; iterator.Valid(); iterator.Next() {
+			var auction types.Auction
+			k.cdc.MustUnmarshalBinaryBare(iterator.Value(), &auction)
+			if highest, ok := auction.HighestBid(); ok {
+				escrowed = escrowed.Add(highest.Amount...)
+			}
+		}
+		iterator.Close()
+
+		balance := k.CoinKeeper.GetCoins(ctx, k.SupplyKeeper.GetModuleAddress(types.ModuleName))
+		// Coins.IsEqual panics on differing denominations
+		broken := !balance.IsAllGTE(escrowed) || !escrowed.IsAllGTE(balance)
+
+		return sdk.FormatInvariant(types.ModuleName, "auction-escrow",
+			fmt.Sprintf("\tsum of highest bids: %s\n\tmodule account balance: %s\n", escrowed, balance)), broken
+	}
+}
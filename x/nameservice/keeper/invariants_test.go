@@ -0,0 +1,93 @@
+package keeper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
+
+func TestInvariants(t *testing.T) {
+	price := sdk.NewCoins(sdk.NewInt64Coin("nametoken", 10))
+	book := types.Product{ProductID: "book1", Owner: Addrs[0], Price: price, Category: "books",
+		Tags: []string{"new"}, Storefront: "alice"}
+
+	tests := []struct {
+		name      string
+		invariant func(Keeper) sdk.Invariant
+		corrupt   func(ctx sdk.Context, k Keeper, store sdk.KVStore)
+	}{
+		{"name without owner", WhoisRecordsInvariant, func(ctx sdk.Context, k Keeper, store sdk.KVStore) {
+			store.Set(types.WhoisKey("bob"), k.cdc.MustMarshalBinaryBare(types.Whois{Value: "8.8.8.8", Price: price}))
+		}},
+		{"name with invalid price", WhoisRecordsInvariant, func(ctx sdk.Context, k Keeper, store sdk.KVStore) {
+			invalid := sdk.Coins{sdk.Coin{Denom: "nametoken", Amount: sdk.NewInt(-1)}}
+			store.Set(types.WhoisKey("bob"), k.cdc.MustMarshalBinaryBare(types.Whois{Owner: Addrs[0], Price: invalid}))
+		}},
+		{"undecodable name", WhoisRecordsInvariant, func(ctx sdk.Context, k Keeper, store sdk.KVStore) {
+			store.Set(types.WhoisKey("bob"), []byte("garbage"))
+		}},
+		{"product key holding another product", ProductRecordsInvariant, func(ctx sdk.Context, k Keeper, store sdk.KVStore) {
+			store.Set([]byte(types.ProductPrefix+"book2"), k.cdc.MustMarshalBinaryBare(book))
+		}},
+		{"product without owner", ProductRecordsInvariant, func(ctx sdk.Context, k Keeper, store sdk.KVStore) {
+			album := types.Product{ProductID: "album1", Price: price}
+			store.Set([]byte(types.ProductPrefix+"album1"), k.cdc.MustMarshalBinaryBare(album))
+		}},
+		{"product missing from its category", ProductIndexesInvariant, func(ctx sdk.Context, k Keeper, store sdk.KVStore) {
+			store.Delete(append(types.CategoryIndexPrefix("books"), "book1"...))
+		}},
+		{"product missing from its storefront", ProductIndexesInvariant, func(ctx sdk.Context, k Keeper, store sdk.KVStore) {
+			store.Delete(append(types.StorefrontIndexPrefix("alice"), "book1"...))
+		}},
+		{"stale index entry", ProductIndexesInvariant, func(ctx sdk.Context, k Keeper, store sdk.KVStore) {
+			store.Set(append(types.TagIndexPrefix("used"), "book1"...), []byte{})
+		}},
+		{"index entry of a missing product", ProductIndexesInvariant, func(ctx sdk.Context, k Keeper, store sdk.KVStore) {
+			store.Set(append(types.CategoryIndexPrefix("books"), "book9"...), []byte{})
+		}},
+		{"malformed index key", ProductIndexesInvariant, func(ctx sdk.Context, k Keeper, store sdk.KVStore) {
+			store.Set([]byte(types.CategoryPrefix+"books"), []byte{})
+		}},
+		{"bid missing from escrow", AuctionEscrowInvariant, func(ctx sdk.Context, k Keeper, store sdk.KVStore) {
+			auction := k.GetAuction(ctx, "book1")
+			auction.Bids = append(auction.Bids, types.Bid{Bidder: Addrs[2], Amount: price.Add(price...), Height: 1})
+			k.SetAuction(ctx, auction)
+		}},
+		{"escrow holding more than the bids", AuctionEscrowInvariant, func(ctx sdk.Context, k Keeper, store sdk.KVStore) {
+			err := k.SupplyKeeper.SendCoinsFromAccountToModule(ctx, Addrs[2], types.ModuleName, price)
+			require.NoError(t, err)
+		}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			input := CreateTestInput(t)
+			ctx, k := input.Ctx, input.Keeper
+
+			// a consistent state: a name, a product published under it and an auction
+			// of the product whose highest bid is in escrow
+			k.SetWhois(ctx, "alice", types.Whois{Value: "8.8.8.8", Owner: Addrs[0], Price: price})
+			k.SetProduct(ctx, types.ProductPrefix+book.ProductID, book)
+			auction := types.NewAuction("book1", Addrs[0], types.AuctionEnglish, price, nil, nil, 1, 10)
+			auction.Bids = []types.Bid{{Bidder: Addrs[1], Amount: price, Height: 1}}
+			k.SetAuction(ctx, auction)
+			require.NoError(t, k.SupplyKeeper.SendCoinsFromAccountToModule(ctx, Addrs[1], types.ModuleName, price))
+
+			msg, broken := AllInvariants(k)(ctx)
+			require.False(t, broken, msg)
+			_, broken = tc.invariant(k)(ctx)
+			require.False(t, broken)
+
+			tc.corrupt(ctx, k, ctx.KVStore(k.storeKey))
+
+			msg, broken = tc.invariant(k)(ctx)
+			require.True(t, broken, msg)
+			_, broken = AllInvariants(k)(ctx)
+			require.True(t, broken)
+		})
+	}
+}
@@ -1,14 +1,21 @@
 package keeper
 
 import (
+	"fmt"
+
+	"github.com/tendermint/tendermint/libs/log"
+
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
 )
 
 // Keeper maintains the link to storage and exposes getter/setter methods for the various parts of the state machine
 type Keeper struct {
-	CoinKeeper types.BankKeeper
+	CoinKeeper      types.BankKeeper
+	SupplyKeeper    types.SupplyKeeper
+	PriceFeedKeeper types.PriceFeedKeeper
 
 	storeKey sdk.StoreKey // Unexposed key to access store from sdk.Context
 
@@ -16,14 +23,21 @@ type Keeper struct {
 }
 
 // NewKeeper creates new instances of the nameservice Keeper
-func NewKeeper(cdc *codec.Codec, storeKey sdk.StoreKey, coinKeeper types.BankKeeper) Keeper {
+func NewKeeper(cdc *codec.Codec, storeKey sdk.StoreKey, coinKeeper types.BankKeeper, supplyKeeper types.SupplyKeeper, priceFeedKeeper types.PriceFeedKeeper) Keeper {
 	return Keeper{
-		cdc:        cdc,
-		storeKey:   storeKey,
-		CoinKeeper: coinKeeper,
+		cdc:             cdc,
+		storeKey:        storeKey,
+		CoinKeeper:      coinKeeper,
+		SupplyKeeper:    supplyKeeper,
+		PriceFeedKeeper: priceFeedKeeper,
 	}
 }
 
+// Logger returns a module-specific logger
+func (k Keeper) Logger(ctx sdk.Context) log.Logger {
+	return ctx.Logger().With("module", fmt.Sprintf("x/%s", types.ModuleName))
+}
+
 // Gets the entire Whois metadata struct for a name
 func (k Keeper) GetWhois(ctx sdk.Context, name string) types.Whois {
 	store := ctx.KVStore(k.storeKey)
@@ -32,7 +46,7 @@ func (k Keeper) GetWhois(ctx sdk.Context, name string) types.Whois {
 		return types.NewWhois()
 	}
 
-	bz := store.Get([]byte(name))
+	bz := store.Get(types.WhoisKey(name))
 
 	var whois types.Whois
 
@@ -49,13 +63,20 @@ func (k Keeper) SetWhois(ctx sdk.Context, name string, whois types.Whois) {
 
 	store := ctx.KVStore(k.storeKey)
 
-	store.Set([]byte(name), k.cdc.MustMarshalBinaryBare(whois))
+	previous := k.GetWhois(ctx, name)
+	store.Set(types.WhoisKey(name), k.cdc.MustMarshalBinaryBare(whois))
+	emitNameChange(ctx, name, whois.Owner, previous.Owner)
 }
 
 // Deletes the entire Whois metadata struct for a name
 func (k Keeper) DeleteWhois(ctx sdk.Context, name string) {
 	store := ctx.KVStore(k.storeKey)
-	store.Delete([]byte(name))
+
+	if k.IsNamePresent(ctx, name) {
+		emitNameChange(ctx, name, nil, k.GetOwner(ctx, name))
+	}
+
+	store.Delete(types.WhoisKey(name))
 }
 
 // ResolveName - returns the string that the name resolves to
@@ -99,16 +120,16 @@ func (k Keeper) SetPrice(ctx sdk.Context, name string, price sdk.Coins) {
 	k.SetWhois(ctx, name, whois)
 }
 
-// Get an iterator over all names in which the keys are the names and the values are the whois
+// Get an iterator over all names in which the keys are the prefixed names and the values are the whois
 func (k Keeper) GetNamesIterator(ctx sdk.Context) sdk.Iterator {
 	store := ctx.KVStore(k.storeKey)
-	return sdk.KVStorePrefixIterator(store, nil)
+	return sdk.KVStorePrefixIterator(store, []byte(types.WhoisPrefix))
 }
 
 // Check if the name is present in the store or not
 func (k Keeper) IsNamePresent(ctx sdk.Context, name string) bool {
 	store := ctx.KVStore(k.storeKey)
-	return store.Has([]byte(name))
+	return store.Has(types.WhoisKey(name))
 }
 
 func (k Keeper) GetProduct(ctx sdk.Context, key string) types.Product {
@@ -127,6 +148,7 @@ func (k Keeper) GetProduct(ctx sdk.Context, key string) types.Product {
 	return product
 }
 
+// SetProduct stores the product and keeps its category and tag indexes up to date
 func (k Keeper) SetProduct(ctx sdk.Context, key string, product types.Product) {
 	if product.Owner.Empty() {
 		return
@@ -134,14 +156,65 @@ func (k Keeper) SetProduct(ctx sdk.Context, key string, product types.Product) {
 
 	store := ctx.KVStore(k.storeKey)
 
+	previous := types.NewProduct()
+	if k.IsProductPresent(ctx, key) {
+		previous = k.GetProduct(ctx, key)
+		k.removeProductIndexes(ctx, previous)
+	}
+
 	store.Set([]byte(key), k.cdc.MustMarshalBinaryBare(product))
+	k.setProductIndexes(ctx, product)
+	emitProductChange(ctx, product.ProductID, product.Owner, previous.Owner, product.Storefront, previous.Storefront)
 }
 
+// DeleteProduct removes the product along with its category and tag indexes, its
+// history, its licenses, its subscriptions, its purchases and its reviews, so a product
+// recreated under the same ID starts clean
 func (k Keeper) DeleteProduct(ctx sdk.Context, key string) {
 	store := ctx.KVStore(k.storeKey)
+
+	if k.IsProductPresent(ctx, key) {
+		product := k.GetProduct(ctx, key)
+		k.removeProductIndexes(ctx, product)
+		k.deleteProductHistory(ctx, product.ProductID)
+		k.deleteLicenses(ctx, product.ProductID)
+		k.closeSubscriptions(ctx, product.ProductID)
+		k.deletePurchases(ctx, product.ProductID)
+		k.deleteReviews(ctx, product.ProductID)
+		emitProductChange(ctx, product.ProductID, nil, product.Owner, "", product.Storefront)
+	}
+
 	store.Delete([]byte(key))
 }
 
+func (k Keeper) setProductIndexes(ctx sdk.Context, product types.Product) {
+	store := ctx.KVStore(k.storeKey)
+
+	if product.Category != "" {
+		store.Set(append(types.CategoryIndexPrefix(product.Category), product.ProductID...), []byte{})
+	}
+	for _, tag := range product.Tags {
+		store.Set(append(types.TagIndexPrefix(tag), product.ProductID...), []byte{})
+	}
+	if product.Storefront != "" {
+		store.Set(append(types.StorefrontIndexPrefix(product.Storefront), product.ProductID...), []byte{})
+	}
+}
+
+func (k Keeper) removeProductIndexes(ctx sdk.Context, product types.Product) {
+	store := ctx.KVStore(k.storeKey)
+
+	if product.Category != "" {
+		store.Delete(append(types.CategoryIndexPrefix(product.Category), product.ProductID...))
+	}
+	for _, tag := range product.Tags {
+		store.Delete(append(types.TagIndexPrefix(tag), product.ProductID...))
+	}
+	if product.Storefront != "" {
+		store.Delete(append(types.StorefrontIndexPrefix(product.Storefront), product.ProductID...))
+	}
+}
+
 func (k Keeper) IsProductPresent(ctx sdk.Context, key string) bool {
 	store := ctx.KVStore(k.storeKey)
 	return store.Has([]byte(key))
@@ -149,5 +222,584 @@ func (k Keeper) IsProductPresent(ctx sdk.Context, key string) bool {
 
 func (k Keeper) GetProductsIterator(ctx sdk.Context) sdk.Iterator {
 	store := ctx.KVStore(k.storeKey)
-	return sdk.KVStorePrefixIterator(store, nil)
+	return sdk.KVStorePrefixIterator(store, []byte(types.ProductPrefix))
+}
+
+// GetProductPrice returns what a buyer paying in the given denomination is charged.
+// An empty denomination charges the listed price, otherwise the matching accepted price
+// is charged, or for pegged products the reference price converted at the feed rate,
+// which must have been posted within the maximum price age of the feed.
+func (k Keeper) GetProductPrice(ctx sdk.Context, product types.Product, denom string) (sdk.Coins, error) {
+	if product.IsPegged() {
+		if denom == "" {
+			return nil, sdkerrors.Wrap(types.ErrDenomNotAccepted, "a denomination is required for pegged products")
+		}
+
+		reference := product.ReferencePrice[0]
+		rate, height, ok := k.PriceFeedKeeper.GetRate(ctx, denom, reference.Denom)
+		if !ok || !rate.IsPositive() {
+			return nil, sdkerrors.Wrapf(types.ErrPriceUnavailable, "%s/%s", denom, reference.Denom)
+		}
+		if ctx.BlockHeight()-height > k.PriceFeedKeeper.MaxPriceAge(ctx) {
+			return nil, sdkerrors.Wrapf(types.ErrPriceStale, "%s/%s posted at height %d", denom, reference.Denom, height)
+		}
+
+		amount := reference.Amount.Quo(rate).Ceil().TruncateInt()
+		return sdk.NewCoins(sdk.NewCoin(denom, amount)), nil
+	}
+
+	if denom == "" {
+		return product.Price, nil
+	}
+	if amount := product.AcceptedPrices.AmountOf(denom); amount.IsPositive() {
+		return sdk.NewCoins(sdk.NewCoin(denom, amount)), nil
+	}
+	if len(product.Price) == 1 && product.Price[0].Denom == denom {
+		return product.Price, nil
+	}
+	return nil, sdkerrors.Wrap(types.ErrDenomNotAccepted, denom)
+}
+
+// GetCategoryProductIDs returns the IDs of all products in a category
+func (k Keeper) GetCategoryProductIDs(ctx sdk.Context, category string) []string {
+	return k.getIndexedProductIDs(ctx, types.CategoryIndexPrefix(category))
+}
+
+// GetTagProductIDs returns the IDs of all products labelled with a tag
+func (k Keeper) GetTagProductIDs(ctx sdk.Context, tag string) []string {
+	return k.getIndexedProductIDs(ctx, types.TagIndexPrefix(tag))
+}
+
+// GetStorefrontProductIDs returns the IDs of all products published under a name
+func (k Keeper) GetStorefrontProductIDs(ctx sdk.Context, name string) []string {
+	return k.getIndexedProductIDs(ctx, types.StorefrontIndexPrefix(name))
+}
+
+func (k Keeper) getIndexedProductIDs(ctx sdk.Context, prefix []byte) []string {
+	store := ctx.KVStore(k.storeKey)
+
+	iterator := sdk.KVStorePrefixIterator(store, prefix)
+	defer iterator.Close()
+
+	var productIDs []string
+	for ; iterator.Valid(); iterator.Next() {
+		productIDs = append(productIDs, string(iterator.Key()[len(prefix):]))
+	}
+	return productIDs
+}
+
+func (k Keeper) GetAuction(ctx sdk.Context, productID string) types.Auction {
+	store := ctx.KVStore(k.storeKey)
+
+	if !k.IsAuctionPresent(ctx, productID) {
+		return types.Auction{}
+	}
+
+	bz := store.Get([]byte(types.AuctionPrefix + productID))
+
+	var auction types.Auction
+
+	k.cdc.MustUnmarshalBinaryBare(bz, &auction)
+
+	return auction
+}
+
+func (k Keeper) SetAuction(ctx sdk.Context, auction types.Auction) {
+	if auction.Seller.Empty() {
+		return
+	}
+
+	store := ctx.KVStore(k.storeKey)
+
+	store.Set([]byte(types.AuctionPrefix+auction.ProductID), k.cdc.MustMarshalBinaryBare(auction))
+	emitProductChange(ctx, auction.ProductID, auction.Seller, auction.Seller, "", "")
+}
+
+func (k Keeper) DeleteAuction(ctx sdk.Context, productID string) {
+	store := ctx.KVStore(k.storeKey)
+
+	if k.IsAuctionPresent(ctx, productID) {
+		seller := k.GetAuction(ctx, productID).Seller
+		emitProductChange(ctx, productID, seller, seller, "", "")
+	}
+
+	store.Delete([]byte(types.AuctionPrefix + productID))
+}
+
+func (k Keeper) IsAuctionPresent(ctx sdk.Context, productID string) bool {
+	store := ctx.KVStore(k.storeKey)
+	return store.Has([]byte(types.AuctionPrefix + productID))
+}
+
+// Get an iterator over all auctions in which the values are the auctions
+func (k Keeper) GetAuctionsIterator(ctx sdk.Context) sdk.Iterator {
+	store := ctx.KVStore(k.storeKey)
+	return sdk.KVStorePrefixIterator(store, []byte(types.AuctionPrefix))
+}
+
+// SetPurchase records that a buyer acquired a product
+func (k Keeper) SetPurchase(ctx sdk.Context, purchase types.Purchase) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.PurchaseKey(purchase.ProductID, purchase.Buyer), k.cdc.MustMarshalBinaryBare(purchase))
+}
+
+// GetPurchase returns the latest purchase of a product by a buyer
+func (k Keeper) GetPurchase(ctx sdk.Context, productID string, buyer sdk.AccAddress) (types.Purchase, bool) {
+	store := ctx.KVStore(k.storeKey)
+
+	bz := store.Get(types.PurchaseKey(productID, buyer))
+	if bz == nil {
+		return types.Purchase{}, false
+	}
+
+	var purchase types.Purchase
+	k.cdc.MustUnmarshalBinaryBare(bz, &purchase)
+	return purchase, true
+}
+
+// GetPurchases returns all the purchases of a product
+func (k Keeper) GetPurchases(ctx sdk.Context, productID string) []types.Purchase {
+	store := ctx.KVStore(k.storeKey)
+
+	iterator := sdk.KVStorePrefixIterator(store, types.PurchasesPrefix(productID))
+	defer iterator.Close()
+
+	purchases := []types.Purchase{}
+	for ; iterator.Valid(); iterator.Next() {
+		var purchase types.Purchase
+		k.cdc.MustUnmarshalBinaryBare(iterator.Value(), &purchase)
+		// product IDs may contain the separator, so the prefix can match other products
+		if purchase.ProductID == productID {
+			purchases = append(purchases, purchase)
+		}
+	}
+	return purchases
+}
+
+func (k Keeper) deletePurchases(ctx sdk.Context, productID string) {
+	store := ctx.KVStore(k.storeKey)
+
+	for _, purchase := range k.GetPurchases(ctx, productID) {
+		store.Delete(types.PurchaseKey(productID, purchase.Buyer))
+	}
+}
+
+// GetPurchasesIterator returns an iterator over all recorded purchases
+func (k Keeper) GetPurchasesIterator(ctx sdk.Context) sdk.Iterator {
+	store := ctx.KVStore(k.storeKey)
+	return sdk.KVStorePrefixIterator(store, []byte(types.PurchasePrefix))
+}
+
+// SetReview stores a review and adds its rating to the product and seller ratings
+func (k Keeper) SetReview(ctx sdk.Context, review types.Review) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.ReviewKey(review.ProductID, review.Reviewer), k.cdc.MustMarshalBinaryBare(review))
+
+	k.setRating(ctx, []byte(types.ProductRatingPrefix+review.ProductID),
+		k.GetProductRating(ctx, review.ProductID).Add(review.Rating))
+	k.setRating(ctx, []byte(types.SellerRatingPrefix+review.Seller.String()),
+		k.GetSellerRating(ctx, review.Seller).Add(review.Rating))
+}
+
+// HasReview returns whether a reviewer already reviewed a product
+func (k Keeper) HasReview(ctx sdk.Context, productID string, reviewer sdk.AccAddress) bool {
+	store := ctx.KVStore(k.storeKey)
+	return store.Has(types.ReviewKey(productID, reviewer))
+}
+
+// GetReviews returns all the reviews of a product
+func (k Keeper) GetReviews(ctx sdk.Context, productID string) []types.Review {
+	store := ctx.KVStore(k.storeKey)
+
+	iterator := sdk.KVStorePrefixIterator(store, types.ReviewsPrefix(productID))
+	defer iterator.Close()
+
+	reviews := []types.Review{}
+	for ; iterator.Valid(); iterator.Next() {
+		var review types.Review
+		k.cdc.MustUnmarshalBinaryBare(iterator.Value(), &review)
+		// product IDs may contain the separator, so the prefix can match other products
+		if review.ProductID == productID {
+			reviews = append(reviews, review)
+		}
+	}
+	return reviews
+}
+
+// deleteReviews removes the reviews and the rating of a product. The reviews are taken
+// out of the ratings of their sellers too, which genesis rebuilds from the stored reviews.
+func (k Keeper) deleteReviews(ctx sdk.Context, productID string) {
+	store := ctx.KVStore(k.storeKey)
+
+	for _, review := range k.GetReviews(ctx, productID) {
+		store.Delete(types.ReviewKey(productID, review.Reviewer))
+		k.setRating(ctx, []byte(types.SellerRatingPrefix+review.Seller.String()),
+			k.GetSellerRating(ctx, review.Seller).Remove(review.Rating))
+	}
+	store.Delete([]byte(types.ProductRatingPrefix + productID))
+}
+
+// GetReviewsIterator returns an iterator over the reviews of all products
+func (k Keeper) GetReviewsIterator(ctx sdk.Context) sdk.Iterator {
+	store := ctx.KVStore(k.storeKey)
+	return sdk.KVStorePrefixIterator(store, []byte(types.ReviewPrefix))
+}
+
+// GetProductRating returns the aggregated rating of a product
+func (k Keeper) GetProductRating(ctx sdk.Context, productID string) types.Rating {
+	return k.getRating(ctx, []byte(types.ProductRatingPrefix+productID))
+}
+
+// GetSellerRating returns the aggregated rating of a seller
+func (k Keeper) GetSellerRating(ctx sdk.Context, seller sdk.AccAddress) types.Rating {
+	return k.getRating(ctx, []byte(types.SellerRatingPrefix+seller.String()))
+}
+
+func (k Keeper) getRating(ctx sdk.Context, key []byte) types.Rating {
+	store := ctx.KVStore(k.storeKey)
+
+	var rating types.Rating
+	if bz := store.Get(key); bz != nil {
+		k.cdc.MustUnmarshalBinaryBare(bz, &rating)
+	}
+	return rating
+}
+
+// setRating stores a rating, a rating without reviews is deleted as it encodes to nothing
+func (k Keeper) setRating(ctx sdk.Context, key []byte, rating types.Rating) {
+	store := ctx.KVStore(k.storeKey)
+	if rating.Count == 0 {
+		store.Delete(key)
+		return
+	}
+	store.Set(key, k.cdc.MustMarshalBinaryBare(rating))
+}
+
+// GetCoupon returns the coupon stored under a code hash and whether it exists
+func (k Keeper) GetCoupon(ctx sdk.Context, codeHash string) (types.Coupon, bool) {
+	store := ctx.KVStore(k.storeKey)
+
+	bz := store.Get(types.CouponKey(codeHash))
+	if bz == nil {
+		return types.Coupon{}, false
+	}
+
+	var coupon types.Coupon
+	k.cdc.MustUnmarshalBinaryBare(bz, &coupon)
+	return coupon, true
+}
+
+// SetCoupon stores a coupon under its code hash
+func (k Keeper) SetCoupon(ctx sdk.Context, coupon types.Coupon) {
+	if coupon.Issuer.Empty() {
+		return
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.CouponKey(coupon.CodeHash), k.cdc.MustMarshalBinaryBare(coupon))
+}
+
+// DeleteCoupon removes a coupon along with the commitments to its code
+func (k Keeper) DeleteCoupon(ctx sdk.Context, codeHash string) {
+	store := ctx.KVStore(k.storeKey)
+	store.Delete(types.CouponKey(codeHash))
+	k.deleteCouponCommitments(ctx, codeHash)
+}
+
+// GetCouponsIterator returns an iterator over all coupons
+func (k Keeper) GetCouponsIterator(ctx sdk.Context) sdk.Iterator {
+	store := ctx.KVStore(k.storeKey)
+	return sdk.KVStorePrefixIterator(store, []byte(types.CouponPrefix))
+}
+
+// GetCouponCommitment returns a commitment to the code of a coupon and whether it exists
+func (k Keeper) GetCouponCommitment(ctx sdk.Context, codeHash, commitment string) (types.CouponCommitment, bool) {
+	store := ctx.KVStore(k.storeKey)
+
+	bz := store.Get(types.CouponCommitmentKey(codeHash, commitment))
+	if bz == nil {
+		return types.CouponCommitment{}, false
+	}
+
+	var couponCommitment types.CouponCommitment
+	k.cdc.MustUnmarshalBinaryBare(bz, &couponCommitment)
+	return couponCommitment, true
+}
+
+// SetCouponCommitment stores a commitment to the code of a coupon
+func (k Keeper) SetCouponCommitment(ctx sdk.Context, commitment types.CouponCommitment) {
+	if commitment.Signer.Empty() {
+		return
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.CouponCommitmentKey(commitment.CodeHash, commitment.Commitment), k.cdc.MustMarshalBinaryBare(commitment))
+}
+
+// GetCouponCommitmentsIterator returns an iterator over all coupon commitments
+func (k Keeper) GetCouponCommitmentsIterator(ctx sdk.Context) sdk.Iterator {
+	store := ctx.KVStore(k.storeKey)
+	return sdk.KVStorePrefixIterator(store, []byte(types.CouponCommitmentPrefix))
+}
+
+// deleteCouponCommitments removes all the commitments to the code of a coupon
+func (k Keeper) deleteCouponCommitments(ctx sdk.Context, codeHash string) {
+	store := ctx.KVStore(k.storeKey)
+
+	var keys [][]byte
+	iterator := sdk.KVStorePrefixIterator(store, types.CouponCommitmentsPrefix(codeHash))
+	for ; iterator.Valid(); iterator.Next() {
+		keys = append(keys, iterator.Key())
+	}
+	iterator.Close()
+
+	for _, key := range keys {
+		store.Delete(key)
+	}
+}
+
+// GetRedeemableCoupon returns the coupon with the given code if a buyer may redeem it
+// on a product. The buyer must have committed to the code in an earlier block, which
+// keeps anyone who sees the code in a pending purchase from redeeming it first.
+func (k Keeper) GetRedeemableCoupon(ctx sdk.Context, code string, buyer sdk.AccAddress, product types.Product) (types.Coupon, error) {
+	codeHash := types.HashCouponCode(code)
+
+	coupon, found := k.GetCoupon(ctx, codeHash)
+	if !found {
+		return types.Coupon{}, types.ErrCouponDoesNotExist
+	}
+
+	commitment, found := k.GetCouponCommitment(ctx, codeHash, types.HashCouponCommitment(code, buyer))
+	if !found || commitment.Height >= ctx.BlockHeight() {
+		return types.Coupon{}, types.ErrCouponNotCommitted
+	}
+
+	if err := coupon.CanRedeem(product, ctx.BlockHeight()); err != nil {
+		return types.Coupon{}, err
+	}
+	return coupon, nil
+}
+
+// RedeemCoupon applies the coupon with the given code to the price of a product
+// bought by a buyer and records the use. It returns the discounted price.
+func (k Keeper) RedeemCoupon(ctx sdk.Context, code string, buyer sdk.AccAddress, product types.Product, price sdk.Coins) (sdk.Coins, error) {
+	coupon, err := k.GetRedeemableCoupon(ctx, code, buyer, product)
+	if err != nil {
+		return nil, err
+	}
+
+	coupon.Uses++
+	k.SetCoupon(ctx, coupon)
+
+	return coupon.Apply(price), nil
+}
+
+// GetSubscription returns the subscription of a subscriber to a product and whether it exists
+func (k Keeper) GetSubscription(ctx sdk.Context, productID string, subscriber sdk.AccAddress) (types.Subscription, bool) {
+	store := ctx.KVStore(k.storeKey)
+
+	bz := store.Get(types.SubscriptionKey(productID, subscriber))
+	if bz == nil {
+		return types.Subscription{}, false
+	}
+
+	var subscription types.Subscription
+	k.cdc.MustUnmarshalBinaryBare(bz, &subscription)
+	return subscription, true
+}
+
+// SetSubscription stores a subscription
+func (k Keeper) SetSubscription(ctx sdk.Context, subscription types.Subscription) {
+	if subscription.Subscriber.Empty() {
+		return
+	}
+
+	k.DeleteSubscription(ctx, subscription.ProductID, subscription.Subscriber)
+
+	store := ctx.KVStore(k.storeKey)
+	key := types.SubscriptionKey(subscription.ProductID, subscription.Subscriber)
+	store.Set(key, k.cdc.MustMarshalBinaryBare(subscription))
+	store.Set(types.SubscriptionDueKey(subscription.PaidUntil, subscription.ProductID, subscription.Subscriber), key)
+}
+
+// DeleteSubscription removes the subscription of a subscriber to a product
+func (k Keeper) DeleteSubscription(ctx sdk.Context, productID string, subscriber sdk.AccAddress) {
+	store := ctx.KVStore(k.storeKey)
+
+	if subscription, found := k.GetSubscription(ctx, productID, subscriber); found {
+		store.Delete(types.SubscriptionDueKey(subscription.PaidUntil, productID, subscriber))
+	}
+	store.Delete(types.SubscriptionKey(productID, subscriber))
+}
+
+// GetSubscriptionsIterator returns an iterator over all subscriptions
+func (k Keeper) GetSubscriptionsIterator(ctx sdk.Context) sdk.Iterator {
+	store := ctx.KVStore(k.storeKey)
+	return sdk.KVStorePrefixIterator(store, []byte(types.SubscriptionPrefix))
+}
+
+// GetSubscriptions returns all the subscriptions to a product
+func (k Keeper) GetSubscriptions(ctx sdk.Context, productID string) []types.Subscription {
+	store := ctx.KVStore(k.storeKey)
+
+	iterator := sdk.KVStorePrefixIterator(store, types.SubscriptionsPrefix(productID))
+	defer iterator.Close()
+
+	subscriptions := []types.Subscription{}
+	for ; iterator.Valid(); iterator.Next() {
+		var subscription types.Subscription
+		k.cdc.MustUnmarshalBinaryBare(iterator.Value(), &subscription)
+		// product IDs may contain the separator, so the prefix can match other products
+		if subscription.ProductID == productID {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	return subscriptions
+}
+
+// closeSubscriptions deletes all the subscriptions to a product, which is no longer
+// available to renew them
+func (k Keeper) closeSubscriptions(ctx sdk.Context, productID string) {
+	for _, subscription := range k.GetSubscriptions(ctx, productID) {
+		k.DeleteSubscription(ctx, productID, subscription.Subscriber)
+
+		ctx.EventManager().EmitEvent(
+			sdk.NewEvent(
+				types.EventTypeCancelSubscription,
+				sdk.NewAttribute(sdk.AttributeKeyModule, types.AttributeValueCategory),
+				sdk.NewAttribute(types.AttributeKeyProductID, productID),
+				sdk.NewAttribute(types.AttributeKeySubscriber, subscription.Subscriber.String()),
+				sdk.NewAttribute(types.AttributeKeyReason, types.AttributeValueProductUnavailable),
+			),
+		)
+	}
+}
+
+// GetDueSubscriptions returns the subscriptions whose paid period ended by the given
+// height, iterating only over the index entries of the heights up to it
+func (k Keeper) GetDueSubscriptions(ctx sdk.Context, height int64) []types.Subscription {
+	store := ctx.KVStore(k.storeKey)
+
+	iterator := store.Iterator([]byte(types.SubscriptionDuePrefix), types.SubscriptionDuePrefixUntil(height+1))
+	defer iterator.Close()
+
+	var due []types.Subscription
+	for ; iterator.Valid(); iterator.Next() {
+		var subscription types.Subscription
+		k.cdc.MustUnmarshalBinaryBare(store.Get(iterator.Value()), &subscription)
+		due = append(due, subscription)
+	}
+	return due
+}
+
+// BumpProductVersion returns the product with its version bumped past the
+// previous one and appends the change to the product history
+func (k Keeper) BumpProductVersion(ctx sdk.Context, previous, product types.Product) types.Product {
+	product.Version = previous.Version + 1
+
+	k.SetProductHistoryEntry(ctx, types.NewProductHistoryEntry(previous, product, ctx.BlockHeight()))
+
+	return product
+}
+
+// SetProductHistoryEntry stores one version in the history of a product
+func (k Keeper) SetProductHistoryEntry(ctx sdk.Context, entry types.ProductHistoryEntry) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.ProductHistoryKey(entry.ProductID, entry.Version), k.cdc.MustMarshalBinaryBare(entry))
+}
+
+// GetProductHistoryIterator returns an iterator over the history of all products
+func (k Keeper) GetProductHistoryIterator(ctx sdk.Context) sdk.Iterator {
+	store := ctx.KVStore(k.storeKey)
+	return sdk.KVStorePrefixIterator(store, []byte(types.ProductHistoryPrefix))
+}
+
+// GetProductHistory returns the update audit trail of a product, oldest version first
+func (k Keeper) GetProductHistory(ctx sdk.Context, productID string) []types.ProductHistoryEntry {
+	store := ctx.KVStore(k.storeKey)
+
+	iterator := sdk.KVStorePrefixIterator(store, types.ProductHistoryPrefixFor(productID))
+	defer iterator.Close()
+
+	history := []types.ProductHistoryEntry{}
+	for ; iterator.Valid(); iterator.Next() {
+		var entry types.ProductHistoryEntry
+		k.cdc.MustUnmarshalBinaryBare(iterator.Value(), &entry)
+		// product IDs may contain the separator, so the prefix can match other products
+		if entry.ProductID == productID {
+			history = append(history, entry)
+		}
+	}
+	return history
+}
+
+func (k Keeper) deleteProductHistory(ctx sdk.Context, productID string) {
+	store := ctx.KVStore(k.storeKey)
+
+	for _, entry := range k.GetProductHistory(ctx, productID) {
+		store.Delete(types.ProductHistoryKey(productID, entry.Version))
+	}
+}
+
+// GetLicense returns the license of a holder for a product and whether it exists
+func (k Keeper) GetLicense(ctx sdk.Context, productID string, holder sdk.AccAddress) (types.License, bool) {
+	store := ctx.KVStore(k.storeKey)
+
+	bz := store.Get(types.LicenseKey(productID, holder))
+	if bz == nil {
+		return types.License{}, false
+	}
+
+	var license types.License
+	k.cdc.MustUnmarshalBinaryBare(bz, &license)
+	return license, true
+}
+
+// SetLicense stores a license
+func (k Keeper) SetLicense(ctx sdk.Context, license types.License) {
+	if license.Holder.Empty() {
+		return
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.LicenseKey(license.ProductID, license.Holder), k.cdc.MustMarshalBinaryBare(license))
+}
+
+// HasLicense returns whether an address holds a valid license for a product
+func (k Keeper) HasLicense(ctx sdk.Context, productID string, holder sdk.AccAddress) bool {
+	license, found := k.GetLicense(ctx, productID, holder)
+	return found && license.IsValid(ctx.BlockHeight())
+}
+
+// GetLicenses returns all the licenses of a product
+func (k Keeper) GetLicenses(ctx sdk.Context, productID string) []types.License {
+	store := ctx.KVStore(k.storeKey)
+
+	iterator := sdk.KVStorePrefixIterator(store, types.LicensesPrefix(productID))
+	defer iterator.Close()
+
+	licenses := []types.License{}
+	for ; iterator.Valid(); iterator.Next() {
+		var license types.License
+		k.cdc.MustUnmarshalBinaryBare(iterator.Value(), &license)
+		// product IDs may contain the separator, so the prefix can match other products
+		if license.ProductID == productID {
+			licenses = append(licenses, license)
+		}
+	}
+	return licenses
+}
+
+func (k Keeper) deleteLicenses(ctx sdk.Context, productID string) {
+	store := ctx.KVStore(k.storeKey)
+
+	for _, license := range k.GetLicenses(ctx, productID) {
+		store.Delete(types.LicenseKey(productID, license.Holder))
+	}
+}
+
+// GetLicensesIterator returns an iterator over all licenses
+func (k Keeper) GetLicensesIterator(ctx sdk.Context) sdk.Iterator {
+	store := ctx.KVStore(k.storeKey)
+	return sdk.KVStorePrefixIterator(store, []byte(types.LicensePrefix))
 }
@@ -0,0 +1,324 @@
+package keeper
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+	pricefeedtypes "github.com/cosmos/sdk-tutorials/nameservice/x/pricefeed/types"
+)
+
+func TestWhois(t *testing.T) {
+	input := CreateTestInput(t)
+	ctx, keeper := input.Ctx, input.Keeper
+
+	require.False(t, keeper.IsNamePresent(ctx, "alice"))
+	require.Equal(t, types.MinNamePrice, keeper.GetPrice(ctx, "alice"))
+	require.False(t, keeper.HasOwner(ctx, "alice"))
+
+	price := sdk.NewCoins(sdk.NewInt64Coin("nametoken", 10))
+	keeper.SetOwner(ctx, "alice", Addrs[0])
+	keeper.SetPrice(ctx, "alice", price)
+	keeper.SetName(ctx, "alice", "8.8.8.8")
+
+	require.True(t, keeper.IsNamePresent(ctx, "alice"))
+	require.True(t, keeper.HasOwner(ctx, "alice"))
+	require.Equal(t, Addrs[0], keeper.GetOwner(ctx, "alice"))
+	require.Equal(t, price, keeper.GetPrice(ctx, "alice"))
+	require.Equal(t, "8.8.8.8", keeper.ResolveName(ctx, "alice"))
+
+	keeper.DeleteWhois(ctx, "alice")
+	require.False(t, keeper.IsNamePresent(ctx, "alice"))
+}
+
+func TestNamesIteratorSkipsOtherRecords(t *testing.T) {
+	input := CreateTestInput(t)
+	ctx, keeper := input.Ctx, input.Keeper
+
+	price := sdk.NewCoins(sdk.NewInt64Coin("nametoken", 10))
+	keeper.SetWhois(ctx, "alice", types.Whois{Value: "8.8.8.8", Owner: Addrs[0], Price: price})
+	keeper.SetProduct(ctx, "Product-book1", types.Product{ProductID: "book1", Owner: Addrs[0], Price: price,
+		Category: "books", Tags: []string{"new"}, Storefront: "alice"})
+	keeper.SetAuction(ctx, types.NewAuction("book1", Addrs[0], types.AuctionEnglish, price, nil, nil, 1, 10))
+	keeper.SetPurchase(ctx, types.Purchase{ProductID: "book1", Buyer: Addrs[1], Seller: Addrs[0], Price: price, Height: 1})
+	keeper.SetReview(ctx, types.Review{ProductID: "book1", Reviewer: Addrs[1], Seller: Addrs[0], Rating: 4, Height: 1})
+	keeper.SetCoupon(ctx, types.Coupon{CodeHash: types.HashCouponCode("HALF"), Issuer: Addrs[0], Percent: 50})
+	keeper.SetSubscription(ctx, types.NewSubscription("book1", Addrs[1], price, 10, 1))
+	keeper.SetLicense(ctx, types.NewLicense("book1", Addrs[1], 1, 0))
+
+	var keys []string
+	iterator := keeper.GetNamesIterator(ctx)
+	for ; iterator.Valid(); iterator.Next() {
+		keys = append(keys, string(iterator.Key()))
+	}
+	iterator.Close()
+	require.Equal(t, []string{string(types.WhoisKey("alice"))}, keys)
+}
+
+func TestProductIndexes(t *testing.T) {
+	input := CreateTestInput(t)
+	ctx, keeper := input.Ctx, input.Keeper
+
+	product := types.Product{
+		ProductID:  "book1",
+		Owner:      Addrs[0],
+		Price:      sdk.NewCoins(sdk.NewInt64Coin("nametoken", 10)),
+		Category:   "books",
+		Tags:       []string{"new", "rare"},
+		Storefront: "alice",
+	}
+	keeper.SetProduct(ctx, "Product-book1", product)
+
+	require.True(t, keeper.IsProductPresent(ctx, "Product-book1"))
+	require.Equal(t, []string{"book1"}, keeper.GetCategoryProductIDs(ctx, "books"))
+	require.Equal(t, []string{"book1"}, keeper.GetTagProductIDs(ctx, "rare"))
+	require.Equal(t, []string{"book1"}, keeper.GetStorefrontProductIDs(ctx, "alice"))
+
+	// moving the product drops the stale index entries
+	product.Category = "music"
+	product.Tags = []string{"new"}
+	product.Storefront = ""
+	keeper.SetProduct(ctx, "Product-book1", product)
+
+	require.Empty(t, keeper.GetCategoryProductIDs(ctx, "books"))
+	require.Equal(t, []string{"book1"}, keeper.GetCategoryProductIDs(ctx, "music"))
+	require.Empty(t, keeper.GetTagProductIDs(ctx, "rare"))
+	require.Empty(t, keeper.GetStorefrontProductIDs(ctx, "alice"))
+
+	keeper.DeleteProduct(ctx, "Product-book1")
+	require.False(t, keeper.IsProductPresent(ctx, "Product-book1"))
+	require.Empty(t, keeper.GetCategoryProductIDs(ctx, "music"))
+	require.Empty(t, keeper.GetTagProductIDs(ctx, "new"))
+}
+
+func TestGetProductPrice(t *testing.T) {
+	input := CreateTestInput(t)
+	ctx, keeper := input.Ctx, input.Keeper
+
+	price := sdk.NewCoins(sdk.NewInt64Coin("nametoken", 10))
+	accepted := sdk.NewCoins(sdk.NewInt64Coin(sdk.DefaultBondDenom, 25))
+	fixed := types.Product{ProductID: "fixed", Owner: Addrs[0], Price: price, AcceptedPrices: accepted}
+	pegged := types.Product{ProductID: "pegged", Owner: Addrs[0], Price: price,
+		ReferencePrice: sdk.NewDecCoins(sdk.NewInt64DecCoin("usd", 5))}
+
+	// a price is usable for the maximum price age after the height it was posted at
+	ctx = ctx.WithBlockHeight(200)
+	maxAge := input.PriceFeedKeeper.MaxPriceAge(ctx)
+	input.PriceFeedKeeper.SetPrice(ctx, pricefeedtypes.Price{Denom: "nametoken", Unit: "usd", Price: sdk.NewDecWithPrec(2, 1),
+		Height: 200 - maxAge})
+	input.PriceFeedKeeper.SetPrice(ctx, pricefeedtypes.Price{Denom: "atom", Unit: "usd", Price: sdk.NewDecWithPrec(2, 1),
+		Height: 200 - maxAge - 1})
+
+	tests := []struct {
+		name     string
+		product  types.Product
+		denom    string
+		expPrice sdk.Coins
+		expErr   error
+	}{
+		{"listed price", fixed, "", price, nil},
+		{"listed denomination", fixed, "nametoken", price, nil},
+		{"accepted price", fixed, sdk.DefaultBondDenom, accepted, nil},
+		{"denomination not accepted", fixed, "atom", nil, types.ErrDenomNotAccepted},
+		{"pegged price at the feed rate", pegged, "nametoken", sdk.NewCoins(sdk.NewInt64Coin("nametoken", 25)), nil},
+		{"pegged price without denomination", pegged, "", nil, types.ErrDenomNotAccepted},
+		{"pegged price without feed", pegged, sdk.DefaultBondDenom, nil, types.ErrPriceUnavailable},
+		{"pegged price with stale feed", pegged, "atom", nil, types.ErrPriceStale},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := keeper.GetProductPrice(ctx, tc.product, tc.denom)
+			if tc.expErr != nil {
+				require.True(t, errors.Is(err, tc.expErr), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expPrice, got)
+		})
+	}
+}
+
+func TestRedeemCoupon(t *testing.T) {
+	input := CreateTestInput(t)
+	ctx, keeper := input.Ctx, input.Keeper
+
+	product := types.Product{ProductID: "book1", Owner: Addrs[0], Price: sdk.NewCoins(sdk.NewInt64Coin("nametoken", 100))}
+	codeHash := types.HashCouponCode("HALF")
+	keeper.SetCoupon(ctx, types.Coupon{
+		CodeHash: codeHash,
+		Issuer:   Addrs[0],
+		Percent:  50,
+		MaxUses:  1,
+	})
+
+	_, err := keeper.RedeemCoupon(ctx, "HALF", Addrs[1], product, product.Price)
+	require.True(t, errors.Is(err, types.ErrCouponNotCommitted))
+
+	// a commitment only counts from the next block on, and only for the buyer it names
+	keeper.SetCouponCommitment(ctx, types.CouponCommitment{CodeHash: codeHash,
+		Commitment: types.HashCouponCommitment("HALF", Addrs[1]), Signer: Addrs[1], Height: ctx.BlockHeight()})
+	_, err = keeper.RedeemCoupon(ctx, "HALF", Addrs[1], product, product.Price)
+	require.True(t, errors.Is(err, types.ErrCouponNotCommitted))
+
+	ctx = ctx.WithBlockHeight(ctx.BlockHeight() + 1)
+	_, err = keeper.RedeemCoupon(ctx, "HALF", Addrs[2], product, product.Price)
+	require.True(t, errors.Is(err, types.ErrCouponNotCommitted))
+
+	discounted, err := keeper.RedeemCoupon(ctx, "HALF", Addrs[1], product, product.Price)
+	require.NoError(t, err)
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin("nametoken", 50)), discounted)
+
+	coupon, found := keeper.GetCoupon(ctx, codeHash)
+	require.True(t, found)
+	require.Equal(t, uint64(1), coupon.Uses)
+
+	_, err = keeper.RedeemCoupon(ctx, "HALF", Addrs[1], product, product.Price)
+	require.True(t, errors.Is(err, types.ErrCouponNotApplicable))
+
+	_, err = keeper.RedeemCoupon(ctx, "NONE", Addrs[1], product, product.Price)
+	require.True(t, errors.Is(err, types.ErrCouponDoesNotExist))
+
+	// revoking a coupon drops the commitments to it
+	keeper.DeleteCoupon(ctx, codeHash)
+	_, found = keeper.GetCouponCommitment(ctx, codeHash, types.HashCouponCommitment("HALF", Addrs[1]))
+	require.False(t, found)
+}
+
+func TestReviewsOfPrefixedProductIDs(t *testing.T) {
+	input := CreateTestInput(t)
+	ctx, keeper := input.Ctx, input.Keeper
+
+	// "a/b" cannot be created through messages, but its keys start with those of "a"
+	keeper.SetReview(ctx, types.Review{ProductID: "a", Reviewer: Addrs[1], Seller: Addrs[0], Rating: 5, Height: 1})
+	keeper.SetReview(ctx, types.Review{ProductID: "a/b", Reviewer: Addrs[2], Seller: Addrs[0], Rating: 1, Height: 1})
+
+	reviews := keeper.GetReviews(ctx, "a")
+	require.Len(t, reviews, 1)
+	require.Equal(t, Addrs[1], reviews[0].Reviewer)
+
+	reviews = keeper.GetReviews(ctx, "a/b")
+	require.Len(t, reviews, 1)
+	require.Equal(t, Addrs[2], reviews[0].Reviewer)
+
+	err := types.NewMsgCreateProduct("a/b", "a book", sdk.NewCoins(sdk.NewInt64Coin("nametoken", 10)), "", nil,
+		types.Content{}, Addrs[0]).ValidateBasic()
+	require.Error(t, err)
+}
+
+func TestSubscriptions(t *testing.T) {
+	input := CreateTestInput(t)
+	ctx, keeper := input.Ctx, input.Keeper
+	price := sdk.NewCoins(sdk.NewInt64Coin("nametoken", 10))
+
+	a := types.NewSubscription("a", Addrs[1], price, 10, 1)
+	ab := types.NewSubscription("a/b", Addrs[2], price, 20, 1)
+	keeper.SetSubscription(ctx, a)
+	keeper.SetSubscription(ctx, ab)
+
+	// the keys of "a/b" start with those of "a"
+	require.Equal(t, []types.Subscription{a}, keeper.GetSubscriptions(ctx, "a"))
+	require.Equal(t, []types.Subscription{ab}, keeper.GetSubscriptions(ctx, "a/b"))
+
+	// the due index only holds subscriptions whose paid period ended
+	require.Empty(t, keeper.GetDueSubscriptions(ctx, 10))
+	require.Equal(t, []types.Subscription{a}, keeper.GetDueSubscriptions(ctx, 11))
+	require.Equal(t, []types.Subscription{a, ab}, keeper.GetDueSubscriptions(ctx, 21))
+
+	// renewing moves the subscription in the index
+	a.PaidUntil += a.Period
+	keeper.SetSubscription(ctx, a)
+	require.Empty(t, keeper.GetDueSubscriptions(ctx, 20))
+	require.Len(t, keeper.GetDueSubscriptions(ctx, 21), 2)
+
+	keeper.DeleteSubscription(ctx, "a/b", Addrs[2])
+	require.Equal(t, []types.Subscription{a}, keeper.GetDueSubscriptions(ctx, 21))
+	require.Empty(t, keeper.GetSubscriptions(ctx, "a/b"))
+}
+
+func TestProductHistoryOfPrefixedProductIDs(t *testing.T) {
+	input := CreateTestInput(t)
+	ctx, keeper := input.Ctx, input.Keeper
+	price := sdk.NewCoins(sdk.NewInt64Coin("nametoken", 10))
+
+	for _, productID := range []string{"a", "a/b"} {
+		previous := types.Product{ProductID: productID, Owner: Addrs[0], Price: price, Description: "first"}
+		updated := previous
+		updated.Description = "second"
+		keeper.BumpProductVersion(ctx, previous, updated)
+	}
+
+	// the keys of "a/b" start with those of "a"
+	history := keeper.GetProductHistory(ctx, "a")
+	require.Len(t, history, 1)
+	require.Equal(t, "a", history[0].ProductID)
+
+	history = keeper.GetProductHistory(ctx, "a/b")
+	require.Len(t, history, 1)
+	require.Equal(t, "a/b", history[0].ProductID)
+
+	// deleting "a" leaves the history of "a/b"
+	keeper.SetProduct(ctx, "Product-a", types.Product{ProductID: "a", Owner: Addrs[0], Price: price})
+	keeper.DeleteProduct(ctx, "Product-a")
+	require.Empty(t, keeper.GetProductHistory(ctx, "a"))
+	require.Len(t, keeper.GetProductHistory(ctx, "a/b"), 1)
+
+	err := types.NewMsgUpdateProduct("a/b", "second", price, "", nil, types.Content{}, Addrs[0]).ValidateBasic()
+	require.Error(t, err)
+}
+
+func TestDeleteProductRemovesLicenses(t *testing.T) {
+	input := CreateTestInput(t)
+	ctx, keeper := input.Ctx, input.Keeper
+	price := sdk.NewCoins(sdk.NewInt64Coin("nametoken", 10))
+
+	keeper.SetProduct(ctx, "Product-a", types.Product{ProductID: "a", Owner: Addrs[0], Price: price, Licensing: true})
+	keeper.SetLicense(ctx, types.NewLicense("a", Addrs[1], 1, 0))
+	keeper.SetLicense(ctx, types.NewLicense("a/b", Addrs[2], 1, 0))
+
+	// the keys of "a/b" start with those of "a"
+	require.Len(t, keeper.GetLicenses(ctx, "a"), 1)
+
+	// the holders of a deleted product hold no license for a product recreated under its ID
+	keeper.DeleteProduct(ctx, "Product-a")
+	keeper.SetProduct(ctx, "Product-a", types.Product{ProductID: "a", Owner: Addrs[2], Price: price, Licensing: true})
+	require.False(t, keeper.HasLicense(ctx, "a", Addrs[1]))
+	require.Empty(t, keeper.GetLicenses(ctx, "a"))
+	require.True(t, keeper.HasLicense(ctx, "a/b", Addrs[2]))
+}
+
+func TestDeleteProductRemovesPurchasesAndReviews(t *testing.T) {
+	input := CreateTestInput(t)
+	ctx, keeper := input.Ctx, input.Keeper
+	price := sdk.NewCoins(sdk.NewInt64Coin("nametoken", 10))
+	seller, buyer := Addrs[0], Addrs[1]
+
+	for _, productID := range []string{"a", "a/b", "c"} {
+		keeper.SetProduct(ctx, types.ProductPrefix+productID, types.Product{ProductID: productID, Owner: seller, Price: price})
+		keeper.SetPurchase(ctx, types.Purchase{ProductID: productID, Buyer: buyer, Seller: seller, Price: price, Height: 1})
+	}
+	keeper.SetReview(ctx, types.Review{ProductID: "a", Reviewer: buyer, Seller: seller, Rating: 1, Height: 1})
+	keeper.SetReview(ctx, types.Review{ProductID: "c", Reviewer: buyer, Seller: seller, Rating: 5, Height: 1})
+
+	// the buyers of a deleted product neither bought nor reviewed a product recreated
+	// under its ID, which starts without rating
+	keeper.DeleteProduct(ctx, "Product-a")
+	keeper.SetProduct(ctx, "Product-a", types.Product{ProductID: "a", Owner: seller, Price: price})
+	_, found := keeper.GetPurchase(ctx, "a", buyer)
+	require.False(t, found)
+	require.False(t, keeper.HasReview(ctx, "a", buyer))
+	require.Empty(t, keeper.GetReviews(ctx, "a"))
+	require.Equal(t, types.Rating{}, keeper.GetProductRating(ctx, "a"))
+
+	// the seller keeps the rating of the other products, as genesis would rebuild it
+	require.Equal(t, types.Rating{Count: 1, Total: 5}, keeper.GetSellerRating(ctx, seller))
+
+	// the keys of "a/b" start with those of "a"
+	_, found = keeper.GetPurchase(ctx, "a/b", buyer)
+	require.True(t, found)
+	require.Len(t, keeper.GetPurchases(ctx, "c"), 1)
+}
@@ -0,0 +1,78 @@
+package keeper
+
+import (
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
+
+// GetLayoutVersion returns the version of the layout the store is written in. A store
+// without a version predates the versioning and is written in layout 0.
+func (k Keeper) GetLayoutVersion(ctx sdk.Context) byte {
+	bz := ctx.KVStore(k.storeKey).Get([]byte(types.LayoutVersionKey))
+	if len(bz) != 1 {
+		return 0
+	}
+	return bz[0]
+}
+
+// SetLayoutVersion records the version of the layout the store is written in
+func (k Keeper) SetLayoutVersion(ctx sdk.Context, version byte) {
+	ctx.KVStore(k.storeKey).Set([]byte(types.LayoutVersionKey), []byte{version})
+}
+
+// MigrateStore rewrites a store written in an older layout to the current one. It is
+// a no-op once the store is up to date, so it is safe to run at every block.
+func (k Keeper) MigrateStore(ctx sdk.Context) {
+	if k.GetLayoutVersion(ctx) >= types.LayoutVersion {
+		return
+	}
+	k.migrateWhoisKeys(ctx)
+	k.SetLayoutVersion(ctx, types.LayoutVersion)
+}
+
+// migrateWhoisKeys moves the name records stored under the bare name by layout 0 to
+// WhoisKey. Every key without the prefix of another record is a name; names starting
+// with such a prefix already collided with those records and are left alone. A record
+// already stored under WhoisKey was written after the upgrade and wins over the bare one.
+func (k Keeper) migrateWhoisKeys(ctx sdk.Context) {
+	store := ctx.KVStore(k.storeKey)
+
+	var names []string
+	iterator := store.Iterator(nil, nil)
+	for ; iterator.Valid(); iterator.Next() {
+		if key := string(iterator.Key()); !hasRecordPrefix(key) {
+			names = append(names, key)
+		}
+	}
+	iterator.Close()
+
+	for _, name := range names {
+		bz := store.Get([]byte(name))
+
+		var whois types.Whois
+		if err := k.cdc.UnmarshalBinaryBare(bz, &whois); err != nil {
+			k.Logger(ctx).Error("skipped an undecodable name record", "name", name, "err", err)
+			continue
+		}
+		store.Delete([]byte(name))
+		if k.IsNamePresent(ctx, name) {
+			continue
+		}
+		store.Set(types.WhoisKey(name), bz)
+	}
+	k.Logger(ctx).Info("migrated name records", "count", len(names))
+}
+
+func hasRecordPrefix(key string) bool {
+	if strings.HasPrefix(key, types.WhoisPrefix) {
+		return true
+	}
+	for _, prefix := range types.RecordPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package keeper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
+
+func TestMigrateStoreMovesBareNameRecords(t *testing.T) {
+	input := CreateTestInput(t)
+	ctx, keeper := input.Ctx, input.Keeper
+	store := ctx.KVStore(keeper.storeKey)
+
+	price := sdk.NewCoins(sdk.NewInt64Coin("nametoken", 10))
+	alice := types.Whois{Value: "8.8.8.8", Owner: Addrs[0], Price: price}
+	staleCarol := types.Whois{Value: "1.1.1.1", Owner: Addrs[0], Price: price}
+	carol := types.Whois{Value: "2.2.2.2", Owner: Addrs[1], Price: price}
+	product := types.Product{ProductID: "book1", Owner: Addrs[0], Price: price, Category: "books"}
+
+	// layout 0 stored names under the bare name next to the prefixed records
+	store.Set([]byte("alice"), keeper.cdc.MustMarshalBinaryBare(alice))
+	store.Set([]byte("carol"), keeper.cdc.MustMarshalBinaryBare(staleCarol))
+	store.Set([]byte("garbage"), []byte{0xff})
+	keeper.SetProduct(ctx, types.ProductPrefix+"book1", product)
+	// carol was bought again by a binary writing layout 1 before the migration ran
+	keeper.SetWhois(ctx, "carol", carol)
+
+	require.Equal(t, byte(0), keeper.GetLayoutVersion(ctx))
+	require.False(t, keeper.IsNamePresent(ctx, "alice"))
+
+	keeper.MigrateStore(ctx)
+
+	require.Equal(t, types.LayoutVersion, keeper.GetLayoutVersion(ctx))
+	require.Equal(t, alice, keeper.GetWhois(ctx, "alice"))
+	require.True(t, keeper.HasOwner(ctx, "alice"))
+	require.Equal(t, carol, keeper.GetWhois(ctx, "carol"))
+	require.False(t, store.Has([]byte("alice")))
+	require.False(t, store.Has([]byte("carol")))
+	require.True(t, store.Has([]byte("garbage")))
+	require.Equal(t, product.ProductID, keeper.GetProduct(ctx, types.ProductPrefix+"book1").ProductID)
+	_, broken := WhoisRecordsInvariant(keeper)(ctx)
+	require.False(t, broken)
+
+	// a migrated store is left alone
+	store.Set([]byte("dave"), keeper.cdc.MustMarshalBinaryBare(alice))
+	keeper.MigrateStore(ctx)
+	require.True(t, store.Has([]byte("dave")))
+	require.False(t, keeper.IsNamePresent(ctx, "dave"))
+}
@@ -1,7 +1,10 @@
 package keeper
 
 import (
+	"strings"
+
 	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/store/prefix"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 	abci "github.com/tendermint/tendermint/abci/types"
@@ -14,9 +17,37 @@ const (
 	QueryResolve = "resolve"
 	QueryWhois   = "whois"
 	QueryNames   = "names"
+	// QueryNamesPage returns a page of the records of the names, optionally of one owner
+	QueryNamesPage = "namesPage"
 
 	QueryProduct     = "product"
 	QueryAllProducts = "allProducts"
+	QueryProducts    = "products"
+	// QueryProductsPage returns a page of the products matching filters
+	QueryProductsPage = "productsPage"
+
+	QueryAuction  = "auction"
+	QueryAuctions = "auctions"
+	QueryBids     = "bids"
+
+	QueryStorefront     = "storefront"
+	QueryResolveProduct = "resolveProduct"
+
+	QueryReviews    = "reviews"
+	QueryRating     = "rating"
+	QueryReputation = "reputation"
+
+	QueryCoupon = "coupon"
+
+	QuerySubscription  = "subscription"
+	QuerySubscriptions = "subscriptions"
+
+	QueryProductHistory = "productHistory"
+
+	QueryHasLicense = "hasLicense"
+
+	// QueryListedFilter restricts an allProducts query to products that are for sale
+	QueryListedFilter = "listed"
 )
 
 // NewQuerier is the module level router for state queries
@@ -29,10 +60,42 @@ func NewQuerier(keeper Keeper) sdk.Querier {
 			return queryWhois(ctx, path[1:], req, keeper)
 		case QueryNames:
 			return queryNames(ctx, req, keeper)
+		case QueryNamesPage:
+			return queryNamesPage(ctx, req, keeper)
 		case QueryProduct:
 			return queryProduct(ctx, path[1:], req, keeper)
 		case QueryAllProducts:
-			return queryAllProducts(ctx, req, keeper)
+			return queryAllProducts(ctx, path[1:], req, keeper)
+		case QueryProducts:
+			return queryProducts(ctx, req, keeper)
+		case QueryProductsPage:
+			return queryProductsPage(ctx, req, keeper)
+		case QueryAuction:
+			return queryAuction(ctx, path[1:], req, keeper)
+		case QueryAuctions:
+			return queryAuctions(ctx, req, keeper)
+		case QueryBids:
+			return queryBids(ctx, path[1:], req, keeper)
+		case QueryStorefront:
+			return queryStorefront(ctx, path[1:], req, keeper)
+		case QueryResolveProduct:
+			return queryResolveProduct(ctx, path[1:], req, keeper)
+		case QueryReviews:
+			return queryReviews(ctx, path[1:], req, keeper)
+		case QueryRating:
+			return queryRating(ctx, path[1:], req, keeper)
+		case QueryReputation:
+			return queryReputation(ctx, path[1:], req, keeper)
+		case QueryCoupon:
+			return queryCoupon(ctx, path[1:], req, keeper)
+		case QuerySubscription:
+			return querySubscription(ctx, path[1:], req, keeper)
+		case QuerySubscriptions:
+			return querySubscriptions(ctx, path[1:], req, keeper)
+		case QueryProductHistory:
+			return queryProductHistory(ctx, path[1:], req, keeper)
+		case QueryHasLicense:
+			return queryHasLicense(ctx, path[1:], req, keeper)
 		default:
 			return nil, sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "unknown nameservice query endpoint")
 		}
@@ -73,7 +136,7 @@ func queryNames(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte,
 	iterator := keeper.GetNamesIterator(ctx)
 
 	for ; iterator.Valid(); iterator.Next() {
-		namesList = append(namesList, string(iterator.Key()))
+		namesList = append(namesList, strings.TrimPrefix(string(iterator.Key()), types.WhoisPrefix))
 	}
 
 	res, err := codec.MarshalJSONIndent(keeper.cdc, namesList)
@@ -86,7 +149,7 @@ func queryNames(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte,
 
 func queryProduct(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper) ([]byte, error) {
 
-	key := "Product-" + path[0]
+	key := types.ProductPrefix + path[0]
 
 	product := keeper.GetProduct(ctx, key)
 
@@ -98,23 +161,389 @@ func queryProduct(ctx sdk.Context, path []string, req abci.RequestQuery, keeper
 	return res, nil
 }
 
-func queryAllProducts(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, error) {
+func queryAllProducts(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper) ([]byte, error) {
 
 	var productsList types.QueryResAllProducts
 
+	listedOnly := len(path) > 0 && path[0] == QueryListedFilter
+
 	iterator := keeper.GetProductsIterator(ctx)
 
 	for ; iterator.Valid(); iterator.Next() {
-		key := string(iterator.Key())
-		if "Product-" <= key && key <= "Product-zzzzzzzz" {
-			product := keeper.GetProduct(ctx, key)
+		product := keeper.GetProduct(ctx, string(iterator.Key()))
+		if listedOnly && !product.Listed {
+			continue
+		}
+		productsList = append(productsList, product)
+	}
+	res, err := codec.MarshalJSONIndent(keeper.cdc, productsList)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+	return res, nil
+}
+
+func queryProducts(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, error) {
+	var params types.QueryProductsParams
+
+	err := keeper.cdc.UnmarshalJSON(req.Data, &params)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONUnmarshal, err.Error())
+	}
+
+	productsList := types.QueryResAllProducts{}
+	for _, productID := range filterProductIDs(ctx, params, keeper) {
+		product := keeper.GetProduct(ctx, types.ProductPrefix+productID)
+		if params.Matches(product) {
 			productsList = append(productsList, product)
+		}
+	}
 
+	res, err := codec.MarshalJSONIndent(keeper.cdc, productsList)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+	return res, nil
+}
+
+// filterProductIDs returns the IDs, sorted, of the products that may match the filters,
+// read from the narrowest index the filters select
+func filterProductIDs(ctx sdk.Context, params types.QueryProductsParams, keeper Keeper) []string {
+	return keeper.getIndexedProductIDs(ctx, productIDsPrefix(params))
+}
+
+// productIDsPrefix returns the prefix of the keys ending with the IDs of the products
+// that may match the filters: the narrowest index they select, or the products
+func productIDsPrefix(params types.QueryProductsParams) []byte {
+	switch {
+	case params.Storefront != "":
+		return types.StorefrontIndexPrefix(params.Storefront)
+	case params.Category != "":
+		return types.CategoryIndexPrefix(params.Category)
+	case params.Tag != "":
+		return types.TagIndexPrefix(params.Tag)
+	}
+	return []byte(types.ProductPrefix)
+}
+
+// pageIterator iterates over the keys under a prefix, with the prefix stripped. It
+// starts past the start of the page, or at the first key to count the total.
+func pageIterator(ctx sdk.Context, keeper Keeper, keyPrefix []byte, page types.QueryPage) sdk.Iterator {
+	store := prefix.NewStore(ctx.KVStore(keeper.storeKey), keyPrefix)
+	if page.After == "" || page.CountTotal {
+		return store.Iterator(nil, nil)
+	}
+	// the smallest key following After
+	return store.Iterator(append([]byte(page.After), 0), nil)
+}
+
+// queryNamesPage returns the records of a page of names, in key order. Unless the
+// total is counted, the names past the page are not read.
+func queryNamesPage(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, error) {
+	var params types.QueryNamesPageParams
+	if err := keeper.cdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONUnmarshal, err.Error())
+	}
+	if err := params.Page.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	page := types.QueryResNamesPage{Names: []types.NamedWhois{}}
+
+	iterator := pageIterator(ctx, keeper, []byte(types.WhoisPrefix), params.Page)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var whois types.Whois
+		keeper.cdc.MustUnmarshalBinaryBare(iterator.Value(), &whois)
+		if !params.Owner.Empty() && !whois.Owner.Equals(params.Owner) {
+			continue
+		}
+
+		if params.Page.CountTotal {
+			page.Total++
 		}
+		name := string(iterator.Key())
+		if !params.Page.Includes(name) {
+			continue
+		}
+		if len(page.Names) < params.Page.Limit {
+			page.Names = append(page.Names, types.NamedWhois{Name: name, Whois: whois})
+			continue
+		}
+		page.HasNext = true
+		if !params.Page.CountTotal {
+			break
+		}
+	}
+
+	res, err := codec.MarshalJSONIndent(keeper.cdc, page)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+	return res, nil
+}
+
+// queryProductsPage returns a page of the products matching filters, sorted by ID.
+// Unless the total is counted, the products past the page are not read.
+func queryProductsPage(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, error) {
+	var params types.QueryProductsPageParams
+	if err := keeper.cdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONUnmarshal, err.Error())
+	}
+	if err := params.Page.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	page := types.QueryResProductsPage{Products: []types.Product{}}
+
+	iterator := pageIterator(ctx, keeper, productIDsPrefix(params.Filter), params.Page)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		productID := string(iterator.Key())
+		product := keeper.GetProduct(ctx, types.ProductPrefix+productID)
+		if !params.Filter.Matches(product) {
+			continue
+		}
+
+		if params.Page.CountTotal {
+			page.Total++
+		}
+		if !params.Page.Includes(productID) {
+			continue
+		}
+		if len(page.Products) < params.Page.Limit {
+			page.Products = append(page.Products, product)
+			continue
+		}
+		page.HasNext = true
+		if !params.Page.CountTotal {
+			break
+		}
+	}
+
+	res, err := codec.MarshalJSONIndent(keeper.cdc, page)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+	return res, nil
+}
+
+func queryAuction(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper) ([]byte, error) {
+	if !keeper.IsAuctionPresent(ctx, path[0]) {
+		return nil, sdkerrors.Wrap(types.ErrAuctionDoesNotExist, path[0])
+	}
+
+	auction := keeper.GetAuction(ctx, path[0])
+
+	res, err := codec.MarshalJSONIndent(keeper.cdc, auction)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+
+	return res, nil
+}
+
+func queryAuctions(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, error) {
+	var auctionsList types.QueryResAuctions
+
+	iterator := keeper.GetAuctionsIterator(ctx)
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		var auction types.Auction
+		keeper.cdc.MustUnmarshalBinaryBare(iterator.Value(), &auction)
+		if !auction.IsFinished(ctx.BlockHeight()) {
+			auctionsList = append(auctionsList, auction)
+		}
+	}
+
+	res, err := codec.MarshalJSONIndent(keeper.cdc, auctionsList)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+
+	return res, nil
+}
+
+func queryBids(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper) ([]byte, error) {
+	if !keeper.IsAuctionPresent(ctx, path[0]) {
+		return nil, sdkerrors.Wrap(types.ErrAuctionDoesNotExist, path[0])
+	}
+
+	bids := types.QueryResBids(keeper.GetAuction(ctx, path[0]).Bids)
+
+	res, err := codec.MarshalJSONIndent(keeper.cdc, bids)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
 	}
+
+	return res, nil
+}
+
+func queryStorefront(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper) ([]byte, error) {
+	productsList := types.QueryResAllProducts{}
+
+	for _, productID := range keeper.GetStorefrontProductIDs(ctx, path[0]) {
+		productsList = append(productsList, keeper.GetProduct(ctx, types.ProductPrefix+productID))
+	}
+
 	res, err := codec.MarshalJSONIndent(keeper.cdc, productsList)
 	if err != nil {
 		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
 	}
+
+	return res, nil
+}
+
+// queryResolveProduct resolves a name/productID address to the product published there
+func queryResolveProduct(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper) ([]byte, error) {
+	// product IDs cannot contain the separator, so an address has exactly two parts
+	if len(path) != 2 {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "product address must be name/productID")
+	}
+
+	name, productID := path[0], path[1]
+	if !keeper.IsProductPresent(ctx, types.ProductPrefix+productID) {
+		return nil, sdkerrors.Wrap(types.ErrProductDoesNotExist, productID)
+	}
+
+	product := keeper.GetProduct(ctx, types.ProductPrefix+productID)
+	if product.Storefront == "" || product.Storefront != name {
+		return nil, sdkerrors.Wrapf(types.ErrProductNotPublished, "%s/%s", name, productID)
+	}
+
+	res, err := codec.MarshalJSONIndent(keeper.cdc, product)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+
+	return res, nil
+}
+
+func queryReviews(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper) ([]byte, error) {
+	reviews := types.QueryResReviews(keeper.GetReviews(ctx, path[0]))
+
+	res, err := codec.MarshalJSONIndent(keeper.cdc, reviews)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+
+	return res, nil
+}
+
+func queryRating(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper) ([]byte, error) {
+	rating := types.NewQueryResRating(keeper.GetProductRating(ctx, path[0]))
+
+	res, err := codec.MarshalJSONIndent(keeper.cdc, rating)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+
+	return res, nil
+}
+
+func queryReputation(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper) ([]byte, error) {
+	seller, err := sdk.AccAddressFromBech32(path[0])
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, path[0])
+	}
+
+	rating := types.NewQueryResRating(keeper.GetSellerRating(ctx, seller))
+
+	res, err := codec.MarshalJSONIndent(keeper.cdc, rating)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+
+	return res, nil
+}
+
+func queryCoupon(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper) ([]byte, error) {
+	coupon, found := keeper.GetCoupon(ctx, path[0])
+	if !found {
+		return nil, sdkerrors.Wrap(types.ErrCouponDoesNotExist, path[0])
+	}
+
+	res, err := codec.MarshalJSONIndent(keeper.cdc, coupon)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+
+	return res, nil
+}
+
+// querySubscription returns whether a subscriber currently has paid access to a product
+func querySubscription(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper) ([]byte, error) {
+	if len(path) < 2 {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "subscription query needs productID and subscriber")
+	}
+
+	subscriber, err := sdk.AccAddressFromBech32(path[1])
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, path[1])
+	}
+
+	subscription, found := keeper.GetSubscription(ctx, path[0], subscriber)
+	if !found {
+		return nil, sdkerrors.Wrapf(types.ErrSubscriptionDoesNotExist, "%s/%s", path[0], path[1])
+	}
+
+	status := types.QueryResSubscriptionStatus{
+		Active:       subscription.IsActive(ctx.BlockHeight()),
+		Subscription: subscription,
+	}
+
+	res, err := codec.MarshalJSONIndent(keeper.cdc, status)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+
+	return res, nil
+}
+
+func querySubscriptions(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper) ([]byte, error) {
+	subscriptions := types.QueryResSubscriptions(keeper.GetSubscriptions(ctx, path[0]))
+
+	res, err := codec.MarshalJSONIndent(keeper.cdc, subscriptions)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+
+	return res, nil
+}
+
+func queryProductHistory(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper) ([]byte, error) {
+	history := types.QueryResProductHistory(keeper.GetProductHistory(ctx, path[0]))
+
+	res, err := codec.MarshalJSONIndent(keeper.cdc, history)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+
+	return res, nil
+}
+
+// queryHasLicense reports whether an address holds a valid license for a product
+func queryHasLicense(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper) ([]byte, error) {
+	if len(path) < 2 {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "license query needs productID and holder")
+	}
+
+	holder, err := sdk.AccAddressFromBech32(path[1])
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, path[1])
+	}
+
+	license, _ := keeper.GetLicense(ctx, path[0], holder)
+	result := types.QueryResHasLicense{
+		HasLicense: keeper.HasLicense(ctx, path[0], holder),
+		License:    license,
+	}
+
+	res, err := codec.MarshalJSONIndent(keeper.cdc, result)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+
 	return res, nil
 }
@@ -0,0 +1,147 @@
+package keeper
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
+
+func TestQuerier(t *testing.T) {
+	input := CreateTestInput(t)
+	ctx, keeper, cdc := input.Ctx, input.Keeper, input.Cdc
+
+	price := sdk.NewCoins(sdk.NewInt64Coin("nametoken", 10))
+	seller, buyer := Addrs[0], Addrs[1]
+
+	keeper.SetWhois(ctx, "alice", types.Whois{Value: "8.8.8.8", Owner: seller, Price: price})
+
+	book := types.Product{ProductID: "book1", Owner: seller, Price: price, Listed: true, Category: "books",
+		Tags: []string{"new"}, Storefront: "alice", Version: 1}
+	album := types.Product{ProductID: "album1", Owner: seller, Price: price, Category: "music"}
+	keeper.SetProduct(ctx, "Product-book1", book)
+	keeper.SetProduct(ctx, "Product-album1", album)
+	entry := types.ProductHistoryEntry{ProductID: "book1", Version: 1, Height: 1, NewPrice: price}
+	keeper.SetProductHistoryEntry(ctx, entry)
+
+	auction := types.NewAuction("album1", seller, types.AuctionEnglish, price, nil, nil, 1, 10)
+	auction.Bids = []types.Bid{{Bidder: buyer, Amount: price, Height: 1}}
+	keeper.SetAuction(ctx, auction)
+
+	review := types.Review{ProductID: "book1", Reviewer: buyer, Seller: seller, Rating: 4, Height: 1}
+	keeper.SetReview(ctx, review)
+
+	coupon := types.Coupon{CodeHash: types.HashCouponCode("HALF"), Issuer: seller, Percent: 50}
+	keeper.SetCoupon(ctx, coupon)
+
+	subscription := types.NewSubscription("book1", buyer, price, 10, 1)
+	keeper.SetSubscription(ctx, subscription)
+
+	license := types.NewLicense("book1", buyer, 1, 0)
+	keeper.SetLicense(ctx, license)
+
+	querier := NewQuerier(keeper)
+
+	mustJSON := func(o interface{}) []byte {
+		return cdc.MustMarshalJSON(o)
+	}
+
+	tests := []struct {
+		name   string
+		path   []string
+		data   []byte
+		expErr error
+		expRes interface{}
+	}{
+		{"resolve", []string{QueryResolve, "alice"}, nil, nil, types.QueryResResolve{Value: "8.8.8.8"}},
+		{"resolve unknown name", []string{QueryResolve, "bob"}, nil, sdkerrors.ErrUnknownRequest, nil},
+		{"whois", []string{QueryWhois, "alice"}, nil, nil, keeper.GetWhois(ctx, "alice")},
+		{"names", []string{QueryNames}, nil, nil, types.QueryResNames{"alice"}},
+		{"names page", []string{QueryNamesPage}, mustJSON(types.QueryNamesPageParams{Page: types.QueryPage{Limit: 1}}), nil,
+			types.QueryResNamesPage{Names: []types.NamedWhois{{Name: "alice", Whois: keeper.GetWhois(ctx, "alice")}}}},
+		{"names page with the total", []string{QueryNamesPage},
+			mustJSON(types.QueryNamesPageParams{Page: types.QueryPage{Limit: 1, CountTotal: true}}), nil,
+			types.QueryResNamesPage{Names: []types.NamedWhois{{Name: "alice", Whois: keeper.GetWhois(ctx, "alice")}}, Total: 1}},
+		{"names page of an owner", []string{QueryNamesPage},
+			mustJSON(types.QueryNamesPageParams{Owner: buyer, Page: types.QueryPage{Limit: 1}}), nil,
+			types.QueryResNamesPage{Names: []types.NamedWhois{}}},
+		{"names page after the last name", []string{QueryNamesPage},
+			mustJSON(types.QueryNamesPageParams{Page: types.QueryPage{After: "alice", Limit: 1, CountTotal: true}}), nil,
+			types.QueryResNamesPage{Names: []types.NamedWhois{}, Total: 1}},
+		{"names page over the limit", []string{QueryNamesPage},
+			mustJSON(types.QueryNamesPageParams{Page: types.QueryPage{Limit: types.MaxPageLimit + 1}}), sdkerrors.ErrInvalidRequest, nil},
+		{"product", []string{QueryProduct, "book1"}, nil, nil, book},
+		{"all products", []string{QueryAllProducts}, nil, nil, types.QueryResAllProducts{album, book}},
+		{"listed products", []string{QueryAllProducts, QueryListedFilter}, nil, nil, types.QueryResAllProducts{book}},
+		{"products by category", []string{QueryProducts}, mustJSON(types.QueryProductsParams{Category: "music"}), nil,
+			types.QueryResAllProducts{album}},
+		{"products by tag", []string{QueryProducts}, mustJSON(types.QueryProductsParams{Tag: "new"}), nil,
+			types.QueryResAllProducts{book}},
+		{"products by storefront", []string{QueryProducts}, mustJSON(types.QueryProductsParams{Storefront: "alice"}), nil,
+			types.QueryResAllProducts{book}},
+		{"products with bad params", []string{QueryProducts}, []byte("{"), sdkerrors.ErrJSONUnmarshal, nil},
+		{"products page", []string{QueryProductsPage}, mustJSON(types.QueryProductsPageParams{Page: types.QueryPage{Limit: 1}}), nil,
+			types.QueryResProductsPage{Products: []types.Product{album}, HasNext: true}},
+		{"products page with the total", []string{QueryProductsPage},
+			mustJSON(types.QueryProductsPageParams{Page: types.QueryPage{Limit: 1, CountTotal: true}}), nil,
+			types.QueryResProductsPage{Products: []types.Product{album}, Total: 2, HasNext: true}},
+		{"products page after a product", []string{QueryProductsPage},
+			mustJSON(types.QueryProductsPageParams{Page: types.QueryPage{After: "album1", Limit: 1}}), nil,
+			types.QueryResProductsPage{Products: []types.Product{book}}},
+		{"products page by storefront", []string{QueryProductsPage},
+			mustJSON(types.QueryProductsPageParams{Filter: types.QueryProductsParams{Storefront: "alice"}, Page: types.QueryPage{Limit: 10, CountTotal: true}}), nil,
+			types.QueryResProductsPage{Products: []types.Product{book}, Total: 1}},
+		{"products page with a negative limit", []string{QueryProductsPage},
+			mustJSON(types.QueryProductsPageParams{Page: types.QueryPage{Limit: -1}}), sdkerrors.ErrInvalidRequest, nil},
+		{"auction", []string{QueryAuction, "album1"}, nil, nil, auction},
+		{"unknown auction", []string{QueryAuction, "book1"}, nil, types.ErrAuctionDoesNotExist, nil},
+		{"auctions", []string{QueryAuctions}, nil, nil, types.QueryResAuctions{auction}},
+		{"bids", []string{QueryBids, "album1"}, nil, nil, types.QueryResBids(auction.Bids)},
+		{"bids of unknown auction", []string{QueryBids, "book1"}, nil, types.ErrAuctionDoesNotExist, nil},
+		{"storefront", []string{QueryStorefront, "alice"}, nil, nil, types.QueryResAllProducts{book}},
+		{"resolve product", []string{QueryResolveProduct, "alice", "book1"}, nil, nil, book},
+		{"resolve unpublished product", []string{QueryResolveProduct, "alice", "album1"}, nil, types.ErrProductNotPublished, nil},
+		{"resolve product without name", []string{QueryResolveProduct, "alice"}, nil, sdkerrors.ErrUnknownRequest, nil},
+		{"resolve unknown product", []string{QueryResolveProduct, "alice", "book2"}, nil, types.ErrProductDoesNotExist, nil},
+		{"resolve unknown product with empty name", []string{QueryResolveProduct, "", "book2"}, nil, types.ErrProductDoesNotExist, nil},
+		{"resolve unpublished product with empty name", []string{QueryResolveProduct, "", "album1"}, nil, types.ErrProductNotPublished, nil},
+		{"resolve product address with separator", []string{QueryResolveProduct, "alice", "book1", "x"}, nil, sdkerrors.ErrUnknownRequest, nil},
+		{"reviews", []string{QueryReviews, "book1"}, nil, nil, types.QueryResReviews{review}},
+		{"rating", []string{QueryRating, "book1"}, nil, nil, types.NewQueryResRating(types.Rating{Count: 1, Total: 4})},
+		{"reputation", []string{QueryReputation, seller.String()}, nil, nil, types.NewQueryResRating(types.Rating{Count: 1, Total: 4})},
+		{"reputation of bad address", []string{QueryReputation, "seller"}, nil, sdkerrors.ErrInvalidAddress, nil},
+		{"coupon", []string{QueryCoupon, coupon.CodeHash}, nil, nil, coupon},
+		{"unknown coupon", []string{QueryCoupon, types.HashCouponCode("NONE")}, nil, types.ErrCouponDoesNotExist, nil},
+		{"subscription", []string{QuerySubscription, "book1", buyer.String()}, nil, nil,
+			types.QueryResSubscriptionStatus{Active: true, Subscription: subscription}},
+		{"unknown subscription", []string{QuerySubscription, "book1", seller.String()}, nil, types.ErrSubscriptionDoesNotExist, nil},
+		{"subscription of bad address", []string{QuerySubscription, "book1", "buyer"}, nil, sdkerrors.ErrInvalidAddress, nil},
+		{"subscription without subscriber", []string{QuerySubscription, "book1"}, nil, sdkerrors.ErrUnknownRequest, nil},
+		{"subscriptions", []string{QuerySubscriptions, "book1"}, nil, nil, types.QueryResSubscriptions{subscription}},
+		{"product history", []string{QueryProductHistory, "book1"}, nil, nil, types.QueryResProductHistory{entry}},
+		{"has license", []string{QueryHasLicense, "book1", buyer.String()}, nil, nil,
+			types.QueryResHasLicense{HasLicense: true, License: license}},
+		{"has no license", []string{QueryHasLicense, "book1", seller.String()}, nil, nil, types.QueryResHasLicense{}},
+		{"license of bad address", []string{QueryHasLicense, "book1", "buyer"}, nil, sdkerrors.ErrInvalidAddress, nil},
+		{"license without holder", []string{QueryHasLicense, "book1"}, nil, sdkerrors.ErrUnknownRequest, nil},
+		{"unknown route", []string{"unknown"}, nil, sdkerrors.ErrUnknownRequest, nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := querier(ctx, tc.path, abci.RequestQuery{Data: tc.data})
+			if tc.expErr != nil {
+				require.True(t, errors.Is(err, tc.expErr), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			require.JSONEq(t, string(mustJSON(tc.expRes)), string(res))
+		})
+	}
+}
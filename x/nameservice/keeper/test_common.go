@@ -0,0 +1,126 @@
+package keeper
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/libs/log"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/cosmos-sdk/x/params"
+	"github.com/cosmos/cosmos-sdk/x/supply"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+	pricefeedkeeper "github.com/cosmos/sdk-tutorials/nameservice/x/pricefeed/keeper"
+	pricefeedtypes "github.com/cosmos/sdk-tutorials/nameservice/x/pricefeed/types"
+)
+
+// dummy addresses funded by CreateTestInput
+var Addrs = createTestAddrs(4)
+
+// InitCoins are the coins every address in Addrs starts with
+var InitCoins = sdk.NewCoins(sdk.NewInt64Coin("nametoken", 1000), sdk.NewInt64Coin(sdk.DefaultBondDenom, 1000))
+
+// TestInput holds the context and keepers the nameservice tests run against
+type TestInput struct {
+	Cdc             *codec.Codec
+	Ctx             sdk.Context
+	AccountKeeper   auth.AccountKeeper
+	BankKeeper      bank.Keeper
+	SupplyKeeper    supply.Keeper
+	PriceFeedKeeper pricefeedkeeper.Keeper
+	Keeper          Keeper
+}
+
+// MakeTestCodec creates a codec used only for testing
+func MakeTestCodec() *codec.Codec {
+	var cdc = codec.New()
+
+	auth.RegisterCodec(cdc)
+	bank.RegisterCodec(cdc)
+	supply.RegisterCodec(cdc)
+	types.RegisterCodec(cdc)
+	pricefeedtypes.RegisterCodec(cdc)
+	sdk.RegisterCodec(cdc)
+	codec.RegisterCrypto(cdc)
+
+	return cdc
+}
+
+// CreateTestInput mounts every store the nameservice keeper needs on an in-memory
+// database and funds each address in Addrs with InitCoins
+func CreateTestInput(t *testing.T) TestInput {
+	keyAcc := sdk.NewKVStoreKey(auth.StoreKey)
+	keyParams := sdk.NewKVStoreKey(params.StoreKey)
+	tkeyParams := sdk.NewTransientStoreKey(params.TStoreKey)
+	keySupply := sdk.NewKVStoreKey(supply.StoreKey)
+	keyPriceFeed := sdk.NewKVStoreKey(pricefeedtypes.StoreKey)
+	keyNameservice := sdk.NewKVStoreKey(types.StoreKey)
+
+	db := dbm.NewMemDB()
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(keyAcc, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keyParams, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(tkeyParams, sdk.StoreTypeTransient, db)
+	ms.MountStoreWithDB(keySupply, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keyPriceFeed, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(keyNameservice, sdk.StoreTypeIAVL, db)
+	require.NoError(t, ms.LoadLatestVersion())
+
+	ctx := sdk.NewContext(ms, abci.Header{ChainID: "nameservice-test", Height: 1}, false, log.NewNopLogger())
+	cdc := MakeTestCodec()
+
+	nameserviceAcc := supply.NewEmptyModuleAccount(types.ModuleName, supply.Burner)
+
+	blacklistedAddrs := make(map[string]bool)
+	blacklistedAddrs[nameserviceAcc.GetAddress().String()] = true
+
+	pk := params.NewKeeper(cdc, keyParams, tkeyParams)
+
+	accountKeeper := auth.NewAccountKeeper(cdc, keyAcc, pk.Subspace(auth.DefaultParamspace), auth.ProtoBaseAccount)
+	bankKeeper := bank.NewBaseKeeper(accountKeeper, pk.Subspace(bank.DefaultParamspace), blacklistedAddrs)
+
+	maccPerms := map[string][]string{
+		types.ModuleName: {supply.Burner},
+	}
+	supplyKeeper := supply.NewKeeper(cdc, keySupply, accountKeeper, bankKeeper, maccPerms)
+	supplyKeeper.SetModuleAccount(ctx, nameserviceAcc)
+
+	var totalSupply sdk.Coins
+	for _, addr := range Addrs {
+		_, err := bankKeeper.AddCoins(ctx, addr, InitCoins)
+		require.NoError(t, err)
+		totalSupply = totalSupply.Add(InitCoins...)
+	}
+	supplyKeeper.SetSupply(ctx, supply.NewSupply(totalSupply))
+
+	priceFeedKeeper := pricefeedkeeper.NewKeeper(cdc, keyPriceFeed, pk.Subspace(pricefeedtypes.DefaultParamspace))
+	priceFeedKeeper.SetParams(ctx, pricefeedtypes.DefaultParams())
+	keeper := NewKeeper(cdc, keyNameservice, bankKeeper, supplyKeeper, priceFeedKeeper)
+
+	return TestInput{
+		Cdc:             cdc,
+		Ctx:             ctx,
+		AccountKeeper:   accountKeeper,
+		BankKeeper:      bankKeeper,
+		SupplyKeeper:    supplyKeeper,
+		PriceFeedKeeper: priceFeedKeeper,
+		Keeper:          keeper,
+	}
+}
+
+func createTestAddrs(numAddrs int) []sdk.AccAddress {
+	addrs := make([]sdk.AccAddress, numAddrs)
+	for i := range addrs {
+		addrs[i] = sdk.AccAddress(crypto.AddressHash([]byte(fmt.Sprintf("addr%d", i))))
+	}
+	return addrs
+}
@@ -0,0 +1,101 @@
+package simulation
+
+import (
+	"bytes"
+	"fmt"
+
+	tmkv "github.com/tendermint/tendermint/libs/kv"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
+
+// DecodeStore unmarshals the KVPair's Value to the corresponding nameservice type
+func DecodeStore(cdc *codec.Codec, kvA, kvB tmkv.Pair) string {
+	hasPrefix := func(prefix string) bool {
+		return bytes.HasPrefix(kvA.Key, []byte(prefix))
+	}
+
+	switch {
+	case hasPrefix(types.WhoisPrefix):
+		var whoisA, whoisB types.Whois
+		cdc.MustUnmarshalBinaryBare(kvA.Value, &whoisA)
+		cdc.MustUnmarshalBinaryBare(kvB.Value, &whoisB)
+		return fmt.Sprintf("%v\n%v", whoisA, whoisB)
+
+	case hasPrefix(types.ProductPrefix):
+		var productA, productB types.Product
+		cdc.MustUnmarshalBinaryBare(kvA.Value, &productA)
+		cdc.MustUnmarshalBinaryBare(kvB.Value, &productB)
+		return fmt.Sprintf("%v\n%v", productA, productB)
+
+	case hasPrefix(types.CategoryPrefix), hasPrefix(types.TagPrefix), hasPrefix(types.StorefrontPrefix):
+		// index entries carry no value
+		return fmt.Sprintf("%s\n%s", kvA.Key, kvB.Key)
+
+	case hasPrefix(types.AuctionPrefix):
+		var auctionA, auctionB types.Auction
+		cdc.MustUnmarshalBinaryBare(kvA.Value, &auctionA)
+		cdc.MustUnmarshalBinaryBare(kvB.Value, &auctionB)
+		return fmt.Sprintf("%v\n%v", auctionA, auctionB)
+
+	case hasPrefix(types.PurchasePrefix):
+		var purchaseA, purchaseB types.Purchase
+		cdc.MustUnmarshalBinaryBare(kvA.Value, &purchaseA)
+		cdc.MustUnmarshalBinaryBare(kvB.Value, &purchaseB)
+		return fmt.Sprintf("%v\n%v", purchaseA, purchaseB)
+
+	case hasPrefix(types.ReviewPrefix):
+		var reviewA, reviewB types.Review
+		cdc.MustUnmarshalBinaryBare(kvA.Value, &reviewA)
+		cdc.MustUnmarshalBinaryBare(kvB.Value, &reviewB)
+		return fmt.Sprintf("%v\n%v", reviewA, reviewB)
+
+	case hasPrefix(types.ProductRatingPrefix), hasPrefix(types.SellerRatingPrefix):
+		var ratingA, ratingB types.Rating
+		cdc.MustUnmarshalBinaryBare(kvA.Value, &ratingA)
+		cdc.MustUnmarshalBinaryBare(kvB.Value, &ratingB)
+		return fmt.Sprintf("%v\n%v", ratingA, ratingB)
+
+	case hasPrefix(types.CouponPrefix):
+		var couponA, couponB types.Coupon
+		cdc.MustUnmarshalBinaryBare(kvA.Value, &couponA)
+		cdc.MustUnmarshalBinaryBare(kvB.Value, &couponB)
+		return fmt.Sprintf("%v\n%v", couponA, couponB)
+
+	case hasPrefix(types.CouponCommitmentPrefix):
+		var commitmentA, commitmentB types.CouponCommitment
+		cdc.MustUnmarshalBinaryBare(kvA.Value, &commitmentA)
+		cdc.MustUnmarshalBinaryBare(kvB.Value, &commitmentB)
+		return fmt.Sprintf("%v\n%v", commitmentA, commitmentB)
+
+	case hasPrefix(types.SubscriptionPrefix):
+		var subscriptionA, subscriptionB types.Subscription
+		cdc.MustUnmarshalBinaryBare(kvA.Value, &subscriptionA)
+		cdc.MustUnmarshalBinaryBare(kvB.Value, &subscriptionB)
+		return fmt.Sprintf("%v\n%v", subscriptionA, subscriptionB)
+
+	case hasPrefix(types.SubscriptionDuePrefix):
+		// due index entries carry the key of the subscription
+		return fmt.Sprintf("%s\n%s", kvA.Value, kvB.Value)
+
+	case hasPrefix(types.ProductHistoryPrefix):
+		var entryA, entryB types.ProductHistoryEntry
+		cdc.MustUnmarshalBinaryBare(kvA.Value, &entryA)
+		cdc.MustUnmarshalBinaryBare(kvB.Value, &entryB)
+		return fmt.Sprintf("%v\n%v", entryA, entryB)
+
+	case hasPrefix(types.LicensePrefix):
+		var licenseA, licenseB types.License
+		cdc.MustUnmarshalBinaryBare(kvA.Value, &licenseA)
+		cdc.MustUnmarshalBinaryBare(kvB.Value, &licenseB)
+		return fmt.Sprintf("%v\n%v", licenseA, licenseB)
+
+	case hasPrefix(types.LayoutPrefix):
+		return fmt.Sprintf("%v\n%v", kvA.Value, kvB.Value)
+
+	default:
+		panic(fmt.Sprintf("invalid %s key %s", types.ModuleName, kvA.Key))
+	}
+}
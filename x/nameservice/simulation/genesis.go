@@ -0,0 +1,127 @@
+package simulation
+
+// DONTCOVER
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/module"
+	"github.com/cosmos/cosmos-sdk/x/simulation"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
+
+// Simulation genesis constants
+const (
+	NumNames    = "num_names"
+	NumProducts = "num_products"
+)
+
+var (
+	// categories and tags the simulated products are labelled with
+	simCategories = []string{"", "books", "music", "software", "video"}
+	simTags       = []string{"new", "used", "sale", "digital", "rare", "bundle"}
+)
+
+// GenNumNames randomized number of names registered at genesis
+func GenNumNames(r *rand.Rand) int {
+	return r.Intn(20)
+}
+
+// GenNumProducts randomized number of products created at genesis
+func GenNumProducts(r *rand.Rand) int {
+	return r.Intn(30)
+}
+
+// RandomizedGenState generates a random GenesisState for nameservice
+func RandomizedGenState(simState *module.SimulationState) {
+	var numNames, numProducts int
+	simState.AppParams.GetOrGenerate(
+		simState.Cdc, NumNames, &numNames, simState.Rand,
+		func(r *rand.Rand) { numNames = GenNumNames(r) },
+	)
+
+	simState.AppParams.GetOrGenerate(
+		simState.Cdc, NumProducts, &numProducts, simState.Rand,
+		func(r *rand.Rand) { numProducts = GenNumProducts(r) },
+	)
+
+	r := simState.Rand
+
+	records := make([]types.WhoisRecord, 0, numNames)
+	names := make(map[string]bool)
+	for len(records) < numNames {
+		name := randomName(r)
+		if names[name] {
+			continue
+		}
+		names[name] = true
+
+		owner, _ := simulation.RandomAcc(r, simState.Accounts)
+		records = append(records, types.WhoisRecord{
+			Name: name,
+			Whois: types.Whois{
+				Value: simulation.RandStringOfLength(r, 20),
+				Owner: owner.Address,
+				Price: randomPrice(r),
+			},
+		})
+	}
+
+	products := make([]types.Product, 0, numProducts)
+	productIDs := make(map[string]bool)
+	for len(products) < numProducts {
+		productID := randomProductID(r)
+		if productIDs[productID] {
+			continue
+		}
+		productIDs[productID] = true
+
+		owner, _ := simulation.RandomAcc(r, simState.Accounts)
+		products = append(products, types.Product{
+			ProductID:   productID,
+			Description: simulation.RandStringOfLength(r, 40),
+			Owner:       owner.Address,
+			Price:       randomPrice(r),
+			Listed:      r.Intn(4) != 0,
+			Category:    randomCategory(r),
+			Tags:        randomTags(r),
+		})
+	}
+
+	nsGenesis := types.NewGenesisState(records, products, []types.ProductHistoryEntry{}, []types.Auction{},
+		[]types.Purchase{}, []types.Review{}, []types.Coupon{}, []types.Subscription{}, []types.License{},
+		[]types.CouponCommitment{})
+
+	fmt.Printf("Selected randomly generated nameservice state:\n%s\n", codec.MustMarshalJSONIndent(simState.Cdc, nsGenesis))
+	simState.GenState[types.ModuleName] = simState.Cdc.MustMarshalJSON(nsGenesis)
+}
+
+func randomName(r *rand.Rand) string {
+	return simulation.RandStringOfLength(r, simulation.RandIntBetween(r, 3, 12))
+}
+
+func randomProductID(r *rand.Rand) string {
+	return simulation.RandStringOfLength(r, simulation.RandIntBetween(r, 4, 16))
+}
+
+// randomPrice returns a small price in the bond denomination, the only one
+// held by the simulation accounts
+func randomPrice(r *rand.Rand) sdk.Coins {
+	return sdk.NewCoins(sdk.NewInt64Coin(sdk.DefaultBondDenom, int64(simulation.RandIntBetween(r, 1, 1000))))
+}
+
+func randomCategory(r *rand.Rand) string {
+	return simCategories[r.Intn(len(simCategories))]
+}
+
+func randomTags(r *rand.Rand) []string {
+	var tags []string
+	for _, i := range r.Perm(len(simTags))[:r.Intn(4)] {
+		tags = append(tags, simTags[i])
+	}
+	return tags
+}
@@ -0,0 +1,840 @@
+package simulation
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/simapp/helpers"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/simulation"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/keeper"
+	"github.com/cosmos/sdk-tutorials/nameservice/x/nameservice/types"
+)
+
+// Simulation operation weights constants
+const (
+	OpWeightMsgSetName                = "op_weight_msg_set_name"
+	OpWeightMsgBuyName                = "op_weight_msg_buy_name"
+	OpWeightMsgDeleteName             = "op_weight_msg_delete_name"
+	OpWeightMsgCreateProduct          = "op_weight_msg_create_product"
+	OpWeightMsgUpdateProduct          = "op_weight_msg_update_product"
+	OpWeightMsgDeleteProduct          = "op_weight_msg_delete_product"
+	OpWeightMsgBuyProduct             = "op_weight_msg_buy_product"
+	OpWeightMsgBuyProducts            = "op_weight_msg_buy_products"
+	OpWeightMsgListProduct            = "op_weight_msg_list_product"
+	OpWeightMsgDelistProduct          = "op_weight_msg_delist_product"
+	OpWeightMsgCreateAuction          = "op_weight_msg_create_auction"
+	OpWeightMsgPlaceBid               = "op_weight_msg_place_bid"
+	OpWeightMsgPublishProduct         = "op_weight_msg_publish_product"
+	OpWeightMsgUnpublishProduct       = "op_weight_msg_unpublish_product"
+	OpWeightMsgSetStorefrontSale      = "op_weight_msg_set_storefront_sale"
+	OpWeightMsgReviewProduct          = "op_weight_msg_review_product"
+	OpWeightMsgSetProductPricing      = "op_weight_msg_set_product_pricing"
+	OpWeightMsgCreateCoupon           = "op_weight_msg_create_coupon"
+	OpWeightMsgRevokeCoupon           = "op_weight_msg_revoke_coupon"
+	OpWeightMsgCommitCoupon           = "op_weight_msg_commit_coupon"
+	OpWeightMsgSetProductSubscription = "op_weight_msg_set_product_subscription"
+	OpWeightMsgCancelSubscription     = "op_weight_msg_cancel_subscription"
+	OpWeightMsgSetProductLicensing    = "op_weight_msg_set_product_licensing"
+)
+
+// Default simulation operation weights
+const (
+	DefaultWeightMsgSetName                = 40
+	DefaultWeightMsgBuyName                = 60
+	DefaultWeightMsgDeleteName             = 10
+	DefaultWeightMsgCreateProduct          = 80
+	DefaultWeightMsgUpdateProduct          = 30
+	DefaultWeightMsgDeleteProduct          = 10
+	DefaultWeightMsgBuyProduct             = 80
+	DefaultWeightMsgBuyProducts            = 30
+	DefaultWeightMsgListProduct            = 40
+	DefaultWeightMsgDelistProduct          = 15
+	DefaultWeightMsgCreateAuction          = 25
+	DefaultWeightMsgPlaceBid               = 50
+	DefaultWeightMsgPublishProduct         = 30
+	DefaultWeightMsgUnpublishProduct       = 10
+	DefaultWeightMsgSetStorefrontSale      = 20
+	DefaultWeightMsgReviewProduct          = 40
+	DefaultWeightMsgSetProductPricing      = 20
+	DefaultWeightMsgCreateCoupon           = 30
+	DefaultWeightMsgRevokeCoupon           = 10
+	DefaultWeightMsgCommitCoupon           = 30
+	DefaultWeightMsgSetProductSubscription = 20
+	DefaultWeightMsgCancelSubscription     = 15
+	DefaultWeightMsgSetProductLicensing    = 20
+)
+
+// simAcceptedDenom is the denomination of the alternative prices set by the simulation
+const simAcceptedDenom = "nametoken"
+
+// WeightedOperations returns all the operations from the module with their respective weights
+func WeightedOperations(appParams simulation.AppParams, cdc *codec.Codec, ak types.AccountKeeper,
+	k keeper.Keeper) simulation.WeightedOperations {
+
+	operations := []struct {
+		key           string
+		defaultWeight int
+		operation     simulation.Operation
+	}{
+		{OpWeightMsgSetName, DefaultWeightMsgSetName, SimulateMsgSetName(ak, k)},
+		{OpWeightMsgBuyName, DefaultWeightMsgBuyName, SimulateMsgBuyName(ak, k)},
+		{OpWeightMsgDeleteName, DefaultWeightMsgDeleteName, SimulateMsgDeleteName(ak, k)},
+		{OpWeightMsgCreateProduct, DefaultWeightMsgCreateProduct, SimulateMsgCreateProduct(ak, k)},
+		{OpWeightMsgUpdateProduct, DefaultWeightMsgUpdateProduct, SimulateMsgUpdateProduct(ak, k)},
+		{OpWeightMsgDeleteProduct, DefaultWeightMsgDeleteProduct, SimulateMsgDeleteProduct(ak, k)},
+		{OpWeightMsgBuyProduct, DefaultWeightMsgBuyProduct, SimulateMsgBuyProduct(ak, k)},
+		{OpWeightMsgBuyProducts, DefaultWeightMsgBuyProducts, SimulateMsgBuyProducts(ak, k)},
+		{OpWeightMsgListProduct, DefaultWeightMsgListProduct, SimulateMsgListProduct(ak, k)},
+		{OpWeightMsgDelistProduct, DefaultWeightMsgDelistProduct, SimulateMsgDelistProduct(ak, k)},
+		{OpWeightMsgCreateAuction, DefaultWeightMsgCreateAuction, SimulateMsgCreateAuction(ak, k)},
+		{OpWeightMsgPlaceBid, DefaultWeightMsgPlaceBid, SimulateMsgPlaceBid(ak, k)},
+		{OpWeightMsgPublishProduct, DefaultWeightMsgPublishProduct, SimulateMsgPublishProduct(ak, k)},
+		{OpWeightMsgUnpublishProduct, DefaultWeightMsgUnpublishProduct, SimulateMsgUnpublishProduct(ak, k)},
+		{OpWeightMsgSetStorefrontSale, DefaultWeightMsgSetStorefrontSale, SimulateMsgSetStorefrontSale(ak, k)},
+		{OpWeightMsgReviewProduct, DefaultWeightMsgReviewProduct, SimulateMsgReviewProduct(ak, k)},
+		{OpWeightMsgSetProductPricing, DefaultWeightMsgSetProductPricing, SimulateMsgSetProductPricing(ak, k)},
+		{OpWeightMsgCreateCoupon, DefaultWeightMsgCreateCoupon, SimulateMsgCreateCoupon(ak, k)},
+		{OpWeightMsgRevokeCoupon, DefaultWeightMsgRevokeCoupon, SimulateMsgRevokeCoupon(ak, k)},
+		{OpWeightMsgCommitCoupon, DefaultWeightMsgCommitCoupon, SimulateMsgCommitCoupon(ak, k)},
+		{OpWeightMsgSetProductSubscription, DefaultWeightMsgSetProductSubscription, SimulateMsgSetProductSubscription(ak, k)},
+		{OpWeightMsgCancelSubscription, DefaultWeightMsgCancelSubscription, SimulateMsgCancelSubscription(ak, k)},
+		{OpWeightMsgSetProductLicensing, DefaultWeightMsgSetProductLicensing, SimulateMsgSetProductLicensing(ak, k)},
+	}
+
+	weightedOperations := make(simulation.WeightedOperations, 0, len(operations))
+	for _, op := range operations {
+		var weight int
+		appParams.GetOrGenerate(cdc, op.key, &weight, nil,
+			func(_ *rand.Rand) {
+				weight = op.defaultWeight
+			},
+		)
+		weightedOperations = append(weightedOperations, simulation.NewWeightedOperation(weight, op.operation))
+	}
+
+	return weightedOperations
+}
+
+// SimulateMsgSetName sets the value of a name owned by a simulation account
+func SimulateMsgSetName(ak types.AccountKeeper, k keeper.Keeper) simulation.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context,
+		accs []simulation.Account, chainID string,
+	) (simulation.OperationMsg, []simulation.FutureOperation, error) {
+
+		name, _, owner, ok := randomOwnedName(r, ctx, k, accs, nil)
+		if !ok {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+
+		msg := types.NewMsgSetName(name, simulation.RandStringOfLength(r, 20), owner.Address)
+		return sendMsg(r, app, ak, ctx, chainID, msg, owner, nil)
+	}
+}
+
+// SimulateMsgBuyName buys a name from another simulation account or registers a new one
+func SimulateMsgBuyName(ak types.AccountKeeper, k keeper.Keeper) simulation.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context,
+		accs []simulation.Account, chainID string,
+	) (simulation.OperationMsg, []simulation.FutureOperation, error) {
+
+		buyer, _ := simulation.RandomAcc(r, accs)
+
+		name, whois, _, ok := randomOwnedName(r, ctx, k, accs, func(_ string, whois types.Whois) bool {
+			return !whois.Owner.Equals(buyer.Address)
+		})
+
+		var bid sdk.Coins
+		if ok && r.Intn(2) == 0 {
+			bid = whois.Price.Add(randomPrice(r)...)
+		} else {
+			name = randomName(r)
+			if k.IsNamePresent(ctx, name) {
+				return simulation.NoOpMsg(types.ModuleName), nil, nil
+			}
+			bid = randomPrice(r)
+		}
+
+		msg := types.NewMsgBuyName(name, bid, buyer.Address)
+		return sendMsg(r, app, ak, ctx, chainID, msg, buyer, bid)
+	}
+}
+
+// SimulateMsgDeleteName deletes a name owned by a simulation account
+func SimulateMsgDeleteName(ak types.AccountKeeper, k keeper.Keeper) simulation.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context,
+		accs []simulation.Account, chainID string,
+	) (simulation.OperationMsg, []simulation.FutureOperation, error) {
+
+		name, _, owner, ok := randomOwnedName(r, ctx, k, accs, nil)
+		if !ok {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+
+		msg := types.NewMsgDeleteName(name, owner.Address)
+		return sendMsg(r, app, ak, ctx, chainID, msg, owner, nil)
+	}
+}
+
+// SimulateMsgCreateProduct creates a product with a new random ID
+func SimulateMsgCreateProduct(ak types.AccountKeeper, k keeper.Keeper) simulation.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context,
+		accs []simulation.Account, chainID string,
+	) (simulation.OperationMsg, []simulation.FutureOperation, error) {
+
+		owner, _ := simulation.RandomAcc(r, accs)
+
+		productID := randomProductID(r)
+		if k.IsProductPresent(ctx, types.ProductPrefix+productID) {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+
+		msg := types.NewMsgCreateProduct(productID, simulation.RandStringOfLength(r, 40), randomPrice(r),
+			randomCategory(r), randomTags(r), types.Content{}, owner.Address)
+		return sendMsg(r, app, ak, ctx, chainID, msg, owner, nil)
+	}
+}
+
+// SimulateMsgUpdateProduct changes the terms of a product that is not in auction
+func SimulateMsgUpdateProduct(ak types.AccountKeeper, k keeper.Keeper) simulation.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context,
+		accs []simulation.Account, chainID string,
+	) (simulation.OperationMsg, []simulation.FutureOperation, error) {
+
+		product, owner, ok := randomOwnedProduct(r, ctx, k, accs, func(product types.Product) bool {
+			return !k.IsAuctionPresent(ctx, product.ProductID)
+		})
+		if !ok {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+
+		msg := types.NewMsgUpdateProduct(product.ProductID, simulation.RandStringOfLength(r, 40), randomPrice(r),
+			randomCategory(r), randomTags(r), product.Content, owner.Address)
+		return sendMsg(r, app, ak, ctx, chainID, msg, owner, nil)
+	}
+}
+
+// SimulateMsgDeleteProduct deletes a product that is not in auction
+func SimulateMsgDeleteProduct(ak types.AccountKeeper, k keeper.Keeper) simulation.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context,
+		accs []simulation.Account, chainID string,
+	) (simulation.OperationMsg, []simulation.FutureOperation, error) {
+
+		product, owner, ok := randomOwnedProduct(r, ctx, k, accs, func(product types.Product) bool {
+			return !k.IsAuctionPresent(ctx, product.ProductID)
+		})
+		if !ok {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+
+		msg := types.NewMsgDeleteProduct(product.ProductID, owner.Address)
+		return sendMsg(r, app, ak, ctx, chainID, msg, owner, nil)
+	}
+}
+
+// SimulateMsgBuyProduct buys a listed product, with one of the coupons of the
+// product when the buyer committed to a redeemable one
+func SimulateMsgBuyProduct(ak types.AccountKeeper, k keeper.Keeper) simulation.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context,
+		accs []simulation.Account, chainID string,
+	) (simulation.OperationMsg, []simulation.FutureOperation, error) {
+
+		buyer, _ := simulation.RandomAcc(r, accs)
+
+		candidates := buyableProducts(ctx, k, buyer.Address)
+		if len(candidates) == 0 {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+		product := candidates[r.Intn(len(candidates))]
+
+		// the undiscounted price is set aside, which covers any coupon discount
+		price, _ := k.GetProductPrice(ctx, product, "")
+
+		code := couponCode(product.ProductID, r.Intn(simCouponsPerProduct))
+		if _, err := k.GetRedeemableCoupon(ctx, code, buyer.Address, product); err != nil {
+			code = ""
+		}
+
+		msg := types.NewMsgBuyProduct(product.ProductID, "", code, product.Version, buyer.Address)
+		return sendMsg(r, app, ak, ctx, chainID, msg, buyer, price)
+	}
+}
+
+// SimulateMsgBuyProducts buys a cart of up to three listed products
+func SimulateMsgBuyProducts(ak types.AccountKeeper, k keeper.Keeper) simulation.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context,
+		accs []simulation.Account, chainID string,
+	) (simulation.OperationMsg, []simulation.FutureOperation, error) {
+
+		buyer, _ := simulation.RandomAcc(r, accs)
+
+		candidates := buyableProducts(ctx, k, buyer.Address)
+		if len(candidates) == 0 {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+
+		numItems := simulation.RandIntBetween(r, 1, 4)
+		if numItems > len(candidates) {
+			numItems = len(candidates)
+		}
+
+		var total sdk.Coins
+		items := make([]types.CartItem, numItems)
+		for i, j := range r.Perm(len(candidates))[:numItems] {
+			product := candidates[j]
+			price, _ := k.GetProductPrice(ctx, product, "")
+			total = total.Add(price...)
+			items[i] = types.NewCartItem(product.ProductID, "", "", product.Version)
+		}
+
+		msg := types.NewMsgBuyProducts(items, buyer.Address)
+		return sendMsg(r, app, ak, ctx, chainID, msg, buyer, total)
+	}
+}
+
+// SimulateMsgListProduct puts an unlisted product up for sale
+func SimulateMsgListProduct(ak types.AccountKeeper, k keeper.Keeper) simulation.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context,
+		accs []simulation.Account, chainID string,
+	) (simulation.OperationMsg, []simulation.FutureOperation, error) {
+
+		product, owner, ok := randomOwnedProduct(r, ctx, k, accs, func(product types.Product) bool {
+			return !product.Listed && !k.IsAuctionPresent(ctx, product.ProductID)
+		})
+		if !ok {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+
+		msg := types.NewMsgListProduct(product.ProductID, owner.Address)
+		return sendMsg(r, app, ak, ctx, chainID, msg, owner, nil)
+	}
+}
+
+// SimulateMsgDelistProduct withdraws a listed product from sale
+func SimulateMsgDelistProduct(ak types.AccountKeeper, k keeper.Keeper) simulation.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context,
+		accs []simulation.Account, chainID string,
+	) (simulation.OperationMsg, []simulation.FutureOperation, error) {
+
+		product, owner, ok := randomOwnedProduct(r, ctx, k, accs, func(product types.Product) bool {
+			return product.Listed
+		})
+		if !ok {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+
+		msg := types.NewMsgDelistProduct(product.ProductID, owner.Address)
+		return sendMsg(r, app, ak, ctx, chainID, msg, owner, nil)
+	}
+}
+
+// SimulateMsgCreateAuction puts a one-shot product up for an english or dutch auction
+func SimulateMsgCreateAuction(ak types.AccountKeeper, k keeper.Keeper) simulation.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context,
+		accs []simulation.Account, chainID string,
+	) (simulation.OperationMsg, []simulation.FutureOperation, error) {
+
+		product, owner, ok := randomOwnedProduct(r, ctx, k, accs, func(product types.Product) bool {
+			return !k.IsAuctionPresent(ctx, product.ProductID) && !product.IsSubscription() && !product.Licensing
+		})
+		if !ok {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+
+		startPrice := randomPrice(r)
+		duration := int64(simulation.RandIntBetween(r, 1, 20))
+
+		var msg types.MsgCreateAuction
+		if r.Intn(2) == 0 {
+			msg = types.NewMsgCreateAuction(product.ProductID, types.AuctionEnglish, startPrice, nil, nil, duration, owner.Address)
+		} else {
+			start := startPrice.AmountOf(sdk.DefaultBondDenom)
+			reserve := sdk.NewCoins(sdk.NewCoin(sdk.DefaultBondDenom, simulation.RandomAmount(r, start)))
+			decrement := sdk.NewCoins(sdk.NewInt64Coin(sdk.DefaultBondDenom, int64(simulation.RandIntBetween(r, 1, 100))))
+			msg = types.NewMsgCreateAuction(product.ProductID, types.AuctionDutch, startPrice, reserve, decrement, duration, owner.Address)
+		}
+
+		return sendMsg(r, app, ak, ctx, chainID, msg, owner, nil)
+	}
+}
+
+// SimulateMsgPlaceBid bids on a running auction. English bids outbid the highest
+// bid, dutch bids pay the current price.
+func SimulateMsgPlaceBid(ak types.AccountKeeper, k keeper.Keeper) simulation.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context,
+		accs []simulation.Account, chainID string,
+	) (simulation.OperationMsg, []simulation.FutureOperation, error) {
+
+		bidder, _ := simulation.RandomAcc(r, accs)
+
+		var auctions []types.Auction
+		iterator := k.GetAuctionsIterator(ctx)
+		for ; iterator.Valid(); iterator.Next() {
+			auction := k.GetAuction(ctx, strings.TrimPrefix(string(iterator.Key()), types.AuctionPrefix))
+			if !auction.IsFinished(ctx.BlockHeight()) && !auction.Seller.Equals(bidder.Address) {
+				auctions = append(auctions, auction)
+			}
+		}
+		iterator.Close()
+
+		if len(auctions) == 0 {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+		auction := auctions[r.Intn(len(auctions))]
+
+		amount := auction.CurrentPrice(ctx.BlockHeight())
+		if highest, ok := auction.HighestBid(); ok && auction.AuctionType == types.AuctionEnglish {
+			amount = highest.Amount.Add(randomPrice(r)...)
+		}
+		if !amount.IsAllPositive() {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+
+		msg := types.NewMsgPlaceBid(auction.ProductID, amount, bidder.Address)
+		return sendMsg(r, app, ak, ctx, chainID, msg, bidder, amount)
+	}
+}
+
+// SimulateMsgPublishProduct publishes a product in the storefront of a name of its owner
+func SimulateMsgPublishProduct(ak types.AccountKeeper, k keeper.Keeper) simulation.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context,
+		accs []simulation.Account, chainID string,
+	) (simulation.OperationMsg, []simulation.FutureOperation, error) {
+
+		sellers := make(map[string]bool)
+		for _, product := range getProducts(ctx, k) {
+			sellers[product.Owner.String()] = true
+		}
+
+		// names are scarcer than products, so the storefront is picked first
+		name, _, owner, ok := randomOwnedName(r, ctx, k, accs, func(_ string, whois types.Whois) bool {
+			return sellers[whois.Owner.String()]
+		})
+		if !ok {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+
+		product, _, ok := randomOwnedProduct(r, ctx, k, accs, func(product types.Product) bool {
+			return product.Owner.Equals(owner.Address) && product.Storefront != name
+		})
+		if !ok {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+
+		msg := types.NewMsgPublishProduct(name, product.ProductID, owner.Address)
+		return sendMsg(r, app, ak, ctx, chainID, msg, owner, nil)
+	}
+}
+
+// SimulateMsgUnpublishProduct removes a product from its storefront
+func SimulateMsgUnpublishProduct(ak types.AccountKeeper, k keeper.Keeper) simulation.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context,
+		accs []simulation.Account, chainID string,
+	) (simulation.OperationMsg, []simulation.FutureOperation, error) {
+
+		product, owner, ok := randomOwnedProduct(r, ctx, k, accs, func(product types.Product) bool {
+			return product.Storefront != ""
+		})
+		if !ok {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+
+		msg := types.NewMsgUnpublishProduct(product.ProductID, owner.Address)
+		return sendMsg(r, app, ak, ctx, chainID, msg, owner, nil)
+	}
+}
+
+// SimulateMsgSetStorefrontSale chooses whether a name is sold along with its storefront
+func SimulateMsgSetStorefrontSale(ak types.AccountKeeper, k keeper.Keeper) simulation.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context,
+		accs []simulation.Account, chainID string,
+	) (simulation.OperationMsg, []simulation.FutureOperation, error) {
+
+		name, _, owner, ok := randomOwnedName(r, ctx, k, accs, nil)
+		if !ok {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+
+		msg := types.NewMsgSetStorefrontSale(name, r.Intn(2) == 0, owner.Address)
+		return sendMsg(r, app, ak, ctx, chainID, msg, owner, nil)
+	}
+}
+
+// SimulateMsgReviewProduct reviews a product purchased by a simulation account
+func SimulateMsgReviewProduct(ak types.AccountKeeper, k keeper.Keeper) simulation.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context,
+		accs []simulation.Account, chainID string,
+	) (simulation.OperationMsg, []simulation.FutureOperation, error) {
+
+		type candidate struct {
+			purchase types.Purchase
+			buyer    simulation.Account
+		}
+
+		var candidates []candidate
+		iterator := k.GetPurchasesIterator(ctx)
+		for ; iterator.Valid(); iterator.Next() {
+			var purchase types.Purchase
+			types.ModuleCdc.MustUnmarshalBinaryBare(iterator.Value(), &purchase)
+
+			buyer, found := simulation.FindAccount(accs, purchase.Buyer)
+			if found && !k.HasReview(ctx, purchase.ProductID, purchase.Buyer) {
+				candidates = append(candidates, candidate{purchase, buyer})
+			}
+		}
+		iterator.Close()
+
+		if len(candidates) == 0 {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+		c := candidates[r.Intn(len(candidates))]
+
+		rating := uint8(simulation.RandIntBetween(r, types.MinRating, types.MaxRating+1))
+		msg := types.NewMsgReviewProduct(c.purchase.ProductID, rating, simulation.RandStringOfLength(r, r.Intn(80)), c.buyer.Address)
+		return sendMsg(r, app, ak, ctx, chainID, msg, c.buyer, nil)
+	}
+}
+
+// SimulateMsgSetProductPricing sets or clears the alternative price of a product.
+// Prices are never pegged as the simulation runs without price feeders.
+func SimulateMsgSetProductPricing(ak types.AccountKeeper, k keeper.Keeper) simulation.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context,
+		accs []simulation.Account, chainID string,
+	) (simulation.OperationMsg, []simulation.FutureOperation, error) {
+
+		product, owner, ok := randomOwnedProduct(r, ctx, k, accs, nil)
+		if !ok {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+
+		var acceptedPrices sdk.Coins
+		if r.Intn(2) == 0 {
+			acceptedPrices = sdk.NewCoins(sdk.NewInt64Coin(simAcceptedDenom, int64(simulation.RandIntBetween(r, 1, 1000))))
+		}
+
+		msg := types.NewMsgSetProductPricing(product.ProductID, acceptedPrices, nil, owner.Address)
+		return sendMsg(r, app, ak, ctx, chainID, msg, owner, nil)
+	}
+}
+
+// SimulateMsgCreateCoupon creates a percentage or fixed discount coupon for a product
+func SimulateMsgCreateCoupon(ak types.AccountKeeper, k keeper.Keeper) simulation.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context,
+		accs []simulation.Account, chainID string,
+	) (simulation.OperationMsg, []simulation.FutureOperation, error) {
+
+		product, owner, ok := randomOwnedProduct(r, ctx, k, accs, nil)
+		if !ok {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+
+		codeHash := types.HashCouponCode(couponCode(product.ProductID, r.Intn(simCouponsPerProduct)))
+		if _, found := k.GetCoupon(ctx, codeHash); found {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+
+		var percent uint64
+		var amount sdk.Coins
+		if r.Intn(2) == 0 {
+			percent = uint64(simulation.RandIntBetween(r, 1, types.MaxCouponPercent+1))
+		} else {
+			amount = randomPrice(r)
+		}
+
+		var expiryHeight int64
+		if r.Intn(2) == 0 {
+			expiryHeight = ctx.BlockHeight() + int64(simulation.RandIntBetween(r, 1, 50))
+		}
+
+		msg := types.NewMsgCreateCoupon(codeHash, product.ProductID, percent, amount, expiryHeight,
+			uint64(r.Intn(5)), owner.Address)
+		return sendMsg(r, app, ak, ctx, chainID, msg, owner, nil)
+	}
+}
+
+// SimulateMsgRevokeCoupon revokes a coupon issued by a simulation account
+func SimulateMsgRevokeCoupon(ak types.AccountKeeper, k keeper.Keeper) simulation.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context,
+		accs []simulation.Account, chainID string,
+	) (simulation.OperationMsg, []simulation.FutureOperation, error) {
+
+		type candidate struct {
+			coupon types.Coupon
+			issuer simulation.Account
+		}
+
+		var candidates []candidate
+		iterator := k.GetCouponsIterator(ctx)
+		for ; iterator.Valid(); iterator.Next() {
+			var coupon types.Coupon
+			types.ModuleCdc.MustUnmarshalBinaryBare(iterator.Value(), &coupon)
+
+			if issuer, found := simulation.FindAccount(accs, coupon.Issuer); found {
+				candidates = append(candidates, candidate{coupon, issuer})
+			}
+		}
+		iterator.Close()
+
+		if len(candidates) == 0 {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+		c := candidates[r.Intn(len(candidates))]
+
+		msg := types.NewMsgRevokeCoupon(c.coupon.CodeHash, c.issuer.Address)
+		return sendMsg(r, app, ak, ctx, chainID, msg, c.issuer, nil)
+	}
+}
+
+// SimulateMsgCommitCoupon commits a buyer to one of the coupons of a listed product
+func SimulateMsgCommitCoupon(ak types.AccountKeeper, k keeper.Keeper) simulation.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context,
+		accs []simulation.Account, chainID string,
+	) (simulation.OperationMsg, []simulation.FutureOperation, error) {
+
+		buyer, _ := simulation.RandomAcc(r, accs)
+
+		candidates := buyableProducts(ctx, k, buyer.Address)
+		if len(candidates) == 0 {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+		product := candidates[r.Intn(len(candidates))]
+
+		code := couponCode(product.ProductID, r.Intn(simCouponsPerProduct))
+		codeHash := types.HashCouponCode(code)
+		commitment := types.HashCouponCommitment(code, buyer.Address)
+		if _, found := k.GetCoupon(ctx, codeHash); !found {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+		if _, found := k.GetCouponCommitment(ctx, codeHash, commitment); found {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+
+		msg := types.NewMsgCommitCoupon(codeHash, commitment, buyer.Address)
+		return sendMsg(r, app, ak, ctx, chainID, msg, buyer, nil)
+	}
+}
+
+// SimulateMsgSetProductSubscription sells a product by subscription or as a one-shot sale again
+func SimulateMsgSetProductSubscription(ak types.AccountKeeper, k keeper.Keeper) simulation.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context,
+		accs []simulation.Account, chainID string,
+	) (simulation.OperationMsg, []simulation.FutureOperation, error) {
+
+		product, owner, ok := randomOwnedProduct(r, ctx, k, accs, func(product types.Product) bool {
+			return !k.IsAuctionPresent(ctx, product.ProductID)
+		})
+		if !ok {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+
+		var period int64
+		if !product.Licensing && r.Intn(3) != 0 {
+			period = int64(simulation.RandIntBetween(r, 1, 20))
+		}
+
+		msg := types.NewMsgSetProductSubscription(product.ProductID, period, owner.Address)
+		return sendMsg(r, app, ak, ctx, chainID, msg, owner, nil)
+	}
+}
+
+// SimulateMsgCancelSubscription cancels a running subscription of a simulation account
+func SimulateMsgCancelSubscription(ak types.AccountKeeper, k keeper.Keeper) simulation.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context,
+		accs []simulation.Account, chainID string,
+	) (simulation.OperationMsg, []simulation.FutureOperation, error) {
+
+		type candidate struct {
+			subscription types.Subscription
+			subscriber   simulation.Account
+		}
+
+		var candidates []candidate
+		iterator := k.GetSubscriptionsIterator(ctx)
+		for ; iterator.Valid(); iterator.Next() {
+			var subscription types.Subscription
+			types.ModuleCdc.MustUnmarshalBinaryBare(iterator.Value(), &subscription)
+
+			subscriber, found := simulation.FindAccount(accs, subscription.Subscriber)
+			if found && !subscription.Cancelled {
+				candidates = append(candidates, candidate{subscription, subscriber})
+			}
+		}
+		iterator.Close()
+
+		if len(candidates) == 0 {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+		c := candidates[r.Intn(len(candidates))]
+
+		msg := types.NewMsgCancelSubscription(c.subscription.ProductID, c.subscriber.Address)
+		return sendMsg(r, app, ak, ctx, chainID, msg, c.subscriber, nil)
+	}
+}
+
+// SimulateMsgSetProductLicensing sells perpetual or timed licenses of a product, or stops doing so
+func SimulateMsgSetProductLicensing(ak types.AccountKeeper, k keeper.Keeper) simulation.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context,
+		accs []simulation.Account, chainID string,
+	) (simulation.OperationMsg, []simulation.FutureOperation, error) {
+
+		product, owner, ok := randomOwnedProduct(r, ctx, k, accs, func(product types.Product) bool {
+			return !k.IsAuctionPresent(ctx, product.ProductID)
+		})
+		if !ok {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+
+		licensing := !product.IsSubscription() && r.Intn(3) != 0
+
+		var duration int64
+		if licensing && r.Intn(2) == 0 {
+			duration = int64(simulation.RandIntBetween(r, 1, 50))
+		}
+
+		msg := types.NewMsgSetProductLicensing(product.ProductID, licensing, duration, owner.Address)
+		return sendMsg(r, app, ak, ctx, chainID, msg, owner, nil)
+	}
+}
+
+// sendMsg delivers a transaction with a single msg signed by the simulation account.
+// The fees are picked among the spendable coins left once the amount the msg
+// spends is set aside, the operation is skipped when the account cannot afford it.
+func sendMsg(
+	r *rand.Rand, app *baseapp.BaseApp, ak types.AccountKeeper, ctx sdk.Context, chainID string,
+	msg sdk.Msg, simAccount simulation.Account, spent sdk.Coins,
+) (simulation.OperationMsg, []simulation.FutureOperation, error) {
+
+	account := ak.GetAccount(ctx, simAccount.Address)
+	coins, hasNeg := account.SpendableCoins(ctx.BlockTime()).SafeSub(spent)
+	if hasNeg {
+		return simulation.NoOpMsg(types.ModuleName), nil, nil
+	}
+
+	fees, err := simulation.RandomFees(r, ctx, coins)
+	if err != nil {
+		return simulation.NoOpMsg(types.ModuleName), nil, err
+	}
+
+	tx := helpers.GenTx(
+		[]sdk.Msg{msg},
+		fees,
+		helpers.DefaultGenTxGas,
+		chainID,
+		[]uint64{account.GetAccountNumber()},
+		[]uint64{account.GetSequence()},
+		simAccount.PrivKey,
+	)
+
+	_, _, err = app.Deliver(tx)
+	if err != nil {
+		return simulation.NoOpMsg(types.ModuleName), nil, err
+	}
+
+	return simulation.NewOperationMsg(msg, true, ""), nil, nil
+}
+
+// simCouponsPerProduct is the number of coupon codes the simulation uses per product
+const simCouponsPerProduct = 3
+
+// couponCode returns one of the coupon codes the simulation uses for a product,
+// derived from the product so buyers can redeem coupons they did not create
+func couponCode(productID string, i int) string {
+	return fmt.Sprintf("%s-coupon-%d", productID, i)
+}
+
+// randomOwnedName picks a name owned by a simulation account and accepted by the filter
+func randomOwnedName(
+	r *rand.Rand, ctx sdk.Context, k keeper.Keeper, accs []simulation.Account,
+	filter func(name string, whois types.Whois) bool,
+) (string, types.Whois, simulation.Account, bool) {
+
+	var names []string
+	iterator := k.GetNamesIterator(ctx)
+	for ; iterator.Valid(); iterator.Next() {
+		names = append(names, strings.TrimPrefix(string(iterator.Key()), types.WhoisPrefix))
+	}
+	iterator.Close()
+
+	for _, i := range r.Perm(len(names)) {
+		whois := k.GetWhois(ctx, names[i])
+		owner, found := simulation.FindAccount(accs, whois.Owner)
+		if found && (filter == nil || filter(names[i], whois)) {
+			return names[i], whois, owner, true
+		}
+	}
+	return "", types.Whois{}, simulation.Account{}, false
+}
+
+// randomOwnedProduct picks a product owned by a simulation account and accepted by the filter
+func randomOwnedProduct(
+	r *rand.Rand, ctx sdk.Context, k keeper.Keeper, accs []simulation.Account,
+	filter func(product types.Product) bool,
+) (types.Product, simulation.Account, bool) {
+
+	products := getProducts(ctx, k)
+	for _, i := range r.Perm(len(products)) {
+		owner, found := simulation.FindAccount(accs, products[i].Owner)
+		if found && (filter == nil || filter(products[i])) {
+			return products[i], owner, true
+		}
+	}
+	return types.Product{}, simulation.Account{}, false
+}
+
+// buyableProducts returns the products the buyer can buy at their listed price
+func buyableProducts(ctx sdk.Context, k keeper.Keeper, buyer sdk.AccAddress) []types.Product {
+	var buyable []types.Product
+	for _, product := range getProducts(ctx, k) {
+		if !product.Listed || product.Owner.Equals(buyer) {
+			continue
+		}
+		if subscription, found := k.GetSubscription(ctx, product.ProductID, buyer); found && !subscription.Cancelled {
+			continue
+		}
+		if license, found := k.GetLicense(ctx, product.ProductID, buyer); found && license.IsPerpetual() {
+			continue
+		}
+		if _, err := k.GetProductPrice(ctx, product, ""); err != nil {
+			continue
+		}
+		buyable = append(buyable, product)
+	}
+	return buyable
+}
+
+func getProducts(ctx sdk.Context, k keeper.Keeper) []types.Product {
+	var products []types.Product
+	iterator := k.GetProductsIterator(ctx)
+	for ; iterator.Valid(); iterator.Next() {
+		products = append(products, k.GetProduct(ctx, string(iterator.Key())))
+	}
+	iterator.Close()
+	return products
+}
@@ -21,4 +21,28 @@ func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(MsgUpdateProduct{}, "nameservice/UpdateProduct", nil)
 	cdc.RegisterConcrete(MsgDeleteProduct{}, "nameservice/DeleteProduct", nil)
 	cdc.RegisterConcrete(MsgBuyProduct{}, "nameservice/BuyProduct", nil)
+	cdc.RegisterConcrete(MsgListProduct{}, "nameservice/ListProduct", nil)
+	cdc.RegisterConcrete(MsgDelistProduct{}, "nameservice/DelistProduct", nil)
+
+	cdc.RegisterConcrete(MsgCreateAuction{}, "nameservice/CreateAuction", nil)
+	cdc.RegisterConcrete(MsgPlaceBid{}, "nameservice/PlaceBid", nil)
+
+	cdc.RegisterConcrete(MsgPublishProduct{}, "nameservice/PublishProduct", nil)
+	cdc.RegisterConcrete(MsgUnpublishProduct{}, "nameservice/UnpublishProduct", nil)
+	cdc.RegisterConcrete(MsgSetStorefrontSale{}, "nameservice/SetStorefrontSale", nil)
+
+	cdc.RegisterConcrete(MsgReviewProduct{}, "nameservice/ReviewProduct", nil)
+
+	cdc.RegisterConcrete(MsgSetProductPricing{}, "nameservice/SetProductPricing", nil)
+
+	cdc.RegisterConcrete(MsgCreateCoupon{}, "nameservice/CreateCoupon", nil)
+	cdc.RegisterConcrete(MsgRevokeCoupon{}, "nameservice/RevokeCoupon", nil)
+	cdc.RegisterConcrete(MsgCommitCoupon{}, "nameservice/CommitCoupon", nil)
+
+	cdc.RegisterConcrete(MsgSetProductSubscription{}, "nameservice/SetProductSubscription", nil)
+	cdc.RegisterConcrete(MsgCancelSubscription{}, "nameservice/CancelSubscription", nil)
+
+	cdc.RegisterConcrete(MsgBuyProducts{}, "nameservice/BuyProducts", nil)
+
+	cdc.RegisterConcrete(MsgSetProductLicensing{}, "nameservice/SetProductLicensing", nil)
 }
@@ -9,4 +9,37 @@ var (
 
 	ErrProductDoesNotExist  = sdkerrors.Register(ModuleName, 2, "product does not exist")
 	ErrProductAlreadyExists = sdkerrors.Register(ModuleName, 3, "product already exists")
+	ErrProductNotForSale    = sdkerrors.Register(ModuleName, 4, "product is not for sale")
+
+	ErrAuctionDoesNotExist  = sdkerrors.Register(ModuleName, 5, "auction does not exist")
+	ErrAuctionAlreadyExists = sdkerrors.Register(ModuleName, 6, "product is already in auction")
+	ErrAuctionFinished      = sdkerrors.Register(ModuleName, 7, "auction is finished")
+	ErrBidTooLow            = sdkerrors.Register(ModuleName, 8, "bid is too low")
+
+	ErrProductNotPublished = sdkerrors.Register(ModuleName, 9, "product is not published in a storefront")
+
+	ErrInvalidContent = sdkerrors.Register(ModuleName, 10, "invalid product content")
+
+	ErrNotPurchased    = sdkerrors.Register(ModuleName, 11, "product was not purchased by reviewer")
+	ErrAlreadyReviewed = sdkerrors.Register(ModuleName, 12, "product was already reviewed by reviewer")
+
+	ErrDenomNotAccepted = sdkerrors.Register(ModuleName, 13, "denomination is not accepted for product")
+	ErrPriceUnavailable = sdkerrors.Register(ModuleName, 14, "no price feed for denomination")
+
+	ErrCouponDoesNotExist  = sdkerrors.Register(ModuleName, 15, "coupon does not exist")
+	ErrCouponAlreadyExists = sdkerrors.Register(ModuleName, 16, "coupon already exists")
+	ErrCouponNotApplicable = sdkerrors.Register(ModuleName, 17, "coupon cannot be applied")
+
+	ErrAlreadySubscribed        = sdkerrors.Register(ModuleName, 18, "already subscribed to product")
+	ErrSubscriptionDoesNotExist = sdkerrors.Register(ModuleName, 19, "subscription does not exist")
+	ErrSubscriptionProduct      = sdkerrors.Register(ModuleName, 20, "product is sold by subscription")
+
+	ErrProductVersionMismatch = sdkerrors.Register(ModuleName, 21, "product was updated since the expected version")
+
+	ErrAlreadyLicensed = sdkerrors.Register(ModuleName, 22, "buyer already holds a perpetual license")
+	ErrLicensedProduct = sdkerrors.Register(ModuleName, 23, "product is sold by license")
+
+	ErrPriceStale = sdkerrors.Register(ModuleName, 24, "price feed is stale")
+
+	ErrCouponNotCommitted = sdkerrors.Register(ModuleName, 25, "coupon code was not committed to in an earlier block")
 )
@@ -0,0 +1,50 @@
+package types
+
+// nameservice module event types
+const (
+	// name_change and product_change events are emitted whenever the record of a name
+	// or of a product is written or deleted, by a message or at the end of a block
+	EventTypeNameChange    = "name_change"
+	EventTypeProductChange = "product_change"
+
+	EventTypeBuyProducts = "buy_products"
+
+	EventTypeCloseAuction = "close_auction"
+
+	EventTypeCreateCoupon = "create_coupon"
+	EventTypeRevokeCoupon = "revoke_coupon"
+	EventTypeRedeemCoupon = "redeem_coupon"
+	EventTypeCommitCoupon = "commit_coupon"
+
+	EventTypeSubscribe          = "subscribe"
+	EventTypeRenewSubscription  = "renew_subscription"
+	EventTypeCancelSubscription = "cancel_subscription"
+
+	EventTypeGrantLicense = "grant_license"
+
+	AttributeKeyName          = "name"
+	AttributeKeyOwner         = "owner"
+	AttributeKeyPreviousOwner = "previous_owner"
+	AttributeKeyStorefront    = "storefront"
+
+	AttributeKeyCouponHash = "coupon_hash"
+	AttributeKeyIssuer     = "issuer"
+	AttributeKeyProductID  = "product_id"
+	AttributeKeyProductIDs = "product_ids"
+	AttributeKeyBuyer      = "buyer"
+	AttributeKeyDiscount   = "discount"
+	AttributeKeySubscriber = "subscriber"
+	AttributeKeyPaidUntil  = "paid_until"
+	AttributeKeyReason     = "reason"
+	AttributeKeyHolder     = "holder"
+	AttributeKeyExpiry     = "expiry_height"
+	AttributeKeySeller     = "seller"
+	AttributeKeyBidder     = "bidder"
+
+	AttributeValueCategory = ModuleName
+
+	AttributeValueCancelledBySubscriber = "cancelled_by_subscriber"
+	AttributeValueInsufficientFunds     = "insufficient_funds"
+	AttributeValueProductUnavailable    = "product_unavailable"
+	AttributeValuePayoutFailed          = "payout_failed"
+)
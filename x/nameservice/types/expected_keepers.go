@@ -2,11 +2,33 @@ package types
 
 import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	authexported "github.com/cosmos/cosmos-sdk/x/auth/exported"
 )
 
 // When a module wishes to interact with an otehr module it is good practice to define what it will use
 // as an interface so the module can not use things that are not permitted.
 type BankKeeper interface {
-	SubtractCoins(ctx sdk.Context, addr sdk.AccAddress, amt sdk.Coins) (sdk.Coins, error)
+	GetCoins(ctx sdk.Context, addr sdk.AccAddress) sdk.Coins
 	SendCoins(ctx sdk.Context, fromAddr sdk.AccAddress, toAddr sdk.AccAddress, amt sdk.Coins) error
 }
+
+// SupplyKeeper is used to hold auction bids in escrow on the module account
+// and to burn the price of names bought for the first time
+type SupplyKeeper interface {
+	GetModuleAddress(moduleName string) sdk.AccAddress
+	SendCoinsFromAccountToModule(ctx sdk.Context, senderAddr sdk.AccAddress, recipientModule string, amt sdk.Coins) error
+	SendCoinsFromModuleToAccount(ctx sdk.Context, senderModule string, recipientAddr sdk.AccAddress, amt sdk.Coins) error
+	BurnCoins(ctx sdk.Context, name string, amt sdk.Coins) error
+}
+
+// PriceFeedKeeper provides the exchange rates used to convert pegged product prices, a
+// rate being usable for MaxPriceAge blocks after the height it was posted at
+type PriceFeedKeeper interface {
+	GetRate(ctx sdk.Context, denom, unit string) (rate sdk.Dec, height int64, ok bool)
+	MaxPriceAge(ctx sdk.Context) int64
+}
+
+// AccountKeeper is used by the simulation to sign transactions with the simulated accounts
+type AccountKeeper interface {
+	GetAccount(ctx sdk.Context, addr sdk.AccAddress) authexported.Account
+}
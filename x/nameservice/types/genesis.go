@@ -2,33 +2,166 @@ package types
 
 import (
 	"fmt"
+	"strings"
 )
 
+// WhoisRecord is a name along with its whois, as stored in the genesis state
+type WhoisRecord struct {
+	Name  string `json:"name"`
+	Whois Whois  `json:"whois"`
+}
+
 type GenesisState struct {
-	WhoisRecords []Whois `json:"whois_records"`
+	WhoisRecords   []WhoisRecord         `json:"whois_records"`
+	Products       []Product             `json:"products"`
+	ProductHistory []ProductHistoryEntry `json:"product_history"`
+	Auctions       []Auction             `json:"auctions"`
+	Purchases      []Purchase            `json:"purchases"`
+	Reviews        []Review              `json:"reviews"`
+	Coupons        []Coupon              `json:"coupons"`
+	Subscriptions  []Subscription        `json:"subscriptions"`
+	Licenses       []License             `json:"licenses"`
+
+	CouponCommitments []CouponCommitment `json:"coupon_commitments"`
 }
 
-func NewGenesisState(whoIsRecords []Whois) GenesisState {
-	return GenesisState{WhoisRecords: nil}
+func NewGenesisState(whoisRecords []WhoisRecord, products []Product, productHistory []ProductHistoryEntry,
+	auctions []Auction, purchases []Purchase, reviews []Review, coupons []Coupon,
+	subscriptions []Subscription, licenses []License, couponCommitments []CouponCommitment) GenesisState {
+	return GenesisState{
+		WhoisRecords:   whoisRecords,
+		Products:       products,
+		ProductHistory: productHistory,
+		Auctions:       auctions,
+		Purchases:      purchases,
+		Reviews:        reviews,
+		Coupons:        coupons,
+		Subscriptions:  subscriptions,
+		Licenses:       licenses,
+
+		CouponCommitments: couponCommitments,
+	}
 }
 
 func ValidateGenesis(data GenesisState) error {
+	names := make(map[string]bool)
 	for _, record := range data.WhoisRecords {
-		if record.Owner == nil {
-			return fmt.Errorf("invalid WhoisRecord: Value: %s. Error: Missing Owner", record.Value)
+		if record.Name == "" {
+			return fmt.Errorf("invalid WhoisRecord: Owner: %s. Error: Missing Name", record.Whois.Owner)
+		}
+		if names[record.Name] {
+			return fmt.Errorf("invalid WhoisRecord: Name: %s. Error: Duplicate Name", record.Name)
+		}
+		names[record.Name] = true
+
+		if record.Whois.Owner == nil {
+			return fmt.Errorf("invalid WhoisRecord: Name: %s. Error: Missing Owner", record.Name)
+		}
+		if record.Whois.Price == nil || !record.Whois.Price.IsValid() {
+			return fmt.Errorf("invalid WhoisRecord: Name: %s. Error: Invalid Price", record.Name)
+		}
+	}
+
+	products := make(map[string]bool)
+	for _, product := range data.Products {
+		if product.ProductID == "" {
+			return fmt.Errorf("invalid Product: Owner: %s. Error: Missing ProductID", product.Owner)
+		}
+		if strings.Contains(product.ProductID, IndexSeparator) {
+			return fmt.Errorf("invalid Product: ProductID: %s. Error: ProductID cannot contain %q", product.ProductID, IndexSeparator)
+		}
+		if products[product.ProductID] {
+			return fmt.Errorf("invalid Product: ProductID: %s. Error: Duplicate ProductID", product.ProductID)
+		}
+		products[product.ProductID] = true
+
+		if product.Owner.Empty() {
+			return fmt.Errorf("invalid Product: ProductID: %s. Error: Missing Owner", product.ProductID)
+		}
+		if !product.Price.IsValid() {
+			return fmt.Errorf("invalid Product: ProductID: %s. Error: Invalid Price", product.ProductID)
+		}
+		if product.Storefront != "" && !names[product.Storefront] {
+			return fmt.Errorf("invalid Product: ProductID: %s. Error: Unknown Storefront %s", product.ProductID, product.Storefront)
+		}
+	}
+
+	for _, entry := range data.ProductHistory {
+		if !products[entry.ProductID] {
+			return fmt.Errorf("invalid ProductHistoryEntry: ProductID: %s. Error: Unknown Product", entry.ProductID)
+		}
+	}
+
+	for _, auction := range data.Auctions {
+		if !products[auction.ProductID] {
+			return fmt.Errorf("invalid Auction: ProductID: %s. Error: Unknown Product", auction.ProductID)
+		}
+		if auction.Seller.Empty() {
+			return fmt.Errorf("invalid Auction: ProductID: %s. Error: Missing Seller", auction.ProductID)
+		}
+	}
+
+	for _, purchase := range data.Purchases {
+		if purchase.Buyer.Empty() || purchase.Seller.Empty() {
+			return fmt.Errorf("invalid Purchase: ProductID: %s. Error: Missing Buyer or Seller", purchase.ProductID)
+		}
+	}
+
+	for _, review := range data.Reviews {
+		if review.Reviewer.Empty() || review.Seller.Empty() {
+			return fmt.Errorf("invalid Review: ProductID: %s. Error: Missing Reviewer or Seller", review.ProductID)
+		}
+		if review.Rating < MinRating || review.Rating > MaxRating {
+			return fmt.Errorf("invalid Review: ProductID: %s. Error: Rating %d out of range", review.ProductID, review.Rating)
+		}
+	}
+
+	for _, coupon := range data.Coupons {
+		if err := validateCouponHash(coupon.CodeHash); err != nil {
+			return fmt.Errorf("invalid Coupon: CodeHash: %s. Error: %s", coupon.CodeHash, err)
 		}
-		if record.Value == "" {
-			return fmt.Errorf("invalid WhoisRecord: Owner: %s. Error: Missing Value", record.Owner)
+		if coupon.Issuer.Empty() {
+			return fmt.Errorf("invalid Coupon: CodeHash: %s. Error: Missing Issuer", coupon.CodeHash)
 		}
-		if record.Price == nil {
-			return fmt.Errorf("invalid WhoisRecord: Value: %s. Error: Missing Price", record.Value)
+	}
+
+	for _, commitment := range data.CouponCommitments {
+		msg := NewMsgCommitCoupon(commitment.CodeHash, commitment.Commitment, commitment.Signer)
+		if err := msg.ValidateBasic(); err != nil {
+			return fmt.Errorf("invalid CouponCommitment: Commitment: %s. Error: %s", commitment.Commitment, err)
+		}
+	}
+
+	for _, subscription := range data.Subscriptions {
+		if subscription.Subscriber.Empty() {
+			return fmt.Errorf("invalid Subscription: ProductID: %s. Error: Missing Subscriber", subscription.ProductID)
+		}
+		if subscription.Period <= 0 {
+			return fmt.Errorf("invalid Subscription: ProductID: %s. Error: Period must be positive", subscription.ProductID)
 		}
 	}
+
+	for _, license := range data.Licenses {
+		if license.Holder.Empty() {
+			return fmt.Errorf("invalid License: ProductID: %s. Error: Missing Holder", license.ProductID)
+		}
+	}
+
 	return nil
 }
 
 func DefaultGenesisState() GenesisState {
 	return GenesisState{
-		WhoisRecords: []Whois{},
+		WhoisRecords:   []WhoisRecord{},
+		Products:       []Product{},
+		ProductHistory: []ProductHistoryEntry{},
+		Auctions:       []Auction{},
+		Purchases:      []Purchase{},
+		Reviews:        []Review{},
+		Coupons:        []Coupon{},
+		Subscriptions:  []Subscription{},
+		Licenses:       []License{},
+
+		CouponCommitments: []CouponCommitment{},
 	}
-}
\ No newline at end of file
+}
@@ -1,5 +1,11 @@
 package types
 
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
 const (
 	// ModuleName is the name of the module
 	ModuleName = "nameservice"
@@ -13,3 +19,163 @@ const (
 	// QuerierRoute to be used for querierer msgs
 	QuerierRoute = ModuleName
 )
+
+const (
+	// WhoisPrefix is the key prefix under which name records are stored
+	WhoisPrefix = "Whois-"
+	// ProductPrefix is the key prefix under which products are stored
+	ProductPrefix = "Product-"
+
+	// CategoryPrefix is the key prefix of the category -> product index
+	CategoryPrefix = "Category-"
+	// TagPrefix is the key prefix of the tag -> product index
+	TagPrefix = "Tag-"
+	// StorefrontPrefix is the key prefix of the name -> published product index
+	StorefrontPrefix = "Storefront-"
+	// IndexSeparator separates the indexed value from the productID in index keys
+	IndexSeparator = "/"
+
+	// AuctionPrefix is the key prefix under which product auctions are stored
+	AuctionPrefix = "Auction-"
+
+	// PurchasePrefix is the key prefix under which product purchases are recorded
+	PurchasePrefix = "Purchase-"
+	// ReviewPrefix is the key prefix under which product reviews are stored
+	ReviewPrefix = "Review-"
+	// ProductRatingPrefix is the key prefix of the aggregated rating of a product
+	ProductRatingPrefix = "ProductRating-"
+	// SellerRatingPrefix is the key prefix of the aggregated rating of a seller
+	SellerRatingPrefix = "SellerRating-"
+
+	// CouponPrefix is the key prefix under which coupons are stored by code hash
+	CouponPrefix = "Coupon-"
+	// CouponCommitmentPrefix is the key prefix under which buyers commit to coupon codes
+	// before redeeming them
+	CouponCommitmentPrefix = "CouponCommitment-"
+
+	// SubscriptionPrefix is the key prefix under which product subscriptions are stored
+	SubscriptionPrefix = "Subscription-"
+	// SubscriptionDuePrefix is the key prefix of the index of subscriptions by the height
+	// their paid period ends
+	SubscriptionDuePrefix = "SubscriptionDue-"
+
+	// ProductHistoryPrefix is the key prefix of the update audit trail of products
+	ProductHistoryPrefix = "ProductHistory-"
+
+	// LicensePrefix is the key prefix under which product licenses are stored
+	LicensePrefix = "License-"
+
+	// LayoutPrefix is the key prefix of the metadata of the store layout
+	LayoutPrefix = "Layout-"
+	// LayoutVersionKey is the key of the version of the layout the store is written in
+	LayoutVersionKey = LayoutPrefix + "version"
+)
+
+// LayoutVersion is the version of the current store layout. Layout 0 stored the record
+// of a name under the bare name, layout 1 stores it under WhoisKey.
+const LayoutVersion byte = 1
+
+// RecordPrefixes are the key prefixes of every record of the store but the names
+var RecordPrefixes = []string{
+	ProductPrefix, CategoryPrefix, TagPrefix, StorefrontPrefix, AuctionPrefix,
+	PurchasePrefix, ReviewPrefix, ProductRatingPrefix, SellerRatingPrefix, CouponPrefix,
+	CouponCommitmentPrefix, SubscriptionPrefix, SubscriptionDuePrefix, ProductHistoryPrefix, LicensePrefix, LayoutPrefix,
+}
+
+// WhoisKey returns the key of the record of a name
+func WhoisKey(name string) []byte {
+	return []byte(WhoisPrefix + name)
+}
+
+// CategoryIndexPrefix returns the prefix of all index keys of a category
+func CategoryIndexPrefix(category string) []byte {
+	return []byte(CategoryPrefix + category + IndexSeparator)
+}
+
+// TagIndexPrefix returns the prefix of all index keys of a tag
+func TagIndexPrefix(tag string) []byte {
+	return []byte(TagPrefix + tag + IndexSeparator)
+}
+
+// StorefrontIndexPrefix returns the prefix of all index keys of the storefront of a name
+func StorefrontIndexPrefix(name string) []byte {
+	return []byte(StorefrontPrefix + name + IndexSeparator)
+}
+
+// PurchaseKey returns the key recording the purchase of a product by a buyer
+func PurchaseKey(productID string, buyer sdk.AccAddress) []byte {
+	return append(PurchasesPrefix(productID), buyer.String()...)
+}
+
+// PurchasesPrefix returns the prefix of all purchase keys of a product
+func PurchasesPrefix(productID string) []byte {
+	return []byte(PurchasePrefix + productID + IndexSeparator)
+}
+
+// ReviewKey returns the key of the review of a product by a reviewer
+func ReviewKey(productID string, reviewer sdk.AccAddress) []byte {
+	return append(ReviewsPrefix(productID), reviewer.String()...)
+}
+
+// ReviewsPrefix returns the prefix of all review keys of a product
+func ReviewsPrefix(productID string) []byte {
+	return []byte(ReviewPrefix + productID + IndexSeparator)
+}
+
+// CouponKey returns the key of the coupon with the given code hash
+func CouponKey(codeHash string) []byte {
+	return []byte(CouponPrefix + codeHash)
+}
+
+// CouponCommitmentKey returns the key of a commitment to the code of a coupon
+func CouponCommitmentKey(codeHash, commitment string) []byte {
+	return append(CouponCommitmentsPrefix(codeHash), commitment...)
+}
+
+// CouponCommitmentsPrefix returns the prefix of all commitment keys of a coupon
+func CouponCommitmentsPrefix(codeHash string) []byte {
+	return []byte(CouponCommitmentPrefix + codeHash + IndexSeparator)
+}
+
+// SubscriptionKey returns the key of the subscription of a subscriber to a product
+func SubscriptionKey(productID string, subscriber sdk.AccAddress) []byte {
+	return append(SubscriptionsPrefix(productID), subscriber.String()...)
+}
+
+// SubscriptionsPrefix returns the prefix of all subscription keys of a product
+func SubscriptionsPrefix(productID string) []byte {
+	return []byte(SubscriptionPrefix + productID + IndexSeparator)
+}
+
+// SubscriptionDueKey returns the key of a subscription in the index of subscriptions by
+// the height their paid period ends. Heights are zero padded so the index iterates in order.
+func SubscriptionDueKey(paidUntil int64, productID string, subscriber sdk.AccAddress) []byte {
+	return append(SubscriptionDuePrefixUntil(paidUntil), productID+IndexSeparator+subscriber.String()...)
+}
+
+// SubscriptionDuePrefixUntil returns the prefix of the index keys of the subscriptions
+// whose paid period ends at a height
+func SubscriptionDuePrefixUntil(paidUntil int64) []byte {
+	return []byte(fmt.Sprintf("%s%020d%s", SubscriptionDuePrefix, paidUntil, IndexSeparator))
+}
+
+// ProductHistoryKey returns the key of one version in the history of a product.
+// Versions are zero padded so the history iterates in order.
+func ProductHistoryKey(productID string, version uint64) []byte {
+	return append(ProductHistoryPrefixFor(productID), fmt.Sprintf("%020d", version)...)
+}
+
+// ProductHistoryPrefixFor returns the prefix of all history keys of a product
+func ProductHistoryPrefixFor(productID string) []byte {
+	return []byte(ProductHistoryPrefix + productID + IndexSeparator)
+}
+
+// LicenseKey returns the key of the license of a holder for a product
+func LicenseKey(productID string, holder sdk.AccAddress) []byte {
+	return append(LicensesPrefix(productID), holder.String()...)
+}
+
+// LicensesPrefix returns the prefix of all license keys of a product
+func LicensesPrefix(productID string) []byte {
+	return []byte(LicensePrefix + productID + IndexSeparator)
+}
@@ -1,6 +1,8 @@
 package types
 
 import (
+	"strings"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 )
@@ -140,15 +142,21 @@ type MsgCreateProduct struct {
 	ProductID   string         `json:"productID"`
 	Description string         `json:"description"`
 	Price       sdk.Coins      `json:"price"`
+	Category    string         `json:"category"`
+	Tags        []string       `json:"tags"`
+	Content     Content        `json:"content"`
 	Signer      sdk.AccAddress `json:"signer"`
 }
 
 // NewMsgCreateProduct is a constructor function for MsgCreateProduct
-func NewMsgCreateProduct(productID string, description string, price sdk.Coins, signer sdk.AccAddress) MsgCreateProduct {
+func NewMsgCreateProduct(productID string, description string, price sdk.Coins, category string, tags []string, content Content, signer sdk.AccAddress) MsgCreateProduct {
 	return MsgCreateProduct{
 		ProductID:   productID,
 		Description: description,
 		Price:       price,
+		Category:    category,
+		Tags:        tags,
+		Content:     content,
 		Signer:      signer,
 	}
 }
@@ -164,13 +172,19 @@ func (msg MsgCreateProduct) ValidateBasic() error {
 	if msg.Signer.Empty() {
 		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, msg.Signer.String())
 	}
-	if len(msg.ProductID) == 0 || len(msg.Description) == 0 {
-		return sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "ProductID and/or Description cannot be empty")
+	if err := validateProductID(msg.ProductID); err != nil {
+		return err
+	}
+	if len(msg.Description) == 0 {
+		return sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "Description cannot be empty")
 	}
 	if !msg.Price.IsAllPositive() {
 		return sdkerrors.ErrInsufficientFunds
 	}
-	return nil
+	if err := msg.Content.ValidateBasic(); err != nil {
+		return err
+	}
+	return validateCategoryAndTags(msg.Category, msg.Tags)
 }
 
 // GetSignBytes encodes the message for signing
@@ -188,15 +202,21 @@ type MsgUpdateProduct struct {
 	ProductID   string         `json:"productID"`
 	Description string         `json:"description"`
 	Price       sdk.Coins      `json:"price"`
+	Category    string         `json:"category"`
+	Tags        []string       `json:"tags"`
+	Content     Content        `json:"content"`
 	Signer      sdk.AccAddress `json:"signer"`
 }
 
 // NewMsgUpdateProduct is a constructor function for MsgUpdateProduct
-func NewMsgUpdateProduct(productID string, description string, price sdk.Coins, signer sdk.AccAddress) MsgUpdateProduct {
+func NewMsgUpdateProduct(productID string, description string, price sdk.Coins, category string, tags []string, content Content, signer sdk.AccAddress) MsgUpdateProduct {
 	return MsgUpdateProduct{
 		ProductID:   productID,
 		Description: description,
 		Price:       price,
+		Category:    category,
+		Tags:        tags,
+		Content:     content,
 		Signer:      signer,
 	}
 }
@@ -212,13 +232,19 @@ func (msg MsgUpdateProduct) ValidateBasic() error {
 	if msg.Signer.Empty() {
 		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, msg.Signer.String())
 	}
-	if len(msg.ProductID) == 0 || len(msg.Description) == 0 {
-		return sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "ProductID and/or Description cannot be empty")
+	if err := validateProductID(msg.ProductID); err != nil {
+		return err
+	}
+	if len(msg.Description) == 0 {
+		return sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "Description cannot be empty")
 	}
 	if !msg.Price.IsAllPositive() {
 		return sdkerrors.ErrInsufficientFunds
 	}
-	return nil
+	if err := msg.Content.ValidateBasic(); err != nil {
+		return err
+	}
+	return validateCategoryAndTags(msg.Category, msg.Tags)
 }
 
 // GetSignBytes encodes the message for signing
@@ -256,8 +282,8 @@ func (msg MsgDeleteProduct) ValidateBasic() error {
 	if msg.Signer.Empty() {
 		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, msg.Signer.String())
 	}
-	if len(msg.ProductID) == 0 {
-		return sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "ProductID cannot be empty")
+	if err := validateProductID(msg.ProductID); err != nil {
+		return err
 	}
 	return nil
 }
@@ -274,15 +300,22 @@ func (msg MsgDeleteProduct) GetSigners() []sdk.AccAddress {
 
 // MsgBuyProduct defines a DeleteName message
 type MsgBuyProduct struct {
-	ProductID string         `json:"productID"`
-	Signer    sdk.AccAddress `json:"signer"`
+	ProductID string `json:"productID"`
+	Denom     string `json:"denom"`  // denomination to pay in, empty to pay the listed price
+	Coupon    string `json:"coupon"` // optional coupon code to redeem, committed to with MsgCommitCoupon beforehand
+	// ExpectedVersion fails the purchase if the product was updated since, zero to skip the check
+	ExpectedVersion uint64         `json:"expected_version"`
+	Signer          sdk.AccAddress `json:"signer"`
 }
 
 // NewMsgBuyProduct is a constructor function for MsgBuyProduct
-func NewMsgBuyProduct(productID string, signer sdk.AccAddress) MsgBuyProduct {
+func NewMsgBuyProduct(productID string, denom string, coupon string, expectedVersion uint64, signer sdk.AccAddress) MsgBuyProduct {
 	return MsgBuyProduct{
-		ProductID: productID,
-		Signer:    signer,
+		ProductID:       productID,
+		Denom:           denom,
+		Coupon:          coupon,
+		ExpectedVersion: expectedVersion,
+		Signer:          signer,
 	}
 }
 
@@ -297,8 +330,13 @@ func (msg MsgBuyProduct) ValidateBasic() error {
 	if msg.Signer.Empty() {
 		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, msg.Signer.String())
 	}
-	if len(msg.ProductID) == 0 {
-		return sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "ProductID cannot be empty")
+	if err := validateProductID(msg.ProductID); err != nil {
+		return err
+	}
+	if msg.Denom != "" {
+		if err := sdk.ValidateDenom(msg.Denom); err != nil {
+			return sdkerrors.Wrap(sdkerrors.ErrInvalidCoins, err.Error())
+		}
 	}
 	return nil
 }
@@ -312,3 +350,851 @@ func (msg MsgBuyProduct) GetSignBytes() []byte {
 func (msg MsgBuyProduct) GetSigners() []sdk.AccAddress {
 	return []sdk.AccAddress{msg.Signer}
 }
+
+// MaxCartItems is the maximum number of products bought by a single MsgBuyProducts
+const MaxCartItems = 20
+
+// CartItem is one product of a MsgBuyProducts. Every product is unique, so an
+// item always buys a single unit.
+type CartItem struct {
+	ProductID       string `json:"productID"`
+	Denom           string `json:"denom"`
+	Coupon          string `json:"coupon"`
+	ExpectedVersion uint64 `json:"expected_version"`
+}
+
+// NewCartItem is a constructor function for CartItem
+func NewCartItem(productID string, denom string, coupon string, expectedVersion uint64) CartItem {
+	return CartItem{
+		ProductID:       productID,
+		Denom:           denom,
+		Coupon:          coupon,
+		ExpectedVersion: expectedVersion,
+	}
+}
+
+// Msg returns the purchase of the item by a buyer as a MsgBuyProduct
+func (item CartItem) Msg(buyer sdk.AccAddress) MsgBuyProduct {
+	return NewMsgBuyProduct(item.ProductID, item.Denom, item.Coupon, item.ExpectedVersion, buyer)
+}
+
+// MsgBuyProducts defines a BuyProducts message
+type MsgBuyProducts struct {
+	Items  []CartItem     `json:"items"`
+	Signer sdk.AccAddress `json:"signer"`
+}
+
+// NewMsgBuyProducts is a constructor function for MsgBuyProducts
+func NewMsgBuyProducts(items []CartItem, signer sdk.AccAddress) MsgBuyProducts {
+	return MsgBuyProducts{
+		Items:  items,
+		Signer: signer,
+	}
+}
+
+// Route should return the name of the module
+func (msg MsgBuyProducts) Route() string { return RouterKey }
+
+// Type should return the action
+func (msg MsgBuyProducts) Type() string { return "buy_products" }
+
+// ValidateBasic runs stateless checks on the message
+func (msg MsgBuyProducts) ValidateBasic() error {
+	if msg.Signer.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, msg.Signer.String())
+	}
+	if len(msg.Items) == 0 {
+		return sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "Cart cannot be empty")
+	}
+	if len(msg.Items) > MaxCartItems {
+		return sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "Cart cannot hold more than %d products", MaxCartItems)
+	}
+
+	seen := make(map[string]bool, len(msg.Items))
+	for _, item := range msg.Items {
+		if err := item.Msg(msg.Signer).ValidateBasic(); err != nil {
+			return err
+		}
+		if seen[item.ProductID] {
+			return sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "Product %s is in the cart twice", item.ProductID)
+		}
+		seen[item.ProductID] = true
+	}
+	return nil
+}
+
+// GetSignBytes encodes the message for signing
+func (msg MsgBuyProducts) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// GetSigners defines whose signature is required
+func (msg MsgBuyProducts) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Signer}
+}
+
+// MsgListProduct defines a ListProduct message
+type MsgListProduct struct {
+	ProductID string         `json:"productID"`
+	Signer    sdk.AccAddress `json:"signer"`
+}
+
+// NewMsgListProduct is a constructor function for MsgListProduct
+func NewMsgListProduct(productID string, signer sdk.AccAddress) MsgListProduct {
+	return MsgListProduct{
+		ProductID: productID,
+		Signer:    signer,
+	}
+}
+
+// Route should return the name of the module
+func (msg MsgListProduct) Route() string { return RouterKey }
+
+// Type should return the action
+func (msg MsgListProduct) Type() string { return "list_product" }
+
+// ValidateBasic runs stateless checks on the message
+func (msg MsgListProduct) ValidateBasic() error {
+	if msg.Signer.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, msg.Signer.String())
+	}
+	if err := validateProductID(msg.ProductID); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetSignBytes encodes the message for signing
+func (msg MsgListProduct) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// GetSigners defines whose signature is required
+func (msg MsgListProduct) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Signer}
+}
+
+// MsgDelistProduct defines a DelistProduct message
+type MsgDelistProduct struct {
+	ProductID string         `json:"productID"`
+	Signer    sdk.AccAddress `json:"signer"`
+}
+
+// NewMsgDelistProduct is a constructor function for MsgDelistProduct
+func NewMsgDelistProduct(productID string, signer sdk.AccAddress) MsgDelistProduct {
+	return MsgDelistProduct{
+		ProductID: productID,
+		Signer:    signer,
+	}
+}
+
+// Route should return the name of the module
+func (msg MsgDelistProduct) Route() string { return RouterKey }
+
+// Type should return the action
+func (msg MsgDelistProduct) Type() string { return "delist_product" }
+
+// ValidateBasic runs stateless checks on the message
+func (msg MsgDelistProduct) ValidateBasic() error {
+	if msg.Signer.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, msg.Signer.String())
+	}
+	if err := validateProductID(msg.ProductID); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetSignBytes encodes the message for signing
+func (msg MsgDelistProduct) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// GetSigners defines whose signature is required
+func (msg MsgDelistProduct) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Signer}
+}
+
+// MsgCreateAuction defines a CreateAuction message
+type MsgCreateAuction struct {
+	ProductID    string         `json:"productID"`
+	AuctionType  string         `json:"auction_type"`
+	StartPrice   sdk.Coins      `json:"start_price"`
+	ReservePrice sdk.Coins      `json:"reserve_price"`
+	Decrement    sdk.Coins      `json:"decrement"`
+	Duration     int64          `json:"duration"`
+	Signer       sdk.AccAddress `json:"signer"`
+}
+
+// NewMsgCreateAuction is a constructor function for MsgCreateAuction
+func NewMsgCreateAuction(productID string, auctionType string, startPrice, reservePrice, decrement sdk.Coins, duration int64, signer sdk.AccAddress) MsgCreateAuction {
+	return MsgCreateAuction{
+		ProductID:    productID,
+		AuctionType:  auctionType,
+		StartPrice:   startPrice,
+		ReservePrice: reservePrice,
+		Decrement:    decrement,
+		Duration:     duration,
+		Signer:       signer,
+	}
+}
+
+// Route should return the name of the module
+func (msg MsgCreateAuction) Route() string { return RouterKey }
+
+// Type should return the action
+func (msg MsgCreateAuction) Type() string { return "create_auction" }
+
+// ValidateBasic runs stateless checks on the message
+func (msg MsgCreateAuction) ValidateBasic() error {
+	if msg.Signer.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, msg.Signer.String())
+	}
+	if err := validateProductID(msg.ProductID); err != nil {
+		return err
+	}
+	if msg.Duration <= 0 {
+		return sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "Duration must be positive")
+	}
+	if !msg.StartPrice.IsAllPositive() {
+		return sdkerrors.ErrInsufficientFunds
+	}
+
+	switch msg.AuctionType {
+	case AuctionEnglish:
+		// the highest bid wins whatever its amount, only dutch auctions decline to a reserve
+		if !msg.ReservePrice.Empty() {
+			return sdkerrors.Wrap(sdkerrors.ErrInvalidCoins, "English auctions cannot have a reserve price")
+		}
+		return nil
+	case AuctionDutch:
+		if !msg.Decrement.IsAllPositive() || !msg.Decrement.DenomsSubsetOf(msg.StartPrice) {
+			return sdkerrors.Wrap(sdkerrors.ErrInvalidCoins, "Decrement must be positive and priced in the start price denominations")
+		}
+		if !msg.ReservePrice.IsValid() || msg.ReservePrice.IsAnyGT(msg.StartPrice) {
+			return sdkerrors.Wrap(sdkerrors.ErrInvalidCoins, "Reserve price cannot exceed the start price")
+		}
+		return nil
+	default:
+		return sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "AuctionType must be english or dutch")
+	}
+}
+
+// GetSignBytes encodes the message for signing
+func (msg MsgCreateAuction) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// GetSigners defines whose signature is required
+func (msg MsgCreateAuction) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Signer}
+}
+
+// MsgPlaceBid defines a PlaceBid message
+type MsgPlaceBid struct {
+	ProductID string         `json:"productID"`
+	Amount    sdk.Coins      `json:"amount"`
+	Bidder    sdk.AccAddress `json:"bidder"`
+}
+
+// NewMsgPlaceBid is a constructor function for MsgPlaceBid
+func NewMsgPlaceBid(productID string, amount sdk.Coins, bidder sdk.AccAddress) MsgPlaceBid {
+	return MsgPlaceBid{
+		ProductID: productID,
+		Amount:    amount,
+		Bidder:    bidder,
+	}
+}
+
+// Route should return the name of the module
+func (msg MsgPlaceBid) Route() string { return RouterKey }
+
+// Type should return the action
+func (msg MsgPlaceBid) Type() string { return "place_bid" }
+
+// ValidateBasic runs stateless checks on the message
+func (msg MsgPlaceBid) ValidateBasic() error {
+	if msg.Bidder.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, msg.Bidder.String())
+	}
+	if err := validateProductID(msg.ProductID); err != nil {
+		return err
+	}
+	if !msg.Amount.IsAllPositive() {
+		return sdkerrors.ErrInsufficientFunds
+	}
+	return nil
+}
+
+// GetSignBytes encodes the message for signing
+func (msg MsgPlaceBid) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// GetSigners defines whose signature is required
+func (msg MsgPlaceBid) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Bidder}
+}
+
+// MsgPublishProduct defines a PublishProduct message
+type MsgPublishProduct struct {
+	Name      string         `json:"name"`
+	ProductID string         `json:"productID"`
+	Signer    sdk.AccAddress `json:"signer"`
+}
+
+// NewMsgPublishProduct is a constructor function for MsgPublishProduct
+func NewMsgPublishProduct(name string, productID string, signer sdk.AccAddress) MsgPublishProduct {
+	return MsgPublishProduct{
+		Name:      name,
+		ProductID: productID,
+		Signer:    signer,
+	}
+}
+
+// Route should return the name of the module
+func (msg MsgPublishProduct) Route() string { return RouterKey }
+
+// Type should return the action
+func (msg MsgPublishProduct) Type() string { return "publish_product" }
+
+// ValidateBasic runs stateless checks on the message
+func (msg MsgPublishProduct) ValidateBasic() error {
+	if msg.Signer.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, msg.Signer.String())
+	}
+	if len(msg.Name) == 0 || len(msg.ProductID) == 0 {
+		return sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "Name and/or ProductID cannot be empty")
+	}
+	// The separator is reserved to address products as name/productID
+	if strings.Contains(msg.Name, IndexSeparator) || strings.Contains(msg.ProductID, IndexSeparator) {
+		return sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "Name and/or ProductID cannot contain %q", IndexSeparator)
+	}
+	return nil
+}
+
+// GetSignBytes encodes the message for signing
+func (msg MsgPublishProduct) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// GetSigners defines whose signature is required
+func (msg MsgPublishProduct) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Signer}
+}
+
+// MsgUnpublishProduct defines a UnpublishProduct message
+type MsgUnpublishProduct struct {
+	ProductID string         `json:"productID"`
+	Signer    sdk.AccAddress `json:"signer"`
+}
+
+// NewMsgUnpublishProduct is a constructor function for MsgUnpublishProduct
+func NewMsgUnpublishProduct(productID string, signer sdk.AccAddress) MsgUnpublishProduct {
+	return MsgUnpublishProduct{
+		ProductID: productID,
+		Signer:    signer,
+	}
+}
+
+// Route should return the name of the module
+func (msg MsgUnpublishProduct) Route() string { return RouterKey }
+
+// Type should return the action
+func (msg MsgUnpublishProduct) Type() string { return "unpublish_product" }
+
+// ValidateBasic runs stateless checks on the message
+func (msg MsgUnpublishProduct) ValidateBasic() error {
+	if msg.Signer.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, msg.Signer.String())
+	}
+	if err := validateProductID(msg.ProductID); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetSignBytes encodes the message for signing
+func (msg MsgUnpublishProduct) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// GetSigners defines whose signature is required
+func (msg MsgUnpublishProduct) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Signer}
+}
+
+// MsgSetStorefrontSale defines a SetStorefrontSale message
+type MsgSetStorefrontSale struct {
+	Name     string         `json:"name"`
+	Included bool           `json:"included"`
+	Owner    sdk.AccAddress `json:"owner"`
+}
+
+// NewMsgSetStorefrontSale is a constructor function for MsgSetStorefrontSale
+func NewMsgSetStorefrontSale(name string, included bool, owner sdk.AccAddress) MsgSetStorefrontSale {
+	return MsgSetStorefrontSale{
+		Name:     name,
+		Included: included,
+		Owner:    owner,
+	}
+}
+
+// Route should return the name of the module
+func (msg MsgSetStorefrontSale) Route() string { return RouterKey }
+
+// Type should return the action
+func (msg MsgSetStorefrontSale) Type() string { return "set_storefront_sale" }
+
+// ValidateBasic runs stateless checks on the message
+func (msg MsgSetStorefrontSale) ValidateBasic() error {
+	if msg.Owner.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, msg.Owner.String())
+	}
+	if len(msg.Name) == 0 {
+		return sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "Name cannot be empty")
+	}
+	return nil
+}
+
+// GetSignBytes encodes the message for signing
+func (msg MsgSetStorefrontSale) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// GetSigners defines whose signature is required
+func (msg MsgSetStorefrontSale) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Owner}
+}
+
+// MsgReviewProduct defines a ReviewProduct message
+type MsgReviewProduct struct {
+	ProductID string         `json:"productID"`
+	Rating    uint8          `json:"rating"`
+	Text      string         `json:"text"`
+	Reviewer  sdk.AccAddress `json:"reviewer"`
+}
+
+// NewMsgReviewProduct is a constructor function for MsgReviewProduct
+func NewMsgReviewProduct(productID string, rating uint8, text string, reviewer sdk.AccAddress) MsgReviewProduct {
+	return MsgReviewProduct{
+		ProductID: productID,
+		Rating:    rating,
+		Text:      text,
+		Reviewer:  reviewer,
+	}
+}
+
+// Route should return the name of the module
+func (msg MsgReviewProduct) Route() string { return RouterKey }
+
+// Type should return the action
+func (msg MsgReviewProduct) Type() string { return "review_product" }
+
+// ValidateBasic runs stateless checks on the message
+func (msg MsgReviewProduct) ValidateBasic() error {
+	if msg.Reviewer.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, msg.Reviewer.String())
+	}
+	if err := validateProductID(msg.ProductID); err != nil {
+		return err
+	}
+	if msg.Rating < MinRating || msg.Rating > MaxRating {
+		return sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "Rating must be between %d and %d", MinRating, MaxRating)
+	}
+	if len(msg.Text) > MaxReviewLength {
+		return sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "Text cannot be longer than %d characters", MaxReviewLength)
+	}
+	return nil
+}
+
+// GetSignBytes encodes the message for signing
+func (msg MsgReviewProduct) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// GetSigners defines whose signature is required
+func (msg MsgReviewProduct) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Reviewer}
+}
+
+// MsgSetProductPricing defines a SetProductPricing message
+type MsgSetProductPricing struct {
+	ProductID      string         `json:"productID"`
+	AcceptedPrices sdk.Coins      `json:"accepted_prices"`
+	ReferencePrice sdk.DecCoins   `json:"reference_price"`
+	Signer         sdk.AccAddress `json:"signer"`
+}
+
+// NewMsgSetProductPricing is a constructor function for MsgSetProductPricing
+func NewMsgSetProductPricing(productID string, acceptedPrices sdk.Coins, referencePrice sdk.DecCoins, signer sdk.AccAddress) MsgSetProductPricing {
+	return MsgSetProductPricing{
+		ProductID:      productID,
+		AcceptedPrices: acceptedPrices,
+		ReferencePrice: referencePrice,
+		Signer:         signer,
+	}
+}
+
+// Route should return the name of the module
+func (msg MsgSetProductPricing) Route() string { return RouterKey }
+
+// Type should return the action
+func (msg MsgSetProductPricing) Type() string { return "set_product_pricing" }
+
+// ValidateBasic runs stateless checks on the message
+func (msg MsgSetProductPricing) ValidateBasic() error {
+	if msg.Signer.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, msg.Signer.String())
+	}
+	if err := validateProductID(msg.ProductID); err != nil {
+		return err
+	}
+	if !msg.AcceptedPrices.Empty() && !msg.AcceptedPrices.IsAllPositive() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidCoins, "Accepted prices must be positive")
+	}
+	if len(msg.ReferencePrice) > 1 {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidCoins, "Reference price must be in a single unit")
+	}
+	if !msg.ReferencePrice.Empty() && !msg.ReferencePrice.IsAllPositive() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidCoins, "Reference price must be positive")
+	}
+	return nil
+}
+
+// GetSignBytes encodes the message for signing
+func (msg MsgSetProductPricing) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// GetSigners defines whose signature is required
+func (msg MsgSetProductPricing) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Signer}
+}
+
+// MsgCreateCoupon defines a CreateCoupon message
+type MsgCreateCoupon struct {
+	CodeHash     string         `json:"code_hash"`
+	ProductID    string         `json:"productID"`
+	Percent      uint64         `json:"percent"`
+	Amount       sdk.Coins      `json:"amount"`
+	ExpiryHeight int64          `json:"expiry_height"`
+	MaxUses      uint64         `json:"max_uses"`
+	Issuer       sdk.AccAddress `json:"issuer"`
+}
+
+// NewMsgCreateCoupon is a constructor function for MsgCreateCoupon
+func NewMsgCreateCoupon(codeHash string, productID string, percent uint64, amount sdk.Coins, expiryHeight int64, maxUses uint64, issuer sdk.AccAddress) MsgCreateCoupon {
+	return MsgCreateCoupon{
+		CodeHash:     codeHash,
+		ProductID:    productID,
+		Percent:      percent,
+		Amount:       amount,
+		ExpiryHeight: expiryHeight,
+		MaxUses:      maxUses,
+		Issuer:       issuer,
+	}
+}
+
+// Route should return the name of the module
+func (msg MsgCreateCoupon) Route() string { return RouterKey }
+
+// Type should return the action
+func (msg MsgCreateCoupon) Type() string { return "create_coupon" }
+
+// ValidateBasic runs stateless checks on the message
+func (msg MsgCreateCoupon) ValidateBasic() error {
+	if msg.Issuer.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, msg.Issuer.String())
+	}
+	if err := validateCouponHash(msg.CodeHash); err != nil {
+		return err
+	}
+	if msg.ProductID != "" {
+		if err := validateProductID(msg.ProductID); err != nil {
+			return err
+		}
+	}
+	if hasPercent, hasAmount := msg.Percent > 0, !msg.Amount.Empty(); hasPercent == hasAmount {
+		return sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "Coupon needs either a percentage or a fixed discount")
+	}
+	if msg.Percent > MaxCouponPercent {
+		return sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "Coupon percentage cannot exceed %d", MaxCouponPercent)
+	}
+	if !msg.Amount.Empty() && !msg.Amount.IsAllPositive() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidCoins, "Coupon amount must be positive")
+	}
+	if msg.ExpiryHeight < 0 {
+		return sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "Expiry height cannot be negative")
+	}
+	return nil
+}
+
+// GetSignBytes encodes the message for signing
+func (msg MsgCreateCoupon) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// GetSigners defines whose signature is required
+func (msg MsgCreateCoupon) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Issuer}
+}
+
+// MsgRevokeCoupon defines a RevokeCoupon message
+type MsgRevokeCoupon struct {
+	CodeHash string         `json:"code_hash"`
+	Issuer   sdk.AccAddress `json:"issuer"`
+}
+
+// NewMsgRevokeCoupon is a constructor function for MsgRevokeCoupon
+func NewMsgRevokeCoupon(codeHash string, issuer sdk.AccAddress) MsgRevokeCoupon {
+	return MsgRevokeCoupon{
+		CodeHash: codeHash,
+		Issuer:   issuer,
+	}
+}
+
+// Route should return the name of the module
+func (msg MsgRevokeCoupon) Route() string { return RouterKey }
+
+// Type should return the action
+func (msg MsgRevokeCoupon) Type() string { return "revoke_coupon" }
+
+// ValidateBasic runs stateless checks on the message
+func (msg MsgRevokeCoupon) ValidateBasic() error {
+	if msg.Issuer.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, msg.Issuer.String())
+	}
+	return validateCouponHash(msg.CodeHash)
+}
+
+// GetSignBytes encodes the message for signing
+func (msg MsgRevokeCoupon) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// GetSigners defines whose signature is required
+func (msg MsgRevokeCoupon) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Issuer}
+}
+
+// MsgCommitCoupon defines a CommitCoupon message. A coupon code can only be redeemed
+// by a buyer who committed to it in an earlier block.
+type MsgCommitCoupon struct {
+	CodeHash   string         `json:"code_hash"`
+	Commitment string         `json:"commitment"` // HashCouponCommitment of the code and the signer
+	Signer     sdk.AccAddress `json:"signer"`
+}
+
+// NewMsgCommitCoupon is a constructor function for MsgCommitCoupon
+func NewMsgCommitCoupon(codeHash string, commitment string, signer sdk.AccAddress) MsgCommitCoupon {
+	return MsgCommitCoupon{
+		CodeHash:   codeHash,
+		Commitment: commitment,
+		Signer:     signer,
+	}
+}
+
+// Route should return the name of the module
+func (msg MsgCommitCoupon) Route() string { return RouterKey }
+
+// Type should return the action
+func (msg MsgCommitCoupon) Type() string { return "commit_coupon" }
+
+// ValidateBasic runs stateless checks on the message
+func (msg MsgCommitCoupon) ValidateBasic() error {
+	if msg.Signer.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, msg.Signer.String())
+	}
+	if err := validateCouponHash(msg.CodeHash); err != nil {
+		return err
+	}
+	if !sha256Pattern.MatchString(msg.Commitment) {
+		return sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "Coupon commitment must be a hex encoded sha256 hash")
+	}
+	return nil
+}
+
+// GetSignBytes encodes the message for signing
+func (msg MsgCommitCoupon) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// GetSigners defines whose signature is required
+func (msg MsgCommitCoupon) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Signer}
+}
+
+// MsgSetProductSubscription defines a SetProductSubscription message
+type MsgSetProductSubscription struct {
+	ProductID string         `json:"productID"`
+	Period    int64          `json:"period"`
+	Signer    sdk.AccAddress `json:"signer"`
+}
+
+// NewMsgSetProductSubscription is a constructor function for MsgSetProductSubscription
+func NewMsgSetProductSubscription(productID string, period int64, signer sdk.AccAddress) MsgSetProductSubscription {
+	return MsgSetProductSubscription{
+		ProductID: productID,
+		Period:    period,
+		Signer:    signer,
+	}
+}
+
+// Route should return the name of the module
+func (msg MsgSetProductSubscription) Route() string { return RouterKey }
+
+// Type should return the action
+func (msg MsgSetProductSubscription) Type() string { return "set_product_subscription" }
+
+// ValidateBasic runs stateless checks on the message
+func (msg MsgSetProductSubscription) ValidateBasic() error {
+	if msg.Signer.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, msg.Signer.String())
+	}
+	if err := validateProductID(msg.ProductID); err != nil {
+		return err
+	}
+	if msg.Period < 0 {
+		return sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "Subscription period cannot be negative")
+	}
+	return nil
+}
+
+// GetSignBytes encodes the message for signing
+func (msg MsgSetProductSubscription) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// GetSigners defines whose signature is required
+func (msg MsgSetProductSubscription) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Signer}
+}
+
+// MsgCancelSubscription defines a CancelSubscription message
+type MsgCancelSubscription struct {
+	ProductID  string         `json:"productID"`
+	Subscriber sdk.AccAddress `json:"subscriber"`
+}
+
+// NewMsgCancelSubscription is a constructor function for MsgCancelSubscription
+func NewMsgCancelSubscription(productID string, subscriber sdk.AccAddress) MsgCancelSubscription {
+	return MsgCancelSubscription{
+		ProductID:  productID,
+		Subscriber: subscriber,
+	}
+}
+
+// Route should return the name of the module
+func (msg MsgCancelSubscription) Route() string { return RouterKey }
+
+// Type should return the action
+func (msg MsgCancelSubscription) Type() string { return "cancel_subscription" }
+
+// ValidateBasic runs stateless checks on the message
+func (msg MsgCancelSubscription) ValidateBasic() error {
+	if msg.Subscriber.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, msg.Subscriber.String())
+	}
+	if err := validateProductID(msg.ProductID); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetSignBytes encodes the message for signing
+func (msg MsgCancelSubscription) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// GetSigners defines whose signature is required
+func (msg MsgCancelSubscription) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Subscriber}
+}
+
+// MsgSetProductLicensing defines a SetProductLicensing message
+type MsgSetProductLicensing struct {
+	ProductID string         `json:"productID"`
+	Licensing bool           `json:"licensing"`
+	Duration  int64          `json:"duration"`
+	Signer    sdk.AccAddress `json:"signer"`
+}
+
+// NewMsgSetProductLicensing is a constructor function for MsgSetProductLicensing
+func NewMsgSetProductLicensing(productID string, licensing bool, duration int64, signer sdk.AccAddress) MsgSetProductLicensing {
+	return MsgSetProductLicensing{
+		ProductID: productID,
+		Licensing: licensing,
+		Duration:  duration,
+		Signer:    signer,
+	}
+}
+
+// Route should return the name of the module
+func (msg MsgSetProductLicensing) Route() string { return RouterKey }
+
+// Type should return the action
+func (msg MsgSetProductLicensing) Type() string { return "set_product_licensing" }
+
+// ValidateBasic runs stateless checks on the message
+func (msg MsgSetProductLicensing) ValidateBasic() error {
+	if msg.Signer.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, msg.Signer.String())
+	}
+	if err := validateProductID(msg.ProductID); err != nil {
+		return err
+	}
+	if msg.Duration < 0 {
+		return sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "License duration cannot be negative")
+	}
+	if !msg.Licensing && msg.Duration != 0 {
+		return sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "License duration requires licensing")
+	}
+	return nil
+}
+
+// GetSignBytes encodes the message for signing
+func (msg MsgSetProductLicensing) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// GetSigners defines whose signature is required
+func (msg MsgSetProductLicensing) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Signer}
+}
+
+// validateCouponHash checks that a coupon code hash is a hex encoded sha256 hash
+func validateCouponHash(codeHash string) error {
+	if !sha256Pattern.MatchString(codeHash) {
+		return sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "Coupon code hash must be a hex encoded sha256 hash")
+	}
+	return nil
+}
+
+// validateProductID checks that a product ID can be used in keys, which separate it
+// from what follows with IndexSeparator, and in name/productID addresses
+func validateProductID(productID string) error {
+	if len(productID) == 0 {
+		return sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "ProductID cannot be empty")
+	}
+	if strings.Contains(productID, IndexSeparator) {
+		return sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "ProductID cannot contain %q", IndexSeparator)
+	}
+	return nil
+}
+
+// validateCategoryAndTags checks that category and tags can be used as index keys
+func validateCategoryAndTags(category string, tags []string) error {
+	if strings.Contains(category, IndexSeparator) {
+		return sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "Category cannot contain %q", IndexSeparator)
+	}
+	if len(tags) > MaxProductTags {
+		return sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "Product cannot have more than %d tags", MaxProductTags)
+	}
+	for _, tag := range tags {
+		if len(tag) == 0 || strings.Contains(tag, IndexSeparator) {
+			return sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "Tags cannot be empty or contain %q", IndexSeparator)
+		}
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"fmt"
 	"testing"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -145,3 +146,319 @@ func TestMsgDeleteNameGetSignBytes(t *testing.T) {
 
 	require.Equal(t, expected, string(res))
 }
+
+func TestMsgListProduct(t *testing.T) {
+	acc := sdk.AccAddress([]byte("me"))
+	var msg = NewMsgListProduct("product1", acc)
+
+	require.Equal(t, msg.Route(), RouterKey)
+	require.Equal(t, msg.Type(), "list_product")
+}
+
+func TestMsgListProductValidation(t *testing.T) {
+	acc := sdk.AccAddress([]byte("me"))
+
+	cases := []struct {
+		valid bool
+		tx    sdk.Msg
+	}{
+		{true, NewMsgListProduct("product1", acc)},
+		{false, NewMsgListProduct("", acc)},
+		{false, NewMsgListProduct("books/product1", acc)},
+		{false, NewMsgListProduct("product1", nil)},
+		{true, NewMsgDelistProduct("product1", acc)},
+		{false, NewMsgDelistProduct("", acc)},
+		{false, NewMsgDelistProduct("product1", nil)},
+	}
+
+	for _, tc := range cases {
+		err := tc.tx.ValidateBasic()
+		if tc.valid {
+			require.Nil(t, err)
+		} else {
+			require.NotNil(t, err)
+		}
+	}
+}
+
+func TestMsgCreateAuctionValidation(t *testing.T) {
+	acc := sdk.AccAddress([]byte("me"))
+	price := sdk.NewCoins(sdk.NewInt64Coin("nametoken", 100))
+	reserve := sdk.NewCoins(sdk.NewInt64Coin("nametoken", 10))
+	decrement := sdk.NewCoins(sdk.NewInt64Coin("nametoken", 5))
+	empty := sdk.NewCoins()
+
+	cases := []struct {
+		valid bool
+		tx    MsgCreateAuction
+	}{
+		{true, NewMsgCreateAuction("product1", AuctionEnglish, price, empty, empty, 10, acc)},
+		{true, NewMsgCreateAuction("product1", AuctionDutch, price, reserve, decrement, 10, acc)},
+		{true, NewMsgCreateAuction("product1", AuctionDutch, price, empty, decrement, 10, acc)},
+		{false, NewMsgCreateAuction("product1", "vickrey", price, empty, empty, 10, acc)},
+		{false, NewMsgCreateAuction("product1", AuctionEnglish, price, empty, empty, 0, acc)},
+		{false, NewMsgCreateAuction("product1", AuctionEnglish, empty, empty, empty, 10, acc)},
+		{false, NewMsgCreateAuction("product1", AuctionEnglish, price, reserve, empty, 10, acc)},
+		{false, NewMsgCreateAuction("product1", AuctionDutch, price, reserve, empty, 10, acc)},
+		{false, NewMsgCreateAuction("product1", AuctionDutch, reserve, price, decrement, 10, acc)},
+		{false, NewMsgCreateAuction("", AuctionEnglish, price, empty, empty, 10, acc)},
+		{false, NewMsgCreateAuction("product1", AuctionEnglish, price, empty, empty, 10, nil)},
+	}
+
+	for _, tc := range cases {
+		err := tc.tx.ValidateBasic()
+		if tc.valid {
+			require.Nil(t, err)
+		} else {
+			require.NotNil(t, err)
+		}
+	}
+}
+
+func TestMsgCreateProductValidation(t *testing.T) {
+	acc := sdk.AccAddress([]byte("me"))
+	price := sdk.NewCoins(sdk.NewInt64Coin("nametoken", 10))
+
+	cases := []struct {
+		valid bool
+		tx    sdk.Msg
+	}{
+		{true, NewMsgCreateProduct("product1", "a book", price, "", nil, Content{}, acc)},
+		{true, NewMsgCreateProduct("product1", "a book", price, "books", []string{"fantasy", "used"}, Content{}, acc)},
+		{false, NewMsgCreateProduct("product1", "a book", price, "books/used", nil, Content{}, acc)},
+		{false, NewMsgCreateProduct("product1", "a book", price, "books", []string{""}, Content{}, acc)},
+		{false, NewMsgCreateProduct("product1", "a book", price, "books", []string{"fantasy/used"}, Content{}, acc)},
+		{false, NewMsgCreateProduct("product1", "a book", price, "books", make([]string, MaxProductTags+1), Content{}, acc)},
+		{false, NewMsgCreateProduct("", "a book", price, "", nil, Content{}, acc)},
+		{false, NewMsgCreateProduct("books/product1", "a book", price, "", nil, Content{}, acc)},
+		{false, NewMsgUpdateProduct("books/product1", "a book", price, "", nil, Content{}, acc)},
+		{false, NewMsgCreateProduct("product1", "a book", sdk.NewCoins(), "", nil, Content{}, acc)},
+	}
+
+	for _, tc := range cases {
+		err := tc.tx.ValidateBasic()
+		if tc.valid {
+			require.Nil(t, err)
+		} else {
+			require.NotNil(t, err)
+		}
+	}
+}
+
+func TestMsgPublishProductValidation(t *testing.T) {
+	acc := sdk.AccAddress([]byte("me"))
+
+	cases := []struct {
+		valid bool
+		tx    MsgPublishProduct
+	}{
+		{true, NewMsgPublishProduct(name, "product1", acc)},
+		{false, NewMsgPublishProduct("", "product1", acc)},
+		{false, NewMsgPublishProduct(name, "", acc)},
+		{false, NewMsgPublishProduct("shop/books", "product1", acc)},
+		{false, NewMsgPublishProduct(name, "books/product1", acc)},
+		{false, NewMsgPublishProduct(name, "product1", nil)},
+	}
+
+	for _, tc := range cases {
+		err := tc.tx.ValidateBasic()
+		if tc.valid {
+			require.Nil(t, err)
+		} else {
+			require.NotNil(t, err)
+		}
+	}
+}
+
+func TestMsgReviewProductValidation(t *testing.T) {
+	acc := sdk.AccAddress([]byte("me"))
+
+	cases := []struct {
+		valid bool
+		tx    MsgReviewProduct
+	}{
+		{true, NewMsgReviewProduct("product1", 5, "great", acc)},
+		{true, NewMsgReviewProduct("product1", 1, "", acc)},
+		{false, NewMsgReviewProduct("product1", 0, "great", acc)},
+		{false, NewMsgReviewProduct("product1", 6, "great", acc)},
+		{false, NewMsgReviewProduct("product1", 3, string(make([]byte, MaxReviewLength+1)), acc)},
+		{false, NewMsgReviewProduct("", 3, "great", acc)},
+		{false, NewMsgReviewProduct("books/product1", 3, "great", acc)},
+		{false, NewMsgReviewProduct("product1", 3, "great", nil)},
+	}
+
+	for _, tc := range cases {
+		err := tc.tx.ValidateBasic()
+		if tc.valid {
+			require.Nil(t, err)
+		} else {
+			require.NotNil(t, err)
+		}
+	}
+}
+
+func TestMsgSetProductPricingValidation(t *testing.T) {
+	acc := sdk.AccAddress([]byte("me"))
+	accepted := sdk.NewCoins(sdk.NewInt64Coin("nametoken", 10), sdk.NewInt64Coin("stake", 5))
+	reference := sdk.NewDecCoins(sdk.NewDecCoinFromDec("usd", sdk.NewDecWithPrec(25, 1)))
+	twoUnits := sdk.NewDecCoins(sdk.NewInt64DecCoin("eur", 2), sdk.NewInt64DecCoin("usd", 2))
+
+	cases := []struct {
+		valid bool
+		tx    MsgSetProductPricing
+	}{
+		{true, NewMsgSetProductPricing("product1", accepted, nil, acc)},
+		{true, NewMsgSetProductPricing("product1", nil, reference, acc)},
+		{true, NewMsgSetProductPricing("product1", nil, nil, acc)},
+		{false, NewMsgSetProductPricing("product1", nil, twoUnits, acc)},
+		{false, NewMsgSetProductPricing("", accepted, nil, acc)},
+		{false, NewMsgSetProductPricing("product1", accepted, nil, nil)},
+	}
+
+	for _, tc := range cases {
+		err := tc.tx.ValidateBasic()
+		if tc.valid {
+			require.Nil(t, err)
+		} else {
+			require.NotNil(t, err)
+		}
+	}
+}
+
+func TestMsgCreateCouponValidation(t *testing.T) {
+	acc := sdk.AccAddress([]byte("me"))
+	hash := HashCouponCode("SPRING10")
+	amount := sdk.NewCoins(sdk.NewInt64Coin("nametoken", 5))
+
+	cases := []struct {
+		valid bool
+		tx    MsgCreateCoupon
+	}{
+		{true, NewMsgCreateCoupon(hash, "", 10, nil, 0, 0, acc)},
+		{true, NewMsgCreateCoupon(hash, "product1", 0, amount, 100, 5, acc)},
+		{false, NewMsgCreateCoupon(hash, "", 0, nil, 0, 0, acc)},
+		{false, NewMsgCreateCoupon(hash, "", 10, amount, 0, 0, acc)},
+		{false, NewMsgCreateCoupon(hash, "", 101, nil, 0, 0, acc)},
+		{false, NewMsgCreateCoupon(hash, "", 10, nil, -1, 0, acc)},
+		{false, NewMsgCreateCoupon("SPRING10", "", 10, nil, 0, 0, acc)},
+		{false, NewMsgCreateCoupon(hash, "", 10, nil, 0, 0, nil)},
+	}
+
+	for _, tc := range cases {
+		err := tc.tx.ValidateBasic()
+		if tc.valid {
+			require.Nil(t, err)
+		} else {
+			require.NotNil(t, err)
+		}
+	}
+}
+
+func TestMsgCommitCouponValidation(t *testing.T) {
+	acc := sdk.AccAddress([]byte("me"))
+	hash := HashCouponCode("SPRING10")
+	commitment := HashCouponCommitment("SPRING10", acc)
+
+	cases := []struct {
+		valid bool
+		tx    MsgCommitCoupon
+	}{
+		{true, NewMsgCommitCoupon(hash, commitment, acc)},
+		{false, NewMsgCommitCoupon("SPRING10", commitment, acc)},
+		{false, NewMsgCommitCoupon(hash, "SPRING10", acc)},
+		{false, NewMsgCommitCoupon(hash, commitment, nil)},
+	}
+
+	for _, tc := range cases {
+		err := tc.tx.ValidateBasic()
+		if tc.valid {
+			require.Nil(t, err)
+		} else {
+			require.NotNil(t, err)
+		}
+	}
+}
+
+func TestMsgSetProductSubscriptionValidation(t *testing.T) {
+	acc := sdk.AccAddress([]byte("me"))
+
+	cases := []struct {
+		valid bool
+		tx    MsgSetProductSubscription
+	}{
+		{true, NewMsgSetProductSubscription("product1", 100, acc)},
+		{true, NewMsgSetProductSubscription("product1", 0, acc)},
+		{false, NewMsgSetProductSubscription("product1", -1, acc)},
+		{false, NewMsgSetProductSubscription("", 100, acc)},
+		{false, NewMsgSetProductSubscription("product1", 100, nil)},
+	}
+
+	for _, tc := range cases {
+		err := tc.tx.ValidateBasic()
+		if tc.valid {
+			require.Nil(t, err)
+		} else {
+			require.NotNil(t, err)
+		}
+	}
+}
+
+func TestMsgBuyProductsValidation(t *testing.T) {
+	acc := sdk.AccAddress([]byte("me"))
+	item1 := NewCartItem("product1", "", "", 0)
+	item2 := NewCartItem("product2", "stake", "SPRING10", 0)
+
+	tooMany := make([]CartItem, MaxCartItems+1)
+	for i := range tooMany {
+		tooMany[i] = NewCartItem(fmt.Sprintf("product%d", i), "", "", 0)
+	}
+
+	cases := []struct {
+		valid bool
+		tx    MsgBuyProducts
+	}{
+		{true, NewMsgBuyProducts([]CartItem{item1, item2}, acc)},
+		{false, NewMsgBuyProducts(nil, acc)},
+		{false, NewMsgBuyProducts([]CartItem{item1, item1}, acc)},
+		{false, NewMsgBuyProducts([]CartItem{item1, NewCartItem("", "", "", 0)}, acc)},
+		{false, NewMsgBuyProducts([]CartItem{NewCartItem("books/product1", "", "", 0)}, acc)},
+		{false, NewMsgBuyProducts([]CartItem{NewCartItem("product1", "1bad", "", 0)}, acc)},
+		{false, NewMsgBuyProducts(tooMany, acc)},
+		{false, NewMsgBuyProducts([]CartItem{item1}, nil)},
+	}
+
+	for _, tc := range cases {
+		err := tc.tx.ValidateBasic()
+		if tc.valid {
+			require.Nil(t, err)
+		} else {
+			require.NotNil(t, err)
+		}
+	}
+}
+
+func TestMsgSetProductLicensingValidation(t *testing.T) {
+	acc := sdk.AccAddress([]byte("me"))
+
+	cases := []struct {
+		valid bool
+		tx    MsgSetProductLicensing
+	}{
+		{true, NewMsgSetProductLicensing("product1", true, 0, acc)},
+		{true, NewMsgSetProductLicensing("product1", true, 1000, acc)},
+		{true, NewMsgSetProductLicensing("product1", false, 0, acc)},
+		{false, NewMsgSetProductLicensing("product1", false, 1000, acc)},
+		{false, NewMsgSetProductLicensing("product1", true, -1, acc)},
+		{false, NewMsgSetProductLicensing("", true, 0, acc)},
+		{false, NewMsgSetProductLicensing("product1", true, 0, nil)},
+	}
+
+	for _, tc := range cases {
+		err := tc.tx.ValidateBasic()
+		if tc.valid {
+			require.Nil(t, err)
+		} else {
+			require.NotNil(t, err)
+		}
+	}
+}
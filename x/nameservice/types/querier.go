@@ -1,6 +1,15 @@
 package types
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// MaxPageLimit is the largest number of records a paginated query returns
+const MaxPageLimit = 100
 
 // QueryResResolve Queries Result Payload for a resolve query
 type QueryResResolve struct {
@@ -21,3 +30,162 @@ func (n QueryResNames) String() string {
 }
 
 type QueryResAllProducts []Product
+
+// QueryPage selects a page of records sorted by key: the first Limit records whose key
+// follows After, from the first record when After is empty. The query reads the records
+// from After on and stops past the end of the page, unless CountTotal asks it to count
+// the matching records of all pages, which reads every one of them.
+type QueryPage struct {
+	After      string `json:"after"`
+	Limit      int    `json:"limit"`
+	CountTotal bool   `json:"count_total"`
+}
+
+// ValidateBasic checks the limit of the page
+func (p QueryPage) ValidateBasic() error {
+	if p.Limit < 0 || p.Limit > MaxPageLimit {
+		return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "limit must be between 0 and %d", MaxPageLimit)
+	}
+	return nil
+}
+
+// Includes returns whether a key follows the start of the page
+func (p QueryPage) Includes(key string) bool {
+	return p.After == "" || key > p.After
+}
+
+// QueryNamesPageParams selects a page of the names owned by Owner, of all names when
+// Owner is empty
+type QueryNamesPageParams struct {
+	Owner sdk.AccAddress `json:"owner"`
+	Page  QueryPage      `json:"page"`
+}
+
+// NamedWhois is the record of a name
+type NamedWhois struct {
+	Name  string `json:"name"`
+	Whois Whois  `json:"whois"`
+}
+
+// QueryResNamesPage Queries Result Payload for a page of names, Total counts the names
+// matching the query across all pages when the page asked for it
+type QueryResNamesPage struct {
+	Names   []NamedWhois `json:"names"`
+	Total   uint64       `json:"total"`
+	HasNext bool         `json:"has_next"`
+}
+
+// QueryProductsPageParams selects a page of the products matching Filter, sorted by ID
+type QueryProductsPageParams struct {
+	Filter QueryProductsParams `json:"filter"`
+	Page   QueryPage           `json:"page"`
+}
+
+// QueryResProductsPage Queries Result Payload for a page of products, Total counts the
+// products matching the query across all pages when the page asked for it
+type QueryResProductsPage struct {
+	Products []Product `json:"products"`
+	Total    uint64    `json:"total"`
+	HasNext  bool      `json:"has_next"`
+}
+
+// QueryProductsParams defines the filters of a products query, empty fields match every product
+type QueryProductsParams struct {
+	Category string         `json:"category"`
+	Tag      string         `json:"tag"`
+	Owner    sdk.AccAddress `json:"owner"`
+	MinPrice sdk.Coins      `json:"min_price"`
+	MaxPrice sdk.Coins      `json:"max_price"`
+	Listed   bool           `json:"listed"`
+	// Storefront restricts the products to the ones published under a name
+	Storefront string `json:"storefront"`
+}
+
+// NewQueryProductsParams creates a new instance of QueryProductsParams
+func NewQueryProductsParams(category, tag string, owner sdk.AccAddress, minPrice, maxPrice sdk.Coins, listed bool) QueryProductsParams {
+	return QueryProductsParams{
+		Category: category,
+		Tag:      tag,
+		Owner:    owner,
+		MinPrice: minPrice,
+		MaxPrice: maxPrice,
+		Listed:   listed,
+	}
+}
+
+// Matches returns whether the product passes all filters
+func (p QueryProductsParams) Matches(product Product) bool {
+	if p.Category != "" && product.Category != p.Category {
+		return false
+	}
+	if p.Tag != "" && !product.HasTag(p.Tag) {
+		return false
+	}
+	if !p.Owner.Empty() && !product.Owner.Equals(p.Owner) {
+		return false
+	}
+	if !p.MinPrice.Empty() && !product.Price.IsAllGTE(p.MinPrice) {
+		return false
+	}
+	if !p.MaxPrice.Empty() && !p.MaxPrice.IsAllGTE(product.Price) {
+		return false
+	}
+	if p.Listed && !product.Listed {
+		return false
+	}
+	if p.Storefront != "" && product.Storefront != p.Storefront {
+		return false
+	}
+	return true
+}
+
+type QueryResAuctions []Auction
+
+type QueryResBids []Bid
+
+type QueryResReviews []Review
+
+// QueryResRating Queries Result Payload for a product rating or seller reputation query
+type QueryResRating struct {
+	Count   uint64  `json:"count"`
+	Average sdk.Dec `json:"average"`
+}
+
+// NewQueryResRating creates the query result of an aggregated rating
+func NewQueryResRating(rating Rating) QueryResRating {
+	return QueryResRating{Count: rating.Count, Average: rating.Average()}
+}
+
+// implement fmt.Stringer
+func (r QueryResRating) String() string {
+	return strings.TrimSpace(fmt.Sprintf(`Reviews: %d
+Average: %s`, r.Count, r.Average))
+}
+
+type QueryResSubscriptions []Subscription
+
+type QueryResProductHistory []ProductHistoryEntry
+
+// QueryResSubscriptionStatus Queries Result Payload for a subscription status query
+type QueryResSubscriptionStatus struct {
+	Active       bool         `json:"active"`
+	Subscription Subscription `json:"subscription"`
+}
+
+// implement fmt.Stringer
+func (r QueryResSubscriptionStatus) String() string {
+	return strings.TrimSpace(fmt.Sprintf(`Active: %t
+%s`, r.Active, r.Subscription))
+}
+
+// QueryResHasLicense Queries Result Payload for a license access check
+type QueryResHasLicense struct {
+	HasLicense bool    `json:"has_license"`
+	License    License `json:"license"`
+}
+
+// implement fmt.Stringer
+func (r QueryResHasLicense) String() string {
+	return strings.TrimSpace(fmt.Sprintf(`HasLicense: %t
+%s`, r.HasLicense, r.License))
+}
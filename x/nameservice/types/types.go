@@ -1,20 +1,31 @@
 package types
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"mime"
+	"net/url"
+	"regexp"
 	"strings"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 )
 
 // MinNamePrice is Initial Starting Price for a name that was never previously owned
 var MinNamePrice = sdk.Coins{sdk.NewInt64Coin("nametoken", 1)}
 
+// MaxProductTags is the maximum number of tags a product can be labelled with
+const MaxProductTags = 10
+
 // Whois is a struct that contains all the metadata of a name
 type Whois struct {
 	Value string         `json:"value"`
 	Owner sdk.AccAddress `json:"owner"`
 	Price sdk.Coins      `json:"price"`
+	// StorefrontIncluded makes a sale of the name hand the products published under it to the buyer
+	StorefrontIncluded bool `json:"storefront_included"`
 }
 
 // NewWhois returns a new Whois with the minprice as the price
@@ -28,7 +39,8 @@ func NewWhois() Whois {
 func (w Whois) String() string {
 	return strings.TrimSpace(fmt.Sprintf(`Owner: %s
 Value: %s
-Price: %s`, w.Owner, w.Value, w.Price))
+Price: %s
+Storefront Included: %t`, w.Owner, w.Value, w.Price, w.StorefrontIncluded))
 }
 
 type Product struct {
@@ -36,8 +48,514 @@ type Product struct {
 	Description string         `json:"description"`
 	Owner       sdk.AccAddress `json:"owner"`
 	Price       sdk.Coins      `json:"price"`
+	Listed      bool           `json:"listed"`
+	Category    string         `json:"category"`
+	Tags        []string       `json:"tags"`
+	Storefront  string         `json:"storefront"` // name the product is published under, if any
+	Content     Content        `json:"content"`
+	// AcceptedPrices are alternative prices, each one a full price in its own denomination
+	AcceptedPrices sdk.Coins `json:"accepted_prices"`
+	// ReferencePrice pegs the price to a reference unit converted through the price feed
+	ReferencePrice sdk.DecCoins `json:"reference_price"`
+	// SubscriptionPeriod is the number of blocks a subscription payment lasts, zero for one-shot sales
+	SubscriptionPeriod int64 `json:"subscription_period"`
+	// Licensing products sell non-transferable licenses while the owner keeps the product
+	Licensing bool `json:"licensing"`
+	// LicenseDuration is the number of blocks a license lasts, zero for perpetual licenses
+	LicenseDuration int64 `json:"license_duration"`
+	// Version is bumped every time the owner changes the terms of the product
+	Version uint64 `json:"version"`
 }
 
 func NewProduct() Product {
 	return Product{}
 }
+
+// Address returns the name/productID address of a product published in a storefront
+func (p Product) Address() string {
+	if p.Storefront == "" {
+		return ""
+	}
+	return p.Storefront + IndexSeparator + p.ProductID
+}
+
+// IsSubscription returns whether buying the product subscribes to it instead of transferring it
+func (p Product) IsSubscription() bool {
+	return p.SubscriptionPeriod > 0
+}
+
+// IsPegged returns whether the price is expressed in a reference unit
+func (p Product) IsPegged() bool {
+	return !p.ReferencePrice.Empty()
+}
+
+// HasTag returns whether the product is labelled with the given tag
+func (p Product) HasTag(tag string) bool {
+	for _, t := range p.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	// AuctionEnglish is an ascending price auction closed at its end height
+	AuctionEnglish = "english"
+	// AuctionDutch is a descending price auction won by the first bidder
+	AuctionDutch = "dutch"
+)
+
+// Bid is a single offer placed on an auction
+type Bid struct {
+	Bidder sdk.AccAddress `json:"bidder"`
+	Amount sdk.Coins      `json:"amount"`
+	Height int64          `json:"height"`
+}
+
+// implement fmt.Stringer
+func (b Bid) String() string {
+	return strings.TrimSpace(fmt.Sprintf(`Bidder: %s
+Amount: %s
+Height: %d`, b.Bidder, b.Amount, b.Height))
+}
+
+// Auction is a struct that contains all the metadata of a product auction
+type Auction struct {
+	ProductID    string         `json:"productID"`
+	Seller       sdk.AccAddress `json:"seller"`
+	AuctionType  string         `json:"auction_type"`
+	StartPrice   sdk.Coins      `json:"start_price"`
+	ReservePrice sdk.Coins      `json:"reserve_price"`
+	Decrement    sdk.Coins      `json:"decrement"`
+	StartHeight  int64          `json:"start_height"`
+	EndHeight    int64          `json:"end_height"`
+	Bids         []Bid          `json:"bids"`
+}
+
+// NewAuction returns a new Auction without any bids
+func NewAuction(productID string, seller sdk.AccAddress, auctionType string, startPrice, reservePrice, decrement sdk.Coins, startHeight, endHeight int64) Auction {
+	return Auction{
+		ProductID:    productID,
+		Seller:       seller,
+		AuctionType:  auctionType,
+		StartPrice:   startPrice,
+		ReservePrice: reservePrice,
+		Decrement:    decrement,
+		StartHeight:  startHeight,
+		EndHeight:    endHeight,
+		Bids:         []Bid{},
+	}
+}
+
+// HighestBid returns the latest accepted bid, which is always the highest one
+func (a Auction) HighestBid() (Bid, bool) {
+	if len(a.Bids) == 0 {
+		return Bid{}, false
+	}
+	return a.Bids[len(a.Bids)-1], true
+}
+
+// CurrentPrice returns the price a bid has to reach at the given height.
+// For english auctions this is the start price, for dutch auctions the start
+// price lowered by the decrement for every block elapsed, never going below
+// the reserve price.
+func (a Auction) CurrentPrice(height int64) sdk.Coins {
+	if a.AuctionType != AuctionDutch || height <= a.StartHeight {
+		return a.StartPrice
+	}
+
+	elapsed := height - a.StartHeight
+	price := sdk.NewCoins()
+	for _, coin := range a.StartPrice {
+		amount := coin.Amount.Sub(a.Decrement.AmountOf(coin.Denom).MulRaw(elapsed))
+		if reserve := a.ReservePrice.AmountOf(coin.Denom); amount.LT(reserve) {
+			amount = reserve
+		}
+		price = price.Add(sdk.NewCoin(coin.Denom, amount))
+	}
+	return price
+}
+
+// IsFinished returns whether the auction has to be settled at the given height
+func (a Auction) IsFinished(height int64) bool {
+	if _, ok := a.HighestBid(); ok && a.AuctionType == AuctionDutch {
+		return true
+	}
+	return height >= a.EndHeight
+}
+
+// implement fmt.Stringer
+func (a Auction) String() string {
+	return strings.TrimSpace(fmt.Sprintf(`ProductID: %s
+Seller: %s
+Type: %s
+Start Price: %s
+Reserve Price: %s
+Decrement: %s
+Start Height: %d
+End Height: %d
+Bids: %d`, a.ProductID, a.Seller, a.AuctionType, a.StartPrice, a.ReservePrice, a.Decrement,
+		a.StartHeight, a.EndHeight, len(a.Bids)))
+}
+
+// MaxContentURILength is the maximum length of the URI of off-chain product content
+const MaxContentURILength = 2048
+
+var (
+	sha256Pattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+	cidV0Pattern  = regexp.MustCompile(`^Qm[1-9A-HJ-NP-Za-km-z]{44}$`)
+	cidV1Pattern  = regexp.MustCompile(`^b[a-z2-7]{58,}$`)
+)
+
+// Content describes off-chain content (an image, a document...) delivered with a product.
+// Hash is either the hex encoded SHA-256 digest of the content or its IPFS CID, so buyers
+// can verify what they received.
+type Content struct {
+	Hash     string `json:"hash"`
+	URI      string `json:"uri"`
+	MimeType string `json:"mime_type"`
+	Size     uint64 `json:"size"`
+}
+
+// NewContent returns a new Content
+func NewContent(hash, uri, mimeType string, size uint64) Content {
+	return Content{
+		Hash:     hash,
+		URI:      uri,
+		MimeType: mimeType,
+		Size:     size,
+	}
+}
+
+// IsEmpty returns whether no content is attached
+func (c Content) IsEmpty() bool {
+	return c == Content{}
+}
+
+// IsSHA256 returns whether the content is addressed by its SHA-256 digest rather than a CID
+func (c Content) IsSHA256() bool {
+	return sha256Pattern.MatchString(c.Hash)
+}
+
+// ValidateBasic runs stateless checks on the content metadata
+func (c Content) ValidateBasic() error {
+	if c.IsEmpty() {
+		return nil
+	}
+	if !c.IsSHA256() && !cidV0Pattern.MatchString(c.Hash) && !cidV1Pattern.MatchString(c.Hash) {
+		return sdkerrors.Wrap(ErrInvalidContent, "hash must be a hex SHA-256 digest or a CID")
+	}
+	if len(c.URI) > MaxContentURILength {
+		return sdkerrors.Wrapf(ErrInvalidContent, "uri cannot be longer than %d characters", MaxContentURILength)
+	}
+	if c.URI != "" {
+		if u, err := url.Parse(c.URI); err != nil || u.Scheme == "" {
+			return sdkerrors.Wrapf(ErrInvalidContent, "invalid uri %s", c.URI)
+		}
+	}
+	if c.MimeType != "" {
+		if _, _, err := mime.ParseMediaType(c.MimeType); err != nil {
+			return sdkerrors.Wrapf(ErrInvalidContent, "invalid mime type %s", c.MimeType)
+		}
+	}
+	return nil
+}
+
+// implement fmt.Stringer
+func (c Content) String() string {
+	return strings.TrimSpace(fmt.Sprintf(`Hash: %s
+URI: %s
+Mime Type: %s
+Size: %d`, c.Hash, c.URI, c.MimeType, c.Size))
+}
+
+const (
+	// MinRating is the lowest rating a review can give
+	MinRating = 1
+	// MaxRating is the highest rating a review can give
+	MaxRating = 5
+	// MaxReviewLength is the maximum length of the text of a review
+	MaxReviewLength = 280
+)
+
+// Purchase records that a buyer acquired a product from a seller
+type Purchase struct {
+	ProductID string         `json:"productID"`
+	Buyer     sdk.AccAddress `json:"buyer"`
+	Seller    sdk.AccAddress `json:"seller"`
+	Price     sdk.Coins      `json:"price"`
+	Height    int64          `json:"height"`
+}
+
+// Review is a verified-purchase review of a product
+type Review struct {
+	ProductID string         `json:"productID"`
+	Reviewer  sdk.AccAddress `json:"reviewer"`
+	Seller    sdk.AccAddress `json:"seller"`
+	Rating    uint8          `json:"rating"`
+	Text      string         `json:"text"`
+	Height    int64          `json:"height"`
+}
+
+// implement fmt.Stringer
+func (r Review) String() string {
+	return strings.TrimSpace(fmt.Sprintf(`ProductID: %s
+Reviewer: %s
+Seller: %s
+Rating: %d
+Text: %s`, r.ProductID, r.Reviewer, r.Seller, r.Rating, r.Text))
+}
+
+// Rating aggregates the reviews of a product or a seller
+type Rating struct {
+	Count uint64 `json:"count"`
+	Total uint64 `json:"total"`
+}
+
+// Add returns the rating with one more review of the given score
+func (r Rating) Add(score uint8) Rating {
+	return Rating{Count: r.Count + 1, Total: r.Total + uint64(score)}
+}
+
+// Remove takes the score of a review out of the aggregate
+func (r Rating) Remove(score uint8) Rating {
+	if r.Count == 0 {
+		return r
+	}
+	return Rating{Count: r.Count - 1, Total: r.Total - uint64(score)}
+}
+
+// Average returns the average score of the reviews, zero without reviews
+func (r Rating) Average() sdk.Dec {
+	if r.Count == 0 {
+		return sdk.ZeroDec()
+	}
+	return sdk.NewDecFromInt(sdk.NewIntFromUint64(r.Total)).QuoInt(sdk.NewIntFromUint64(r.Count))
+}
+
+// implement fmt.Stringer
+func (r Rating) String() string {
+	return strings.TrimSpace(fmt.Sprintf(`Reviews: %d
+Average: %s`, r.Count, r.Average()))
+}
+
+// MaxCouponPercent is the largest percentage discount a coupon can grant
+const MaxCouponPercent = 100
+
+// Coupon is a seller-issued discount redeemable when buying a product. Coupons
+// are stored under the hash of their code so the code itself is only revealed
+// when it is redeemed.
+type Coupon struct {
+	CodeHash     string         `json:"code_hash"`
+	Issuer       sdk.AccAddress `json:"issuer"`
+	ProductID    string         `json:"productID"`     // empty for any product of the issuer
+	Percent      uint64         `json:"percent"`       // percentage discount, zero for a fixed discount
+	Amount       sdk.Coins      `json:"amount"`        // fixed discount, empty for a percentage discount
+	ExpiryHeight int64          `json:"expiry_height"` // zero for a coupon that never expires
+	MaxUses      uint64         `json:"max_uses"`      // zero for unlimited uses
+	Uses         uint64         `json:"uses"`
+}
+
+// HashCouponCode returns the hex encoded sha256 hash under which a coupon code is stored
+func HashCouponCode(code string) string {
+	hash := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(hash[:])
+}
+
+// HashCouponCommitment returns the hex encoded sha256 hash a buyer commits to before
+// redeeming a coupon code. It binds the code to the buyer, so seeing the code revealed
+// by a purchase is of no use to anyone who did not commit to it beforehand.
+func HashCouponCommitment(code string, buyer sdk.AccAddress) string {
+	hash := sha256.Sum256([]byte(buyer.String() + ":" + code))
+	return hex.EncodeToString(hash[:])
+}
+
+// CouponCommitment records that a buyer committed to the code of a coupon at a height.
+// Only the hash of the code with the buyer address is known until the code is revealed.
+type CouponCommitment struct {
+	CodeHash   string         `json:"code_hash"`
+	Commitment string         `json:"commitment"`
+	Signer     sdk.AccAddress `json:"signer"`
+	Height     int64          `json:"height"`
+}
+
+// implement fmt.Stringer
+func (c CouponCommitment) String() string {
+	return strings.TrimSpace(fmt.Sprintf(`CodeHash: %s
+Commitment: %s
+Signer: %s
+Height: %d`, c.CodeHash, c.Commitment, c.Signer, c.Height))
+}
+
+// IsExpired reports whether the coupon can no longer be redeemed at the given height
+func (c Coupon) IsExpired(height int64) bool {
+	return c.ExpiryHeight != 0 && height > c.ExpiryHeight
+}
+
+// IsUsedUp reports whether the coupon has been redeemed its maximum number of times
+func (c Coupon) IsUsedUp() bool {
+	return c.MaxUses != 0 && c.Uses >= c.MaxUses
+}
+
+// CanRedeem checks that the coupon applies to a product at the given height
+func (c Coupon) CanRedeem(product Product, height int64) error {
+	switch {
+	case !c.Issuer.Equals(product.Owner):
+		return sdkerrors.Wrap(ErrCouponNotApplicable, "coupon was not issued by the product seller")
+	case c.ProductID != "" && c.ProductID != product.ProductID:
+		return sdkerrors.Wrapf(ErrCouponNotApplicable, "coupon is only valid for product %s", c.ProductID)
+	case c.IsExpired(height):
+		return sdkerrors.Wrapf(ErrCouponNotApplicable, "coupon expired at height %d", c.ExpiryHeight)
+	case c.IsUsedUp():
+		return sdkerrors.Wrap(ErrCouponNotApplicable, "coupon has no uses left")
+	}
+	return nil
+}
+
+// Apply returns the price after the coupon discount. Fixed discounts only
+// reduce the price in matching denominations and never below zero.
+func (c Coupon) Apply(price sdk.Coins) sdk.Coins {
+	var discounted sdk.Coins
+	for _, coin := range price {
+		amount := coin.Amount
+		if c.Percent > 0 {
+			discount := amount.MulRaw(int64(c.Percent)).QuoRaw(MaxCouponPercent)
+			amount = amount.Sub(discount)
+		} else {
+			amount = amount.Sub(sdk.MinInt(amount, c.Amount.AmountOf(coin.Denom)))
+		}
+		discounted = discounted.Add(sdk.NewCoin(coin.Denom, amount))
+	}
+	return discounted
+}
+
+// implement fmt.Stringer
+func (c Coupon) String() string {
+	return strings.TrimSpace(fmt.Sprintf(`CodeHash: %s
+Issuer: %s
+ProductID: %s
+Percent: %d
+Amount: %s
+ExpiryHeight: %d
+MaxUses: %d
+Uses: %d`, c.CodeHash, c.Issuer, c.ProductID, c.Percent, c.Amount, c.ExpiryHeight, c.MaxUses, c.Uses))
+}
+
+// Subscription is a recurring payment for a subscription product. The price and
+// period are fixed when subscribing and the subscription is renewed every period
+// until it is cancelled.
+type Subscription struct {
+	ProductID   string         `json:"productID"`
+	Subscriber  sdk.AccAddress `json:"subscriber"`
+	Price       sdk.Coins      `json:"price"`
+	Period      int64          `json:"period"`
+	StartHeight int64          `json:"start_height"`
+	PaidUntil   int64          `json:"paid_until"`
+	Cancelled   bool           `json:"cancelled"` // a cancelled subscription is not renewed when it runs out
+}
+
+// NewSubscription returns a subscription whose first period has been paid at the given height
+func NewSubscription(productID string, subscriber sdk.AccAddress, price sdk.Coins, period int64, height int64) Subscription {
+	return Subscription{
+		ProductID:   productID,
+		Subscriber:  subscriber,
+		Price:       price,
+		Period:      period,
+		StartHeight: height,
+		PaidUntil:   height + period,
+	}
+}
+
+// IsActive reports whether the subscriber has paid for access at the given height
+func (s Subscription) IsActive(height int64) bool {
+	return height < s.PaidUntil
+}
+
+// IsDue reports whether the subscription has to be renewed or closed at the given height
+func (s Subscription) IsDue(height int64) bool {
+	return height >= s.PaidUntil
+}
+
+// implement fmt.Stringer
+func (s Subscription) String() string {
+	return strings.TrimSpace(fmt.Sprintf(`ProductID: %s
+Subscriber: %s
+Price: %s
+Period: %d
+StartHeight: %d
+PaidUntil: %d
+Cancelled: %t`, s.ProductID, s.Subscriber, s.Price, s.Period, s.StartHeight, s.PaidUntil, s.Cancelled))
+}
+
+// ProductHistoryEntry records one version of a product in its update audit trail
+type ProductHistoryEntry struct {
+	ProductID       string    `json:"productID"`
+	Version         uint64    `json:"version"`
+	Height          int64     `json:"height"`
+	OldPrice        sdk.Coins `json:"old_price"`
+	NewPrice        sdk.Coins `json:"new_price"`
+	DescriptionHash string    `json:"description_hash"` // hex encoded sha256 hash of the new description
+}
+
+// NewProductHistoryEntry returns the history entry of a product changing from previous to its current version
+func NewProductHistoryEntry(previous, product Product, height int64) ProductHistoryEntry {
+	hash := sha256.Sum256([]byte(product.Description))
+	return ProductHistoryEntry{
+		ProductID:       product.ProductID,
+		Version:         product.Version,
+		Height:          height,
+		OldPrice:        previous.Price,
+		NewPrice:        product.Price,
+		DescriptionHash: hex.EncodeToString(hash[:]),
+	}
+}
+
+// implement fmt.Stringer
+func (e ProductHistoryEntry) String() string {
+	return strings.TrimSpace(fmt.Sprintf(`ProductID: %s
+Version: %d
+Height: %d
+OldPrice: %s
+NewPrice: %s
+DescriptionHash: %s`, e.ProductID, e.Version, e.Height, e.OldPrice, e.NewPrice, e.DescriptionHash))
+}
+
+// License is the non-transferable right of a buyer to use a licensing product
+type License struct {
+	ProductID    string         `json:"productID"`
+	Holder       sdk.AccAddress `json:"holder"`
+	Height       int64          `json:"height"`
+	ExpiryHeight int64          `json:"expiry_height"` // zero for a perpetual license
+}
+
+// NewLicense returns a license granted at the given height, lasting duration blocks or forever if zero
+func NewLicense(productID string, holder sdk.AccAddress, height int64, duration int64) License {
+	license := License{
+		ProductID: productID,
+		Holder:    holder,
+		Height:    height,
+	}
+	if duration > 0 {
+		license.ExpiryHeight = height + duration
+	}
+	return license
+}
+
+// IsPerpetual returns whether the license never expires
+func (l License) IsPerpetual() bool {
+	return l.ExpiryHeight == 0
+}
+
+// IsValid reports whether the license grants access at the given height
+func (l License) IsValid(height int64) bool {
+	return l.IsPerpetual() || height < l.ExpiryHeight
+}
+
+// implement fmt.Stringer
+func (l License) String() string {
+	return strings.TrimSpace(fmt.Sprintf(`ProductID: %s
+Holder: %s
+Height: %d
+ExpiryHeight: %d`, l.ProductID, l.Holder, l.Height, l.ExpiryHeight))
+}
@@ -0,0 +1,215 @@
+package types
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuctionCurrentPrice(t *testing.T) {
+	acc := sdk.AccAddress([]byte("me"))
+	price := sdk.NewCoins(sdk.NewInt64Coin("nametoken", 100))
+	reserve := sdk.NewCoins(sdk.NewInt64Coin("nametoken", 40))
+	decrement := sdk.NewCoins(sdk.NewInt64Coin("nametoken", 15))
+
+	english := NewAuction("product1", acc, AuctionEnglish, price, nil, nil, 10, 20)
+	require.Equal(t, price, english.CurrentPrice(15))
+
+	dutch := NewAuction("product1", acc, AuctionDutch, price, reserve, decrement, 10, 20)
+	require.Equal(t, price, dutch.CurrentPrice(10))
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin("nametoken", 70)), dutch.CurrentPrice(12))
+	require.Equal(t, reserve, dutch.CurrentPrice(19))
+}
+
+func TestAuctionIsFinished(t *testing.T) {
+	acc := sdk.AccAddress([]byte("me"))
+	price := sdk.NewCoins(sdk.NewInt64Coin("nametoken", 100))
+
+	english := NewAuction("product1", acc, AuctionEnglish, price, nil, nil, 10, 20)
+	english.Bids = append(english.Bids, Bid{Bidder: acc, Amount: price, Height: 11})
+	require.False(t, english.IsFinished(19))
+	require.True(t, english.IsFinished(20))
+
+	dutch := NewAuction("product1", acc, AuctionDutch, price, nil, price, 10, 20)
+	require.False(t, dutch.IsFinished(11))
+	dutch.Bids = append(dutch.Bids, Bid{Bidder: acc, Amount: price, Height: 11})
+	require.True(t, dutch.IsFinished(11))
+}
+
+func TestQueryProductsParamsMatches(t *testing.T) {
+	acc := sdk.AccAddress([]byte("me"))
+	acc2 := sdk.AccAddress([]byte("you"))
+	product := Product{
+		ProductID: "product1",
+		Owner:     acc,
+		Price:     sdk.NewCoins(sdk.NewInt64Coin("nametoken", 10)),
+		Listed:    true,
+		Category:  "books",
+		Tags:      []string{"fantasy", "used"},
+	}
+
+	cases := []struct {
+		match  bool
+		params QueryProductsParams
+	}{
+		{true, QueryProductsParams{}},
+		{true, NewQueryProductsParams("books", "used", acc, nil, nil, true)},
+		{false, NewQueryProductsParams("music", "", nil, nil, nil, false)},
+		{false, NewQueryProductsParams("", "new", nil, nil, nil, false)},
+		{false, NewQueryProductsParams("", "", acc2, nil, nil, false)},
+		{true, NewQueryProductsParams("", "", nil, sdk.NewCoins(sdk.NewInt64Coin("nametoken", 10)), sdk.NewCoins(sdk.NewInt64Coin("nametoken", 10)), false)},
+		{false, NewQueryProductsParams("", "", nil, sdk.NewCoins(sdk.NewInt64Coin("nametoken", 11)), nil, false)},
+		{false, NewQueryProductsParams("", "", nil, nil, sdk.NewCoins(sdk.NewInt64Coin("nametoken", 9)), false)},
+	}
+
+	for _, tc := range cases {
+		require.Equal(t, tc.match, tc.params.Matches(product))
+	}
+}
+
+func TestContentValidateBasic(t *testing.T) {
+	digest := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	cid := "QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG"
+
+	cases := []struct {
+		valid   bool
+		content Content
+	}{
+		{true, Content{}},
+		{true, NewContent(digest, "", "", 0)},
+		{true, NewContent(cid, "ipfs://"+cid, "image/png", 2048)},
+		{true, NewContent(digest, "https://example.com/book.pdf", "application/pdf", 1024)},
+		{false, NewContent("", "https://example.com/book.pdf", "", 0)},
+		{false, NewContent("not-a-hash", "", "", 0)},
+		{false, NewContent(digest, "example.com/book.pdf", "", 0)},
+		{false, NewContent(digest, "", "pdf;;", 0)},
+	}
+
+	for _, tc := range cases {
+		err := tc.content.ValidateBasic()
+		if tc.valid {
+			require.Nil(t, err)
+		} else {
+			require.NotNil(t, err)
+		}
+	}
+}
+
+func TestRatingAverage(t *testing.T) {
+	var rating Rating
+	require.True(t, rating.Average().IsZero())
+
+	rating = rating.Add(5).Add(4).Add(4)
+	require.Equal(t, uint64(3), rating.Count)
+	require.Equal(t, "4.333333333333333333", rating.Average().String())
+
+	rating = rating.Remove(5)
+	require.Equal(t, Rating{Count: 2, Total: 8}, rating)
+	require.Equal(t, Rating{}, Rating{}.Remove(5))
+}
+
+func TestCouponApply(t *testing.T) {
+	price := sdk.NewCoins(sdk.NewInt64Coin("nametoken", 100), sdk.NewInt64Coin("stake", 7))
+
+	percent := Coupon{Percent: 25}
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin("nametoken", 75), sdk.NewInt64Coin("stake", 6)), percent.Apply(price))
+
+	fixed := Coupon{Amount: sdk.NewCoins(sdk.NewInt64Coin("nametoken", 30))}
+	require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin("nametoken", 70), sdk.NewInt64Coin("stake", 7)), fixed.Apply(price))
+
+	free := Coupon{Amount: sdk.NewCoins(sdk.NewInt64Coin("nametoken", 500), sdk.NewInt64Coin("stake", 500))}
+	require.True(t, free.Apply(price).Empty())
+}
+
+func TestCouponCanRedeem(t *testing.T) {
+	seller := sdk.AccAddress([]byte("seller"))
+	product := Product{ProductID: "product1", Owner: seller}
+
+	cases := []struct {
+		valid  bool
+		coupon Coupon
+	}{
+		{true, Coupon{Issuer: seller}},
+		{true, Coupon{Issuer: seller, ProductID: "product1", ExpiryHeight: 10, MaxUses: 2, Uses: 1}},
+		{false, Coupon{Issuer: sdk.AccAddress([]byte("other"))}},
+		{false, Coupon{Issuer: seller, ProductID: "product2"}},
+		{false, Coupon{Issuer: seller, ExpiryHeight: 9}},
+		{false, Coupon{Issuer: seller, MaxUses: 2, Uses: 2}},
+	}
+
+	for _, tc := range cases {
+		err := tc.coupon.CanRedeem(product, 10)
+		if tc.valid {
+			require.Nil(t, err)
+		} else {
+			require.NotNil(t, err)
+		}
+	}
+}
+
+func TestSubscriptionPeriods(t *testing.T) {
+	price := sdk.NewCoins(sdk.NewInt64Coin("nametoken", 10))
+	subscription := NewSubscription("product1", sdk.AccAddress([]byte("me")), price, 100, 50)
+
+	require.Equal(t, int64(150), subscription.PaidUntil)
+	require.True(t, subscription.IsActive(149))
+	require.False(t, subscription.IsDue(149))
+	require.False(t, subscription.IsActive(150))
+	require.True(t, subscription.IsDue(150))
+}
+
+func TestNewProductHistoryEntry(t *testing.T) {
+	previous := Product{ProductID: "product1", Description: "old", Price: sdk.NewCoins(sdk.NewInt64Coin("nametoken", 10)), Version: 1}
+	product := previous
+	product.Description = "new"
+	product.Price = sdk.NewCoins(sdk.NewInt64Coin("nametoken", 12))
+	product.Version = 2
+
+	entry := NewProductHistoryEntry(previous, product, 42)
+	require.Equal(t, uint64(2), entry.Version)
+	require.Equal(t, int64(42), entry.Height)
+	require.Equal(t, previous.Price, entry.OldPrice)
+	require.Equal(t, product.Price, entry.NewPrice)
+	require.Equal(t, "11507a0e2f5e69d5dfa40a62a1bd7b6ee57e6bcd85c67c9b8431b36fff21c437", entry.DescriptionHash)
+}
+
+func TestProductHistoryKeyOrder(t *testing.T) {
+	require.True(t, string(ProductHistoryKey("product1", 9)) < string(ProductHistoryKey("product1", 10)))
+}
+
+func TestLicenseValidity(t *testing.T) {
+	holder := sdk.AccAddress([]byte("me"))
+
+	perpetual := NewLicense("product1", holder, 10, 0)
+	require.True(t, perpetual.IsPerpetual())
+	require.True(t, perpetual.IsValid(1000000))
+
+	timed := NewLicense("product1", holder, 10, 100)
+	require.Equal(t, int64(110), timed.ExpiryHeight)
+	require.True(t, timed.IsValid(109))
+	require.False(t, timed.IsValid(110))
+}
+
+func TestValidateGenesis(t *testing.T) {
+	acc := sdk.AccAddress([]byte("me"))
+	price := sdk.NewCoins(sdk.NewInt64Coin("nametoken", 10))
+
+	require.NoError(t, ValidateGenesis(DefaultGenesisState()))
+
+	// a bought name does not need a value
+	genesis := DefaultGenesisState()
+	genesis.WhoisRecords = []WhoisRecord{{Name: "name1", Whois: Whois{Owner: acc, Price: price}}}
+	genesis.Products = []Product{{ProductID: "product1", Owner: acc, Price: price, Storefront: "name1"}}
+	require.NoError(t, ValidateGenesis(genesis))
+
+	genesis.WhoisRecords = append(genesis.WhoisRecords, genesis.WhoisRecords[0])
+	require.Error(t, ValidateGenesis(genesis))
+
+	genesis.WhoisRecords = nil
+	require.Error(t, ValidateGenesis(genesis))
+
+	genesis = DefaultGenesisState()
+	genesis.Auctions = []Auction{NewAuction("product1", acc, AuctionEnglish, price, nil, nil, 1, 10)}
+	require.Error(t, ValidateGenesis(genesis))
+}
@@ -0,0 +1,42 @@
+package pricefeed
+
+import (
+	"github.com/cosmos/sdk-tutorials/nameservice/x/pricefeed/keeper"
+	"github.com/cosmos/sdk-tutorials/nameservice/x/pricefeed/types"
+)
+
+const (
+	ModuleName   = types.ModuleName
+	RouterKey    = types.RouterKey
+	StoreKey     = types.StoreKey
+	QuerierRoute = types.QuerierRoute
+
+	DefaultParamspace = types.DefaultParamspace
+)
+
+var (
+	NewKeeper           = keeper.NewKeeper
+	NewQuerier          = keeper.NewQuerier
+	NewMsgPostPrice     = types.NewMsgPostPrice
+	NewMsgAddFeeder     = types.NewMsgAddFeeder
+	NewMsgRemoveFeeder  = types.NewMsgRemoveFeeder
+	NewGenesisState     = types.NewGenesisState
+	DefaultGenesisState = types.DefaultGenesisState
+	NewParams           = types.NewParams
+	DefaultParams       = types.DefaultParams
+	ValidateGenesis     = types.ValidateGenesis
+	ModuleCdc           = types.ModuleCdc
+	RegisterCodec       = types.RegisterCodec
+)
+
+type (
+	Keeper          = keeper.Keeper
+	MsgPostPrice    = types.MsgPostPrice
+	MsgAddFeeder    = types.MsgAddFeeder
+	MsgRemoveFeeder = types.MsgRemoveFeeder
+	Price           = types.Price
+	GenesisState    = types.GenesisState
+	Params          = types.Params
+	QueryResPrices  = types.QueryResPrices
+	QueryResFeeders = types.QueryResFeeders
+)
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/sdk-tutorials/nameservice/x/pricefeed/types"
+	"github.com/spf13/cobra"
+)
+
+func GetQueryCmd(storeKey string, cdc *codec.Codec) *cobra.Command {
+	pricefeedQueryCmd := &cobra.Command{
+		Use:                        types.ModuleName,
+		Short:                      "Querying commands for the pricefeed module",
+		DisableFlagParsing:         true,
+		SuggestionsMinimumDistance: 2,
+		RunE:                       client.ValidateCmd,
+	}
+	pricefeedQueryCmd.AddCommand(flags.GetCommands(
+		GetCmdPrice(storeKey, cdc),
+		GetCmdPrices(storeKey, cdc),
+		GetCmdFeeders(storeKey, cdc),
+	)...)
+
+	return pricefeedQueryCmd
+}
+
+// GetCmdPrice queries the latest price of a denomination
+func GetCmdPrice(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "price [denom] [unit]",
+		Short: "Query the price of a denomination in a unit",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/price/%s/%s", queryRoute, args[0], args[1]), nil)
+			if err != nil {
+				fmt.Printf("could not get price - %s/%s \n", args[0], args[1])
+				return nil
+			}
+
+			var out types.Price
+			cdc.MustUnmarshalJSON(res, &out)
+			return cliCtx.PrintOutput(out)
+		},
+	}
+}
+
+// GetCmdPrices queries all the latest prices
+func GetCmdPrices(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "prices",
+		Short: "Query all prices",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/prices", queryRoute), nil)
+			if err != nil {
+				fmt.Printf("could not get prices\n")
+				return nil
+			}
+
+			var out types.QueryResPrices
+			cdc.MustUnmarshalJSON(res, &out)
+			return cliCtx.PrintOutput(out)
+		},
+	}
+}
+
+// GetCmdFeeders queries the addresses authorised to post prices
+func GetCmdFeeders(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "feeders",
+		Short: "Query the authorised price feeders",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/feeders", queryRoute), nil)
+			if err != nil {
+				fmt.Printf("could not get feeders\n")
+				return nil
+			}
+
+			var out types.QueryResFeeders
+			cdc.MustUnmarshalJSON(res, &out)
+			return cliCtx.PrintOutput(out)
+		},
+	}
+}
@@ -0,0 +1,19 @@
+package rest
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+
+	"github.com/gorilla/mux"
+)
+
+// RegisterRoutes - Central function to define routes that get registered by the main application
+func RegisterRoutes(cliCtx context.CLIContext, r *mux.Router, storeName string) {
+	r.HandleFunc(fmt.Sprintf("/%s/prices", storeName), pricesHandler(cliCtx, storeName)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/%s/prices", storeName), postPriceHandler(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/prices/{denom}/{unit}", storeName), priceHandler(cliCtx, storeName)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/%s/feeders", storeName), feedersHandler(cliCtx, storeName)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/%s/feeders", storeName), addFeederHandler(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/feeders", storeName), removeFeederHandler(cliCtx)).Methods("DELETE")
+}
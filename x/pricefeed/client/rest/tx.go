@@ -0,0 +1,113 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/sdk-tutorials/nameservice/x/pricefeed/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/rest"
+	"github.com/cosmos/cosmos-sdk/x/auth/client/utils"
+)
+
+type postPriceReq struct {
+	BaseReq rest.BaseReq `json:"base_req"`
+	Denom   string       `json:"denom"`
+	Unit    string       `json:"unit"`
+	Price   string       `json:"price"`
+}
+
+func postPriceHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req postPriceReq
+
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		baseReq := req.BaseReq.Sanitize()
+		if !baseReq.ValidateBasic(w) {
+			return
+		}
+
+		feeder, err := sdk.AccAddressFromBech32(req.BaseReq.From)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		price, err := sdk.NewDecFromStr(req.Price)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// create the message
+		msg := types.NewMsgPostPrice(req.Denom, req.Unit, price, feeder)
+		err = msg.ValidateBasic()
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, baseReq, []sdk.Msg{msg})
+	}
+}
+
+type feederReq struct {
+	BaseReq rest.BaseReq `json:"base_req"`
+	Feeder  string       `json:"feeder"`
+}
+
+func addFeederHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return feederHandler(cliCtx, func(feeder, admin sdk.AccAddress) sdk.Msg {
+		return types.NewMsgAddFeeder(feeder, admin)
+	})
+}
+
+func removeFeederHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return feederHandler(cliCtx, func(feeder, admin sdk.AccAddress) sdk.Msg {
+		return types.NewMsgRemoveFeeder(feeder, admin)
+	})
+}
+
+// feederHandler builds the transaction of a feeder change signed by the admin
+func feederHandler(cliCtx context.CLIContext, newMsg func(feeder, admin sdk.AccAddress) sdk.Msg) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req feederReq
+
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		baseReq := req.BaseReq.Sanitize()
+		if !baseReq.ValidateBasic(w) {
+			return
+		}
+
+		admin, err := sdk.AccAddressFromBech32(req.BaseReq.From)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		feeder, err := sdk.AccAddressFromBech32(req.Feeder)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// create the message
+		msg := newMsg(feeder, admin)
+		err = msg.ValidateBasic()
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, baseReq, []sdk.Msg{msg})
+	}
+}
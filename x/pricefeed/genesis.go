@@ -0,0 +1,19 @@
+package pricefeed
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func InitGenesis(ctx sdk.Context, keeper Keeper, data GenesisState) {
+	keeper.SetParams(ctx, data.Params)
+	for _, feeder := range data.Feeders {
+		keeper.SetFeeder(ctx, feeder)
+	}
+	for _, price := range data.Prices {
+		keeper.SetPrice(ctx, price)
+	}
+}
+
+func ExportGenesis(ctx sdk.Context, k Keeper) GenesisState {
+	return NewGenesisState(k.GetParams(ctx), k.GetFeeders(ctx), k.GetPrices(ctx))
+}
@@ -0,0 +1,75 @@
+package pricefeed
+
+import (
+	"fmt"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/pricefeed/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// NewHandler returns a handler for "pricefeed" type messages.
+func NewHandler(keeper Keeper) sdk.Handler {
+	return func(ctx sdk.Context, msg sdk.Msg) (*sdk.Result, error) {
+		switch msg := msg.(type) {
+		case MsgPostPrice:
+			return handleMsgPostPrice(ctx, keeper, msg)
+		case MsgAddFeeder:
+			return handleMsgAddFeeder(ctx, keeper, msg)
+		case MsgRemoveFeeder:
+			return handleMsgRemoveFeeder(ctx, keeper, msg)
+		default:
+			return nil, sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, fmt.Sprintf("Unrecognized pricefeed Msg type: %v", msg.Type()))
+		}
+	}
+}
+
+// Handle a message to post a price
+func handleMsgPostPrice(ctx sdk.Context, keeper Keeper, msg MsgPostPrice) (*sdk.Result, error) {
+	if !keeper.IsFeeder(ctx, msg.Feeder) {
+		return nil, sdkerrors.Wrap(types.ErrUnauthorizedFeeder, msg.Feeder.String())
+	}
+
+	keeper.SetPrice(ctx, Price{
+		Denom:  msg.Denom,
+		Unit:   msg.Unit,
+		Price:  msg.Price,
+		Feeder: msg.Feeder,
+		Height: ctx.BlockHeight(),
+	})
+	return &sdk.Result{}, nil
+}
+
+// Handle a message to authorise a feeder
+func handleMsgAddFeeder(ctx sdk.Context, keeper Keeper, msg MsgAddFeeder) (*sdk.Result, error) {
+	if err := checkAdmin(ctx, keeper, msg.Admin); err != nil {
+		return nil, err
+	}
+
+	keeper.SetFeeder(ctx, msg.Feeder)
+	return &sdk.Result{}, nil
+}
+
+// Handle a message to revoke a feeder
+func handleMsgRemoveFeeder(ctx sdk.Context, keeper Keeper, msg MsgRemoveFeeder) (*sdk.Result, error) {
+	if err := checkAdmin(ctx, keeper, msg.Admin); err != nil {
+		return nil, err
+	}
+	if !keeper.IsFeeder(ctx, msg.Feeder) {
+		return nil, sdkerrors.Wrap(types.ErrUnauthorizedFeeder, msg.Feeder.String())
+	}
+
+	keeper.DeleteFeeder(ctx, msg.Feeder)
+	return &sdk.Result{}, nil
+}
+
+// checkAdmin fails unless the signer is the admin of the module. Without an admin
+// nobody may change the feeders.
+func checkAdmin(ctx sdk.Context, keeper Keeper, signer sdk.AccAddress) error {
+	admin := keeper.Admin(ctx)
+	if admin.Empty() || !admin.Equals(signer) {
+		return sdkerrors.Wrap(types.ErrUnauthorizedAdmin, signer.String())
+	}
+	return nil
+}
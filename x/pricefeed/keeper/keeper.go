@@ -0,0 +1,136 @@
+package keeper
+
+import (
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/params"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/pricefeed/types"
+)
+
+// Keeper maintains the link to storage and exposes getter/setter methods for prices and feeders
+type Keeper struct {
+	storeKey sdk.StoreKey // Unexposed key to access store from sdk.Context
+
+	cdc *codec.Codec // The wire codec for binary encoding/decoding.
+
+	paramSpace params.Subspace // The subspace of the parameters of the module
+}
+
+// NewKeeper creates new instances of the pricefeed Keeper
+func NewKeeper(cdc *codec.Codec, storeKey sdk.StoreKey, paramSpace params.Subspace) Keeper {
+	return Keeper{
+		cdc:        cdc,
+		storeKey:   storeKey,
+		paramSpace: paramSpace.WithKeyTable(types.ParamKeyTable()),
+	}
+}
+
+// GetParams returns the parameters of the module
+func (k Keeper) GetParams(ctx sdk.Context) types.Params {
+	var params types.Params
+	k.paramSpace.GetParamSet(ctx, &params)
+	return params
+}
+
+// SetParams sets the parameters of the module
+func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
+	k.paramSpace.SetParamSet(ctx, &params)
+}
+
+// MaxPriceAge returns the number of blocks after which a posted price is stale
+func (k Keeper) MaxPriceAge(ctx sdk.Context) int64 {
+	var maxPriceAge int64
+	k.paramSpace.Get(ctx, types.KeyMaxPriceAge, &maxPriceAge)
+	return maxPriceAge
+}
+
+// Admin returns the address allowed to add and remove feeders, if any
+func (k Keeper) Admin(ctx sdk.Context) sdk.AccAddress {
+	var admin sdk.AccAddress
+	k.paramSpace.Get(ctx, types.KeyAdmin, &admin)
+	return admin
+}
+
+// GetPrice returns the latest price of a denomination in a unit
+func (k Keeper) GetPrice(ctx sdk.Context, denom, unit string) (types.Price, bool) {
+	store := ctx.KVStore(k.storeKey)
+
+	bz := store.Get(types.PriceKey(denom, unit))
+	if bz == nil {
+		return types.Price{}, false
+	}
+
+	var price types.Price
+	k.cdc.MustUnmarshalBinaryBare(bz, &price)
+	return price, true
+}
+
+// GetRate returns how many units one token of a denomination is worth, along with the
+// height the rate was posted at
+func (k Keeper) GetRate(ctx sdk.Context, denom, unit string) (sdk.Dec, int64, bool) {
+	price, ok := k.GetPrice(ctx, denom, unit)
+	if !ok {
+		return sdk.Dec{}, 0, false
+	}
+	return price.Price, price.Height, true
+}
+
+// SetPrice stores the latest price of a denomination in a unit
+func (k Keeper) SetPrice(ctx sdk.Context, price types.Price) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.PriceKey(price.Denom, price.Unit), k.cdc.MustMarshalBinaryBare(price))
+}
+
+// Get an iterator over all prices
+func (k Keeper) GetPricesIterator(ctx sdk.Context) sdk.Iterator {
+	store := ctx.KVStore(k.storeKey)
+	return sdk.KVStorePrefixIterator(store, []byte(types.PricePrefix))
+}
+
+// GetPrices returns all the latest prices
+func (k Keeper) GetPrices(ctx sdk.Context) []types.Price {
+	iterator := k.GetPricesIterator(ctx)
+	defer iterator.Close()
+
+	prices := []types.Price{}
+	for ; iterator.Valid(); iterator.Next() {
+		var price types.Price
+		k.cdc.MustUnmarshalBinaryBare(iterator.Value(), &price)
+		prices = append(prices, price)
+	}
+	return prices
+}
+
+// SetFeeder authorises an address to post prices
+func (k Keeper) SetFeeder(ctx sdk.Context, feeder sdk.AccAddress) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(append([]byte(types.FeederPrefix), feeder...), []byte{})
+}
+
+// DeleteFeeder revokes the authorisation of an address to post prices. The prices it
+// already posted are kept.
+func (k Keeper) DeleteFeeder(ctx sdk.Context, feeder sdk.AccAddress) {
+	store := ctx.KVStore(k.storeKey)
+	store.Delete(append([]byte(types.FeederPrefix), feeder...))
+}
+
+// IsFeeder returns whether an address is authorised to post prices
+func (k Keeper) IsFeeder(ctx sdk.Context, feeder sdk.AccAddress) bool {
+	store := ctx.KVStore(k.storeKey)
+	return store.Has(append([]byte(types.FeederPrefix), feeder...))
+}
+
+// GetFeeders returns all the authorised feeders
+func (k Keeper) GetFeeders(ctx sdk.Context) []sdk.AccAddress {
+	store := ctx.KVStore(k.storeKey)
+
+	iterator := sdk.KVStorePrefixIterator(store, []byte(types.FeederPrefix))
+	defer iterator.Close()
+
+	feeders := []sdk.AccAddress{}
+	for ; iterator.Valid(); iterator.Next() {
+		feeders = append(feeders, sdk.AccAddress(iterator.Key()[len(types.FeederPrefix):]))
+	}
+	return feeders
+}
@@ -0,0 +1,69 @@
+package keeper
+
+import (
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/pricefeed/types"
+)
+
+// query endpoints supported by the pricefeed Querier
+const (
+	QueryPrice   = "price"
+	QueryPrices  = "prices"
+	QueryFeeders = "feeders"
+)
+
+// NewQuerier is the module level router for state queries
+func NewQuerier(keeper Keeper) sdk.Querier {
+	return func(ctx sdk.Context, path []string, req abci.RequestQuery) (res []byte, err error) {
+		switch path[0] {
+		case QueryPrice:
+			return queryPrice(ctx, path[1:], req, keeper)
+		case QueryPrices:
+			return queryPrices(ctx, req, keeper)
+		case QueryFeeders:
+			return queryFeeders(ctx, req, keeper)
+		default:
+			return nil, sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "unknown pricefeed query endpoint")
+		}
+	}
+}
+
+func queryPrice(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper) ([]byte, error) {
+	if len(path) < 2 {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "price query must be price/denom/unit")
+	}
+
+	price, ok := keeper.GetPrice(ctx, path[0], path[1])
+	if !ok {
+		return nil, sdkerrors.Wrapf(types.ErrPriceDoesNotExist, "%s/%s", path[0], path[1])
+	}
+
+	res, err := codec.MarshalJSONIndent(keeper.cdc, price)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+
+	return res, nil
+}
+
+func queryPrices(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, error) {
+	res, err := codec.MarshalJSONIndent(keeper.cdc, types.QueryResPrices(keeper.GetPrices(ctx)))
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+
+	return res, nil
+}
+
+func queryFeeders(ctx sdk.Context, req abci.RequestQuery, keeper Keeper) ([]byte, error) {
+	res, err := codec.MarshalJSONIndent(keeper.cdc, types.QueryResFeeders(keeper.GetFeeders(ctx)))
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+
+	return res, nil
+}
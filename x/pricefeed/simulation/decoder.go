@@ -0,0 +1,32 @@
+package simulation
+
+import (
+	"bytes"
+	"fmt"
+
+	tmkv "github.com/tendermint/tendermint/libs/kv"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/pricefeed/types"
+)
+
+// DecodeStore unmarshals the KVPair's Value to the corresponding pricefeed type
+func DecodeStore(cdc *codec.Codec, kvA, kvB tmkv.Pair) string {
+	switch {
+	case bytes.HasPrefix(kvA.Key, []byte(types.PricePrefix)):
+		var priceA, priceB types.Price
+		cdc.MustUnmarshalBinaryBare(kvA.Value, &priceA)
+		cdc.MustUnmarshalBinaryBare(kvB.Value, &priceB)
+		return fmt.Sprintf("%v\n%v", priceA, priceB)
+
+	case bytes.HasPrefix(kvA.Key, []byte(types.FeederPrefix)):
+		// feeders are stored in the key, the value is empty
+		return fmt.Sprintf("%s\n%s", sdk.AccAddress(kvA.Key[len(types.FeederPrefix):]),
+			sdk.AccAddress(kvB.Key[len(types.FeederPrefix):]))
+
+	default:
+		panic(fmt.Sprintf("invalid %s key %s", types.ModuleName, kvA.Key))
+	}
+}
@@ -0,0 +1,59 @@
+package simulation
+
+// DONTCOVER
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/module"
+	"github.com/cosmos/cosmos-sdk/x/simulation"
+
+	"github.com/cosmos/sdk-tutorials/nameservice/x/pricefeed/types"
+)
+
+// Simulation parameter constants
+const (
+	MaxPriceAge = "max_price_age"
+	NumFeeders  = "num_feeders"
+)
+
+// GenMaxPriceAge randomized number of blocks a posted price stays usable
+func GenMaxPriceAge(r *rand.Rand) int64 {
+	return int64(simulation.RandIntBetween(r, 1, 200))
+}
+
+// GenNumFeeders randomized number of feeders authorised at genesis
+func GenNumFeeders(r *rand.Rand) int {
+	return r.Intn(4)
+}
+
+// RandomizedGenState generates a random GenesisState for pricefeed
+func RandomizedGenState(simState *module.SimulationState) {
+	var maxPriceAge int64
+	simState.AppParams.GetOrGenerate(
+		simState.Cdc, MaxPriceAge, &maxPriceAge, simState.Rand,
+		func(r *rand.Rand) { maxPriceAge = GenMaxPriceAge(r) },
+	)
+
+	var numFeeders int
+	simState.AppParams.GetOrGenerate(
+		simState.Cdc, NumFeeders, &numFeeders, simState.Rand,
+		func(r *rand.Rand) { numFeeders = GenNumFeeders(r) },
+	)
+
+	admin, _ := simulation.RandomAcc(simState.Rand, simState.Accounts)
+
+	feeders := make([]sdk.AccAddress, 0, numFeeders)
+	for i := 0; i < numFeeders; i++ {
+		feeder, _ := simulation.RandomAcc(simState.Rand, simState.Accounts)
+		feeders = append(feeders, feeder.Address)
+	}
+
+	pricefeedGenesis := types.NewGenesisState(types.NewParams(maxPriceAge, admin.Address), feeders, []types.Price{})
+
+	fmt.Printf("Selected randomly generated pricefeed state:\n%s\n", codec.MustMarshalJSONIndent(simState.Cdc, pricefeedGenesis))
+	simState.GenState[types.ModuleName] = simState.Cdc.MustMarshalJSON(pricefeedGenesis)
+}
@@ -0,0 +1,19 @@
+package types
+
+import (
+	"github.com/cosmos/cosmos-sdk/codec"
+)
+
+// ModuleCdc is the codec for the module
+var ModuleCdc = codec.New()
+
+func init() {
+	RegisterCodec(ModuleCdc)
+}
+
+// RegisterCodec registers concrete types on the Amino codec
+func RegisterCodec(cdc *codec.Codec) {
+	cdc.RegisterConcrete(MsgPostPrice{}, "pricefeed/PostPrice", nil)
+	cdc.RegisterConcrete(MsgAddFeeder{}, "pricefeed/AddFeeder", nil)
+	cdc.RegisterConcrete(MsgRemoveFeeder{}, "pricefeed/RemoveFeeder", nil)
+}
@@ -0,0 +1,11 @@
+package types
+
+import (
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+var (
+	ErrPriceDoesNotExist  = sdkerrors.Register(ModuleName, 1, "price does not exist")
+	ErrUnauthorizedFeeder = sdkerrors.Register(ModuleName, 2, "address is not an authorised feeder")
+	ErrUnauthorizedAdmin  = sdkerrors.Register(ModuleName, 3, "address is not the pricefeed admin")
+)
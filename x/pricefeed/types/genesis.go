@@ -0,0 +1,43 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+type GenesisState struct {
+	Params  Params           `json:"params"`
+	Feeders []sdk.AccAddress `json:"feeders"`
+	Prices  []Price          `json:"prices"`
+}
+
+func NewGenesisState(params Params, feeders []sdk.AccAddress, prices []Price) GenesisState {
+	return GenesisState{Params: params, Feeders: feeders, Prices: prices}
+}
+
+func ValidateGenesis(data GenesisState) error {
+	if err := data.Params.Validate(); err != nil {
+		return fmt.Errorf("invalid Params: Error: %s", err)
+	}
+	for _, feeder := range data.Feeders {
+		if feeder.Empty() {
+			return fmt.Errorf("invalid Feeder: Error: Missing Address")
+		}
+	}
+	for _, price := range data.Prices {
+		msg := NewMsgPostPrice(price.Denom, price.Unit, price.Price, price.Feeder)
+		if err := msg.ValidateBasic(); err != nil {
+			return fmt.Errorf("invalid Price: Denom: %s. Unit: %s. Error: %s", price.Denom, price.Unit, err)
+		}
+	}
+	return nil
+}
+
+func DefaultGenesisState() GenesisState {
+	return GenesisState{
+		Params:  DefaultParams(),
+		Feeders: []sdk.AccAddress{},
+		Prices:  []Price{},
+	}
+}
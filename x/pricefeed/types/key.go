@@ -0,0 +1,27 @@
+package types
+
+const (
+	// ModuleName is the name of the module
+	ModuleName = "pricefeed"
+
+	// StoreKey to be used when creating the KVStore
+	StoreKey = ModuleName
+
+	// RouterKey is the module name router key
+	RouterKey = ModuleName
+
+	// QuerierRoute to be used for querierer msgs
+	QuerierRoute = ModuleName
+)
+
+const (
+	// PricePrefix is the key prefix under which prices are stored
+	PricePrefix = "Price-"
+	// FeederPrefix is the key prefix under which authorised feeders are stored
+	FeederPrefix = "Feeder-"
+)
+
+// PriceKey returns the key of the price of a denomination in a unit
+func PriceKey(denom, unit string) []byte {
+	return []byte(PricePrefix + denom + "/" + unit)
+}
@@ -0,0 +1,137 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// MsgPostPrice defines a PostPrice message
+type MsgPostPrice struct {
+	Denom  string         `json:"denom"`
+	Unit   string         `json:"unit"`
+	Price  sdk.Dec        `json:"price"`
+	Feeder sdk.AccAddress `json:"feeder"`
+}
+
+// NewMsgPostPrice is a constructor function for MsgPostPrice
+func NewMsgPostPrice(denom string, unit string, price sdk.Dec, feeder sdk.AccAddress) MsgPostPrice {
+	return MsgPostPrice{
+		Denom:  denom,
+		Unit:   unit,
+		Price:  price,
+		Feeder: feeder,
+	}
+}
+
+// Route should return the name of the module
+func (msg MsgPostPrice) Route() string { return RouterKey }
+
+// Type should return the action
+func (msg MsgPostPrice) Type() string { return "post_price" }
+
+// ValidateBasic runs stateless checks on the message
+func (msg MsgPostPrice) ValidateBasic() error {
+	if msg.Feeder.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, msg.Feeder.String())
+	}
+	if err := sdk.ValidateDenom(msg.Denom); err != nil {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidCoins, err.Error())
+	}
+	if err := sdk.ValidateDenom(msg.Unit); err != nil {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidCoins, err.Error())
+	}
+	if msg.Price.IsNil() || !msg.Price.IsPositive() {
+		return sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "Price must be positive")
+	}
+	return nil
+}
+
+// GetSignBytes encodes the message for signing
+func (msg MsgPostPrice) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// GetSigners defines whose signature is required
+func (msg MsgPostPrice) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Feeder}
+}
+
+// MsgAddFeeder defines an AddFeeder message
+type MsgAddFeeder struct {
+	Feeder sdk.AccAddress `json:"feeder"`
+	Admin  sdk.AccAddress `json:"admin"`
+}
+
+// NewMsgAddFeeder is a constructor function for MsgAddFeeder
+func NewMsgAddFeeder(feeder sdk.AccAddress, admin sdk.AccAddress) MsgAddFeeder {
+	return MsgAddFeeder{
+		Feeder: feeder,
+		Admin:  admin,
+	}
+}
+
+// Route should return the name of the module
+func (msg MsgAddFeeder) Route() string { return RouterKey }
+
+// Type should return the action
+func (msg MsgAddFeeder) Type() string { return "add_feeder" }
+
+// ValidateBasic runs stateless checks on the message
+func (msg MsgAddFeeder) ValidateBasic() error {
+	return validateFeederChange(msg.Feeder, msg.Admin)
+}
+
+// GetSignBytes encodes the message for signing
+func (msg MsgAddFeeder) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// GetSigners defines whose signature is required
+func (msg MsgAddFeeder) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Admin}
+}
+
+// MsgRemoveFeeder defines a RemoveFeeder message
+type MsgRemoveFeeder struct {
+	Feeder sdk.AccAddress `json:"feeder"`
+	Admin  sdk.AccAddress `json:"admin"`
+}
+
+// NewMsgRemoveFeeder is a constructor function for MsgRemoveFeeder
+func NewMsgRemoveFeeder(feeder sdk.AccAddress, admin sdk.AccAddress) MsgRemoveFeeder {
+	return MsgRemoveFeeder{
+		Feeder: feeder,
+		Admin:  admin,
+	}
+}
+
+// Route should return the name of the module
+func (msg MsgRemoveFeeder) Route() string { return RouterKey }
+
+// Type should return the action
+func (msg MsgRemoveFeeder) Type() string { return "remove_feeder" }
+
+// ValidateBasic runs stateless checks on the message
+func (msg MsgRemoveFeeder) ValidateBasic() error {
+	return validateFeederChange(msg.Feeder, msg.Admin)
+}
+
+// GetSignBytes encodes the message for signing
+func (msg MsgRemoveFeeder) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// GetSigners defines whose signature is required
+func (msg MsgRemoveFeeder) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Admin}
+}
+
+func validateFeederChange(feeder, admin sdk.AccAddress) error {
+	if feeder.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, feeder.String())
+	}
+	if admin.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, admin.String())
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/params"
+)
+
+// DefaultParamspace is the subspace of the parameters of the module
+const DefaultParamspace = ModuleName
+
+// DefaultMaxPriceAge is the default number of blocks a posted price stays usable
+const DefaultMaxPriceAge int64 = 100
+
+// Parameter store keys
+var (
+	KeyMaxPriceAge = []byte("MaxPriceAge")
+	KeyAdmin       = []byte("Admin")
+)
+
+// Params are the parameters of the pricefeed module
+type Params struct {
+	// MaxPriceAge is the number of blocks after which a posted price is stale
+	MaxPriceAge int64 `json:"max_price_age" yaml:"max_price_age"`
+	// Admin is the address allowed to add and remove feeders. Without an admin the
+	// feeders can only be changed through the genesis file.
+	Admin sdk.AccAddress `json:"admin" yaml:"admin"`
+}
+
+// NewParams creates a new instance of Params
+func NewParams(maxPriceAge int64, admin sdk.AccAddress) Params {
+	return Params{MaxPriceAge: maxPriceAge, Admin: admin}
+}
+
+// DefaultParams returns the default parameters of the module
+func DefaultParams() Params {
+	return NewParams(DefaultMaxPriceAge, nil)
+}
+
+// ParamKeyTable returns the key table of the parameters of the module
+func ParamKeyTable() params.KeyTable {
+	return params.NewKeyTable().RegisterParamSet(&Params{})
+}
+
+// ParamSetPairs implements params.ParamSet
+func (p *Params) ParamSetPairs() params.ParamSetPairs {
+	return params.ParamSetPairs{
+		params.NewParamSetPair(KeyMaxPriceAge, &p.MaxPriceAge, validateMaxPriceAge),
+		params.NewParamSetPair(KeyAdmin, &p.Admin, validateAdmin),
+	}
+}
+
+// Validate checks the parameters
+func (p Params) Validate() error {
+	if err := validateMaxPriceAge(p.MaxPriceAge); err != nil {
+		return err
+	}
+	return validateAdmin(p.Admin)
+}
+
+// implement fmt.Stringer
+func (p Params) String() string {
+	return fmt.Sprintf(`Max Price Age: %d
+Admin:         %s`, p.MaxPriceAge, p.Admin)
+}
+
+func validateMaxPriceAge(i interface{}) error {
+	v, ok := i.(int64)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	if v <= 0 {
+		return fmt.Errorf("max price age must be positive: %d", v)
+	}
+	return nil
+}
+
+func validateAdmin(i interface{}) error {
+	if _, ok := i.(sdk.AccAddress); !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// QueryResFeeders Queries Result Payload for a feeders query
+type QueryResFeeders []sdk.AccAddress
+
+type QueryResPrices []Price
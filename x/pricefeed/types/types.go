@@ -0,0 +1,26 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Price is the value of one unit of a denomination expressed in a reference unit
+type Price struct {
+	Denom  string         `json:"denom"`
+	Unit   string         `json:"unit"`
+	Price  sdk.Dec        `json:"price"`
+	Feeder sdk.AccAddress `json:"feeder"`
+	Height int64          `json:"height"`
+}
+
+// implement fmt.Stringer
+func (p Price) String() string {
+	return strings.TrimSpace(fmt.Sprintf(`Denom: %s
+Unit: %s
+Price: %s
+Feeder: %s
+Height: %d`, p.Denom, p.Unit, p.Price, p.Feeder, p.Height))
+}